@@ -0,0 +1,67 @@
+package cmds
+
+import (
+	"context"
+	"errors"
+	"github.com/spf13/cobra"
+	"github.io/uberate/hcli/pkg/hctx"
+)
+
+var cachePruneAll bool
+
+func CacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "manage the LLM response cache",
+	}
+
+	cmd.AddCommand(
+		cachePruneCmd(),
+	)
+
+	return cmd
+}
+
+func cachePruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "remove the expired responses and shrink the cache to its max size",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return PruneCache(cmd.Context(), cachePruneAll)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&cachePruneAll, "all", "", false, "remove all the cached responses")
+
+	return cmd
+}
+
+func PruneCache(ctx context.Context, all bool) error {
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.LLMs.Cache.Disabled && !all {
+		return errors.New("the LLM cache is disabled, use '--all' to clear it anyway")
+	}
+
+	store, err := c.LLMs.Cache.Store()
+	if err != nil {
+		return err
+	}
+
+	res, err := store.Prune(all)
+	if err != nil {
+		return err
+	}
+
+	hctx.Println(ctx, "cache %s: %d removed, %.2f MB freed, %d kept, %.2f MB used", store.Dir(),
+		res.Removed, mb(res.Freed), res.Kept, mb(res.Size))
+	return nil
+}
+
+func mb(size int64) float64 {
+	return float64(size) / (1 << 20)
+}
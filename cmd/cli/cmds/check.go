@@ -0,0 +1,88 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/links"
+	"path/filepath"
+	"time"
+)
+
+var (
+	checkExternal bool
+	checkTimeout  time.Duration
+)
+
+func CheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "check the posts of the site",
+	}
+
+	cmd.AddCommand(
+		checkLinksCmd(),
+	)
+
+	return cmd
+}
+
+func checkLinksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "links [posts...]",
+		Short: "report the broken refs, relative links, images and anchors of posts, fail if any",
+		Long: "Check the links of the posts, or of the posts under the dirs. Without posts, all the posts of all " +
+			"the templates are checked. The refs and the site absolute links resolve from the Hugo site.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return CheckLinks(cmd.Context(), args, checkExternal, checkTimeout)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&checkExternal, "external", "", false, "check the http and https URLs with requests too")
+	cmd.Flags().DurationVarP(&checkTimeout, "timeout", "", links.DefaultTimeout, "the timeout of a request "+
+		"to an external URL")
+
+	return cmd
+}
+
+// CheckLinks checks the links of the posts at paths, the dirs are walked for Markdown files. It fails if any link
+// is broken.
+func CheckLinks(ctx context.Context, paths []string, external bool, timeout time.Duration) error {
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	if len(paths) == 0 {
+		if files, err = c.PostFiles(); err != nil {
+			return err
+		}
+	} else if files, err = markdownFiles(paths); err != nil {
+		return err
+	}
+
+	args := links.CheckArgs{Files: files, External: external, Timeout: timeout}
+	if c.Site != nil {
+		args.ContentDir = c.Site.ContentDir
+		args.StaticDir = filepath.Join(c.Site.Root, "static")
+	} else {
+		hctx.Debug(ctx, "no Hugo site, the site absolute links aren't checked")
+	}
+
+	broken, err := links.Check(ctx, args)
+	if err != nil {
+		return err
+	}
+	for _, b := range broken {
+		hctx.Println(ctx, "%s", b)
+	}
+
+	if len(broken) != 0 {
+		return fmt.Errorf("%d broken links in %d posts", len(broken), len(files))
+	}
+	hctx.Println(ctx, "no broken links in %d posts", len(files))
+	return nil
+}
@@ -1,9 +1,12 @@
 package cmds
 
 import (
+	"context"
+	"errors"
 	"github.com/spf13/cobra"
 	"github.io/uberate/hcli/pkg/config"
 	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/hugo"
 	"github.io/uberate/hcli/pkg/yamlutil"
 )
 
@@ -14,6 +17,7 @@ func ConfigCmd() *cobra.Command {
 
 	configCmd.AddCommand(
 		demoCmd(),
+		siteCmd(),
 	)
 
 	return configCmd
@@ -34,3 +38,54 @@ func demoCmd() *cobra.Command {
 
 	return cmd
 }
+
+func siteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "site",
+		Short: "show the Hugo site found by hcli",
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			c, err := readConfig(ctx)
+			if err != nil {
+				return err
+			}
+
+			if c.Site == nil {
+				if c.SiteErr != nil {
+					return c.SiteErr
+				}
+				return hugo.ErrSiteNotFound
+			}
+
+			hctx.Println(ctx, "root: %s", c.Site.Root)
+			hctx.Println(ctx, "config: %s", c.Site.ConfigPath)
+			hctx.Println(ctx, "contentDir: %s", c.Site.ContentDir)
+			hctx.Println(ctx, "archetypeDir: %s", c.Site.ArchetypeDir)
+			hctx.Println(ctx, "defaultContentLanguage: %s", c.Site.DefaultContentLanguage)
+			for _, l := range c.Site.Languages {
+				hctx.Println(ctx, "language: %s (weight %d)", l.Code, l.Weight)
+			}
+			hctx.Println(ctx, "taxonomies: %v", c.Site.TaxonomyKeys())
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// readConfig reads the config of the run. An auto-detected Hugo site which can't be loaded is reported, hcli then
+// runs without a site.
+func readConfig(ctx context.Context) (config.CliConfig, error) {
+	c, err := config.ReadConfig(hctx.GetConfigPath(ctx))
+	if err != nil || c.SiteErr == nil {
+		return c, err
+	}
+
+	if errors.Is(c.SiteErr, hugo.ErrSiteNotFound) {
+		hctx.Debug(ctx, "%v", c.SiteErr)
+	} else {
+		hctx.Warn(ctx, "ignore the Hugo site: %v", c.SiteErr)
+	}
+	return c, nil
+}
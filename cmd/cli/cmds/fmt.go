@@ -0,0 +1,89 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.io/uberate/hcli/pkg/diff"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/typography"
+	"os"
+)
+
+var (
+	fmtCheck bool
+	fmtDiff  bool
+)
+
+func FmtCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fmt [posts...]",
+		Short: "format the spacing and the punctuation of the CJK text mixed with Latin text in posts",
+		Long: "Format the bodies of the posts, or of the posts under the dirs, in place. Without posts, all the " +
+			"posts of all the templates are formatted. The front matter, the code, the URLs and the shortcodes " +
+			"are kept.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fmtCheck {
+				cmd.SilenceUsage = true
+			}
+			return Fmt(cmd.Context(), args, fmtCheck, fmtDiff)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&fmtCheck, "check", "", false, "don't write the posts, list the unformatted ones and "+
+		"fail if any")
+	cmd.Flags().BoolVarP(&fmtDiff, "diff", "d", false, "don't write the posts, print the diffs of the formatting")
+
+	return cmd
+}
+
+// Fmt formats the posts at paths, the dirs are walked for Markdown files. check and showDiff keep the files as
+// they are, check fails if any post is unformatted.
+func Fmt(ctx context.Context, paths []string, check, showDiff bool) error {
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	if len(paths) == 0 {
+		if files, err = c.PostFiles(); err != nil {
+			return err
+		}
+	} else if files, err = markdownFiles(paths); err != nil {
+		return err
+	}
+
+	unformatted := 0
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		formatted, err := typography.Format(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f, err)
+		}
+		if string(formatted) == string(data) {
+			continue
+		}
+		unformatted++
+
+		switch {
+		case showDiff:
+			hctx.Write(ctx, diff.Unified(f+".orig", f, string(data), string(formatted)))
+		case check:
+			hctx.Println(ctx, "%s", f)
+		default:
+			if err = os.WriteFile(f, formatted, 0644); err != nil {
+				return err
+			}
+			hctx.Println(ctx, "formatted %s", f)
+		}
+	}
+
+	if check && unformatted != 0 {
+		return fmt.Errorf("%d of %d posts are not formatted, run 'hcli fmt'", unformatted, len(files))
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmds
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
@@ -9,9 +10,34 @@ import (
 	"github.io/uberate/hcli/pkg/hctx"
 	"github.io/uberate/hcli/pkg/llms"
 	"github.io/uberate/hcli/pkg/poster"
+	"github.io/uberate/hcli/pkg/prompts"
+	"github.io/uberate/hcli/pkg/template"
+	"io"
+	"strconv"
+	"strings"
 )
 
-var picTemplateName string
+const (
+	rendererLLM   = "llm"
+	rendererLocal = "local"
+)
+
+var (
+	picTemplateName string
+	picRenderer     string
+	picCandidates   int
+	picVarySummary  bool
+	picChoose       bool
+	picPick         int
+)
+
+// PicOptions are the generation options of 'hcli gen pic'.
+type PicOptions struct {
+	Renderer string
+	// Candidates more than 1 writes the posters as candidates to choose from.
+	Candidates  int
+	VarySummary bool
+}
 
 func genPic() *cobra.Command {
 	cmd := &cobra.Command{
@@ -22,22 +48,50 @@ func genPic() *cobra.Command {
 			ctx := cmd.Context()
 			fileName := args[0]
 
-			return GeneratePictureFromTemplate(ctx, fileName, picTemplateName)
+			if cmd.Flags().Changed("pick") {
+				if picPick < 1 {
+					return fmt.Errorf("invalid candidate %d, candidates are numbered from 1", picPick)
+				}
+				return ChoosePicture(ctx, cmd.InOrStdin(), fileName, picTemplateName, picPick)
+			}
+			if picChoose {
+				return ChoosePicture(ctx, cmd.InOrStdin(), fileName, picTemplateName, 0)
+			}
+
+			return GeneratePictureFromTemplate(ctx, fileName, picTemplateName, PicOptions{
+				Renderer:    picRenderer,
+				Candidates:  picCandidates,
+				VarySummary: picVarySummary,
+			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&picTemplateName, "template-name", "n", "", "the template name for picture generation")
+	cmd.Flags().StringVar(&picRenderer, "renderer", rendererLLM, "the poster renderer, support: llm, local. "+
+		"local draws a procedural cover with the title and tags, no model is needed")
+	cmd.Flags().IntVar(&picCandidates, "candidates", 1, "generate N posters concurrently as "+
+		"'feature.candidate-N' files, then pick one with '--choose' or '--pick N'")
+	cmd.Flags().BoolVar(&picVarySummary, "vary-summary", false, "generate a summary per candidate instead of "+
+		"drawing all the candidates from one summary")
+	cmd.Flags().BoolVar(&picChoose, "choose", false, "list the poster candidates and ask which one to promote")
+	cmd.Flags().IntVar(&picPick, "pick", 0, "promote the candidate N to the poster and delete the others")
+	cmd.MarkFlagsMutuallyExclusive("choose", "pick")
 
 	return cmd
 }
 
-func GeneratePictureFromTemplate(ctx context.Context, fileName, templateName string) error {
-	c, err := config.ReadConfig(hctx.GetConfigPath(ctx))
-	if err != nil {
-		return err
+func GeneratePictureFromTemplate(ctx context.Context, fileName, templateName string, opts PicOptions) error {
+	if opts.Renderer != rendererLLM && opts.Renderer != rendererLocal {
+		return fmt.Errorf("invalid renderer '%s', support: llm, local", opts.Renderer)
+	}
+	if opts.Candidates < 1 {
+		return fmt.Errorf("invalid candidates %d, need at least 1", opts.Candidates)
+	}
+	if opts.Candidates > 1 && opts.Renderer == rendererLocal {
+		return errors.New("the local renderer always draws the same poster, candidates need the llm renderer")
 	}
 
-	llmTools, err := llms.NewLLMWithAutoEnv(c.LLMs)
+	c, err := readConfig(ctx)
 	if err != nil {
 		return err
 	}
@@ -51,22 +105,142 @@ func GeneratePictureFromTemplate(ctx context.Context, fileName, templateName str
 		return errors.New(fmt.Sprintf("template %s has no dir, pic can't generate", templateName))
 	}
 
-	generateResult, err := poster.GeneratePoster(ctx, poster.GeneratePosterArgs{
+	if opts.Candidates > 1 {
+		return generatePictureCandidates(ctx, c, tp, fileName, opts)
+	}
+
+	var generateResult *poster.GeneratePosterResult
+	if opts.Renderer == rendererLocal {
+		generateResult, err = poster.RenderLocalPoster(ctx, poster.GeneratePosterArgs{
+			TP:       &tp,
+			FileName: fileName,
+		})
+	} else {
+		var llmTools llms.LLMTools
+		if llmTools, err = newLLMTools(ctx, c); err != nil {
+			return err
+		}
+		var reg *prompts.Registry
+		if reg, err = c.PromptRegistry(); err != nil {
+			return err
+		}
+		generateResult, err = poster.GeneratePoster(ctx, poster.GeneratePosterArgs{
+			TP:       &tp,
+			LLMTools: llmTools,
+			FileName: fileName,
+			Prompts:  reg,
+		})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if generateResult.Summary != "" {
+		if err = tp.WritePicSummary(fileName, generateResult.Summary); err != nil {
+			return err
+		}
+	}
+	if err = tp.WritePoster(fileName, generateResult.Pic); err != nil {
+		return err
+	}
+	for _, thumb := range generateResult.Thumbnails {
+		if err = tp.WriteResource(fileName, tp.ThumbnailFileName(thumb.Width), thumb.Data); err != nil {
+			return err
+		}
+	}
+
+	return updatePosterFrontMatter(ctx, c, tp, fileName)
+}
+
+// updatePosterFrontMatter sets the poster keys on the files of the post, the alt texts are the titles of the files.
+// The summary the poster was drawn from is a prompt for the image model, not a description for the readers.
+func updatePosterFrontMatter(ctx context.Context, c config.CliConfig, tp template.Template, fileName string) error {
+	updated, err := tp.UpdatePosterFrontMatter(fileName, siteLangs(c)...)
+	for _, p := range updated {
+		hctx.Println(ctx, "front matter updated: %s", p)
+	}
+
+	return err
+}
+
+func generatePictureCandidates(ctx context.Context, c config.CliConfig, tp template.Template, fileName string,
+	opts PicOptions) error {
+
+	llmTools, err := newLLMTools(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	reg, err := c.PromptRegistry()
+	if err != nil {
+		return err
+	}
+
+	results, err := poster.GeneratePosterCandidates(ctx, poster.GeneratePosterArgs{
 		TP:       &tp,
 		LLMTools: llmTools,
 		FileName: fileName,
-	})
+		Prompts:  reg,
+	}, opts.Candidates, opts.VarySummary)
+	if err != nil {
+		return err
+	}
+
+	if err = poster.WriteCandidates(&tp, fileName, results); err != nil {
+		return err
+	}
 
+	hctx.Println(ctx, "%d candidates written, choose one with 'hcli gen pic %s --choose' or "+
+		"'--pick N'", len(results), fileName)
+	return nil
+}
+
+// ChoosePicture promotes a poster candidate of the post, index 0 lists the candidates and reads the choice from in.
+func ChoosePicture(ctx context.Context, in io.Reader, fileName, templateName string, index int) error {
+	c, err := readConfig(ctx)
 	if err != nil {
 		return err
 	}
 
-	if err = tp.WritePicSummary(fileName, generateResult.Summary); err != nil {
+	tp, err := c.SearchTemplate(templateName)
+	if err != nil {
 		return err
 	}
-	if err = tp.WritePoster(fileName, generateResult.Pic); err != nil {
+
+	if index == 0 {
+		candidates, err := poster.ListCandidates(&tp, fileName)
+		if err != nil {
+			return err
+		}
+		if len(candidates) == 0 {
+			return fmt.Errorf("no poster candidates of %s, generate them with '--candidates'", fileName)
+		}
+
+		for _, candidate := range candidates {
+			hctx.Println(ctx, "[%d] %s\n    %s", candidate.Index, candidate.Path, firstLine(candidate.Summary))
+		}
+		hctx.Println(ctx, "choose a candidate [1-%d]:", len(candidates))
+
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if index, err = strconv.Atoi(strings.TrimSpace(line)); err != nil {
+			return fmt.Errorf("invalid choice '%s'", strings.TrimSpace(line))
+		}
+	}
+
+	chosen, err := poster.ChooseCandidate(&tp, fileName, index)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	hctx.Println(ctx, "candidate %d promoted to %s", chosen.Index, tp.ResourcePath(fileName, tp.PosterFileName()))
+	return updatePosterFrontMatter(ctx, c, tp, fileName)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
 }
@@ -0,0 +1,45 @@
+package cmds
+
+import (
+	"github.com/spf13/cobra"
+	"io"
+	"testing"
+)
+
+func TestGenPicChooseFlags(t *testing.T) {
+	for _, c := range []struct {
+		args   []string
+		choose bool
+		pick   int
+	}{
+		{[]string{"post", "--pick", "2"}, false, 2},
+		{[]string{"--pick=3", "post"}, false, 3},
+		{[]string{"--choose", "post"}, true, 0},
+		{[]string{"post", "--choose"}, true, 0},
+	} {
+		picChoose, picPick = false, 0
+		cmd := genPic()
+		var got []string
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			got = args
+			return nil
+		}
+		cmd.SetArgs(c.args)
+		cmd.SetOut(io.Discard)
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v: %v", c.args, err)
+		}
+		if len(got) != 1 || got[0] != "post" || picChoose != c.choose || picPick != c.pick {
+			t.Fatalf("%v: unexpected args %v, choose %v, pick %d", c.args, got, picChoose, picPick)
+		}
+	}
+
+	cmd := genPic()
+	cmd.SetArgs([]string{"post", "--choose", "--pick", "1"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("Expected an error for '--choose' with '--pick'")
+	}
+}
@@ -3,9 +3,11 @@ package cmds
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/spf13/cobra"
 	"github.io/uberate/hcli/pkg/config"
 	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/slug"
 	"github.io/uberate/hcli/pkg/template"
 	"path/filepath"
 	"strings"
@@ -16,6 +18,8 @@ var tags []string
 var categories []string
 var title string
 var customArgs []string
+var lang string
+var slugMode string
 
 func genPost() *cobra.Command {
 	cmd := &cobra.Command{
@@ -24,9 +28,12 @@ func genPost() *cobra.Command {
 		Short:   "generate post by specify template define",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			fileName := args[0]
-			if strings.HasSuffix(fileName, ".md") {
-				fileName = strings.TrimSuffix(fileName, ".md")
+			fileName := ""
+			if len(args) != 0 {
+				fileName = strings.TrimSuffix(args[0], ".md")
+			}
+			if fileName == "" && title == "" {
+				return errors.New("give a file name or '--title'")
 			}
 			if title == "" {
 				title = getFileNameWithoutExtension(fileName)
@@ -41,6 +48,9 @@ func genPost() *cobra.Command {
 	cmd.Flags().StringSliceVarP(&categories, "categories", "k", nil, "categories")
 	cmd.Flags().StringVarP(&title, "title", "", "", "title")
 	cmd.Flags().StringSliceVarP(&customArgs, "custom-args", "a", nil, "custom args")
+	cmd.Flags().StringVarP(&lang, "lang", "", "", "the post language, writes index.<lang>.md or <name>.<lang>.md")
+	cmd.Flags().StringVarP(&slugMode, "slug-mode", "", slug.ModePinyin, "how the file name is derived from the "+
+		"title without a file name, support: pinyin, llm")
 
 	return cmd
 }
@@ -48,7 +58,7 @@ func genPost() *cobra.Command {
 func GenerateNewPost(ctx context.Context, name string, title string) error {
 	// read config first
 	hctx.Debug(ctx, "config path: %s", hctx.GetConfigPath(ctx))
-	c, err := config.ReadConfig(hctx.GetConfigPath(ctx))
+	c, err := readConfig(ctx)
 	if err != nil {
 		return errors.New("read config error: " + err.Error())
 	}
@@ -60,6 +70,11 @@ func GenerateNewPost(ctx context.Context, name string, title string) error {
 
 	hctx.Debug(ctx, "%+v", tp)
 
+	// the arguments are checked before the slug is derived, an invalid one costs no LLM call.
+	if err = validateSite(c, lang, taxonomyKeys(tp)...); err != nil {
+		return err
+	}
+
 	args := map[string]string{}
 
 	for _, item := range customArgs {
@@ -71,14 +86,55 @@ func GenerateNewPost(ctx context.Context, name string, title string) error {
 		args[values[0]] = strings.Join(values[1:], "=")
 	}
 
+	if name == "" {
+		if name, err = deriveSlug(ctx, c, tp, title); err != nil {
+			return err
+		}
+		hctx.Println(ctx, "file name: %s", name)
+	}
+
 	return template.RenderToFile(ctx, &tp, name, template.RenderOption{
 		Title:            title,
 		AppendTags:       tags,
 		AppendCategories: categories,
 		CustomArgs:       args,
+		Lang:             lang,
 	})
 }
 
+// deriveSlug returns the file name of the post from its title, made unique in the dir of the template.
+func deriveSlug(ctx context.Context, c config.CliConfig, tp template.Template, title string) (string, error) {
+	var s string
+	switch slugMode {
+	case slug.ModePinyin:
+		s = slug.Pinyin(title)
+	case slug.ModeLLM:
+		llmTools, err := newLLMTools(ctx, c)
+		if err != nil {
+			return "", err
+		}
+		reg, err := c.PromptRegistry()
+		if err != nil {
+			return "", err
+		}
+		if s, err = slug.Translate(ctx, llmTools, reg, title); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("invalid slug mode '%s', support: pinyin, llm", slugMode)
+	}
+
+	if s == "" {
+		return "", fmt.Errorf("no file name can be derived from the title '%s', give one", title)
+	}
+
+	unique := slug.Unique(s, tp.PostExists)
+	if unique != s {
+		hctx.Warn(ctx, "%s is taken in %s, use %s", s, tp.Dir, unique)
+	}
+	return unique, nil
+}
+
 func getFileNameWithoutExtension(filePath string) string {
 	filename := filepath.Base(filePath)
 	fileSuffix := filepath.Ext(filename)
@@ -86,3 +142,28 @@ func getFileNameWithoutExtension(filePath string) string {
 
 	return fileNameWithoutSuffix
 }
+
+// taxonomyKeys returns the taxonomy front matter keys the post is generated with.
+func taxonomyKeys(tp template.Template) []string {
+	var keys []string
+	if len(tp.Tags)+len(tags) != 0 {
+		keys = append(keys, "tags")
+	}
+	if len(tp.Categories)+len(categories) != 0 {
+		keys = append(keys, "categories")
+	}
+	return keys
+}
+
+// validateSite checks the language and the taxonomy keys against the Hugo site, nothing is checked out of a site.
+func validateSite(c config.CliConfig, lang string, taxonomies ...string) error {
+	if c.Site == nil {
+		return nil
+	}
+
+	if lang != "" && !c.Site.HasLanguage(lang) {
+		return fmt.Errorf("language %s is not defined in %s", lang, c.Site.ConfigPath)
+	}
+
+	return c.Site.ValidateTaxonomies(taxonomies...)
+}
@@ -0,0 +1,132 @@
+package cmds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/lint"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	lintFormatText = "text"
+	lintFormatJSON = "json"
+)
+
+var lintFormat string
+
+func LintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [posts...]",
+		Short: "check posts with the Markdown and Hugo rules of their templates, fail if any issue is found",
+		Long: "Check the posts, or the posts under the dirs, with the rules of the templates holding them. " +
+			"Without posts, all the posts of all the templates are checked.\n\nRules:\n" + lintRules(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// the issues are the output, the usage would bury them in CI logs
+			cmd.SilenceUsage = true
+			return Lint(cmd.Context(), args, lintFormat)
+		},
+	}
+
+	cmd.Flags().StringVarP(&lintFormat, "format", "", lintFormatText, "the output format, support: text, json")
+
+	return cmd
+}
+
+func lintRules() string {
+	var builder strings.Builder
+	for _, r := range lint.Rules {
+		builder.WriteString(fmt.Sprintf("  %-24s %s\n", r.Name, r.Description))
+	}
+	return builder.String()
+}
+
+// Lint checks the posts at paths, the dirs are walked for Markdown files. It fails if any issue is found.
+func Lint(ctx context.Context, paths []string, format string) error {
+	if format != lintFormatText && format != lintFormatJSON {
+		return fmt.Errorf("invalid format '%s', support: text, json", format)
+	}
+
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	if len(paths) == 0 {
+		if files, err = c.PostFiles(); err != nil {
+			return err
+		}
+	} else if files, err = markdownFiles(paths); err != nil {
+		return err
+	}
+
+	issues := []lint.Issue{}
+	failed := map[string]bool{}
+	for _, f := range files {
+		tp, ok := c.TemplateOf(f)
+		if !ok {
+			hctx.Debug(ctx, "%s isn't in the dir of a template, lint with the default rules", f)
+		}
+
+		res, err := lint.File(f, tp.Lint)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f, err)
+		}
+		for _, issue := range res {
+			failed[issue.File] = true
+		}
+		issues = append(issues, res...)
+	}
+
+	if format == lintFormatJSON {
+		data, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			return err
+		}
+		hctx.Println(ctx, "%s", data)
+	} else {
+		for _, issue := range issues {
+			hctx.Println(ctx, "%s", issue)
+		}
+	}
+
+	if len(issues) != 0 {
+		return fmt.Errorf("%d issues in %d of %d posts", len(issues), len(failed), len(files))
+	}
+	if format == lintFormatText {
+		hctx.Println(ctx, "no issues in %d posts", len(files))
+	}
+	return nil
+}
+
+// markdownFiles returns the files of paths, and the Markdown files under the dirs of paths.
+func markdownFiles(paths []string) ([]string, error) {
+	var res []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			res = append(res, p)
+			continue
+		}
+
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".md") {
+				res = append(res, path)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,18 @@
+package cmds
+
+import (
+	"context"
+	"github.io/uberate/hcli/pkg/config"
+	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/usage"
+)
+
+// newLLMTools creates the LLM tools of the config, the usage of the calls is recorded to the run.
+func newLLMTools(ctx context.Context, c config.CliConfig) (llms.LLMTools, error) {
+	tools, err := llms.NewLLMWithAutoEnv(c.LLMs)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage.Meter(tools, usage.RecorderFrom(ctx)), nil
+}
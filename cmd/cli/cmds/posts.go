@@ -0,0 +1,451 @@
+package cmds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.io/uberate/hcli/pkg/config"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/posts"
+	"time"
+)
+
+var (
+	postsTemplateName string
+	postsDryRun       bool
+	postsAt           string
+	postsGit          bool
+)
+
+func PostsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "posts",
+		Short: "reorganize the posts of a template without breaking their links",
+	}
+
+	cmd.AddCommand(
+		postsMvCmd(),
+		postsBundleCmd(),
+		postsUnbundleCmd(),
+		postsPublishCmd(),
+		postsUnpublishCmd(),
+		postsScheduleCmd(),
+		postsDraftsCmd(),
+		postsTouchCmd(),
+	)
+
+	return cmd
+}
+
+func postsMvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mv <old> <new>",
+		Short: "move a post, rewrite the links to it and add its old URL to the aliases",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MovePost(cmd.Context(), postsTemplateName, args[0], args[1], postsDryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(&postsTemplateName, "template-name", "n", "", "the template name of the post")
+	cmd.Flags().BoolVarP(&postsDryRun, "dry-run", "", false, "print the changes without making them")
+
+	return cmd
+}
+
+func postsBundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle <name>",
+		Short: "turn the post name.md into the page bundle name/index.md, so 'hcli gen pic' can add its poster",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return BundlePost(cmd.Context(), postsTemplateName, args[0], false, postsDryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(&postsTemplateName, "template-name", "n", "", "the template name of the post")
+	cmd.Flags().BoolVarP(&postsDryRun, "dry-run", "", false, "print the changes without making them")
+
+	return cmd
+}
+
+func postsUnbundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unbundle <name>",
+		Short: "turn the page bundle name/index.md without resources into the post name.md",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return BundlePost(cmd.Context(), postsTemplateName, args[0], true, postsDryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(&postsTemplateName, "template-name", "n", "", "the template name of the post")
+	cmd.Flags().BoolVarP(&postsDryRun, "dry-run", "", false, "print the changes without making them")
+
+	return cmd
+}
+
+func postsPublishCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "publish <post>",
+		Short: "publish a post and its language files now: draft is false, date and lastmod are now",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return PublishPost(cmd.Context(), postsTemplateName, args[0], posts.StatusPublished, "")
+		},
+	}
+
+	cmd.Flags().StringVarP(&postsTemplateName, "template-name", "n", "", "the template name of the post")
+
+	return cmd
+}
+
+func postsUnpublishCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unpublish <post>",
+		Short: "turn a post and its language files back into drafts",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return PublishPost(cmd.Context(), postsTemplateName, args[0], posts.StatusDraft, "")
+		},
+	}
+
+	cmd.Flags().StringVarP(&postsTemplateName, "template-name", "n", "", "the template name of the post")
+
+	return cmd
+}
+
+func postsScheduleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule <post> --at <time>",
+		Short: "schedule a post and its language files, Hugo builds them from the given time on",
+		Long: `Schedule a post: draft is false, and date and publishDate are the given time.
+A time without offset, '2026-11-01T09:00', is in the timeZone of the Hugo site, or the local one.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return PublishPost(cmd.Context(), postsTemplateName, args[0], posts.StatusScheduled, postsAt)
+		},
+	}
+
+	cmd.Flags().StringVarP(&postsTemplateName, "template-name", "n", "", "the template name of the post")
+	cmd.Flags().StringVarP(&postsAt, "at", "", "", "the publish time, e.g. 2026-11-01T09:00")
+	_ = cmd.MarkFlagRequired("at")
+
+	return cmd
+}
+
+func postsDraftsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drafts",
+		Short: "list the drafts and the scheduled posts with their age, the oldest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListDrafts(cmd.Context(), postsTemplateName)
+		},
+	}
+
+	cmd.Flags().StringVarP(&postsTemplateName, "template-name", "n", "",
+		"only list the posts of the template, all the templates if empty")
+
+	return cmd
+}
+
+func postsTouchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "touch [post]",
+		Short: "set the lastmod of a post and its language files to now, or to their last content commit with --git",
+		Long: `Set the lastmod of a post and its language files to now.
+
+With --git, the lastmod of each file is the time of the last commit which changed its content, the commits only
+editing the front matter are skipped. Without a post, all the posts of the template, or of all the templates, are
+touched. The files not committed yet are left as is.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fileName := ""
+			if len(args) != 0 {
+				fileName = args[0]
+			}
+			return TouchPosts(cmd.Context(), postsTemplateName, fileName, postsGit, postsDryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(&postsTemplateName, "template-name", "n", "", "the template name of the post")
+	cmd.Flags().BoolVarP(&postsGit, "git", "", false, "read the lastmod from the git history")
+	cmd.Flags().BoolVarP(&postsDryRun, "dry-run", "", false, "print the changes without making them")
+
+	return cmd
+}
+
+// TouchPosts updates the lastmod of the post and its language files, or with fromGit of all the posts when fileName
+// is empty.
+func TouchPosts(ctx context.Context, templateName, fileName string, fromGit, dryRun bool) error {
+	if fileName == "" && !fromGit {
+		return errors.New("give a post, or '--git' to touch all the posts")
+	}
+
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+	loc, err := siteLocation(c)
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	switch {
+	case fileName != "":
+		tp, err := c.SearchTemplate(templateName)
+		if err != nil {
+			return err
+		}
+		paths = langFiles(c, tp, fileName)
+	case templateName != "":
+		tp, err := c.SearchTemplate(templateName)
+		if err != nil {
+			return err
+		}
+		if paths, err = tp.PostFiles(); err != nil {
+			return err
+		}
+	default:
+		if paths, err = c.PostFiles(); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().In(loc)
+	changed := 0
+	for _, p := range paths {
+		lastmod := now
+		if fromGit {
+			t, ok, err := posts.GitLastmod(p)
+			if err != nil {
+				return fmt.Errorf("%s: %w", p, err)
+			}
+			if !ok {
+				hctx.Debug(ctx, "%s: not committed yet, skipped", p)
+				continue
+			}
+			lastmod = t.In(loc)
+		}
+
+		doc, err := frontmatter.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		if fromGit && doc.Format == frontmatter.FormatNone {
+			continue
+		}
+
+		ok, err := posts.Touch(doc, lastmod)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		if !ok {
+			continue
+		}
+
+		changed++
+		hctx.Println(ctx, "%s: lastmod %s", p, lastmod.Format(time.RFC3339))
+		if !dryRun {
+			if err = doc.WriteFile(p); err != nil {
+				return err
+			}
+		}
+	}
+
+	if dryRun {
+		hctx.Println(ctx, "dry run, nothing changed")
+	} else if fromGit {
+		hctx.Println(ctx, "%d of %d posts updated", changed, len(paths))
+	}
+	return nil
+}
+
+// PublishPost sets the post and its language files to status: published now, a draft, or scheduled at at.
+func PublishPost(ctx context.Context, templateName, fileName, status, at string) error {
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+	tp, err := c.SearchTemplate(templateName)
+	if err != nil {
+		return err
+	}
+	loc, err := siteLocation(c)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().In(loc)
+	var atTime time.Time
+	if status == posts.StatusScheduled {
+		var ok bool
+		if atTime, ok = frontmatter.ParseTimeIn(at, loc); !ok {
+			return fmt.Errorf("invalid time %s, expected e.g. 2026-11-01T09:00", at)
+		}
+	}
+
+	for _, p := range langFiles(c, tp, fileName) {
+		doc, err := frontmatter.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		switch status {
+		case posts.StatusPublished:
+			err = posts.Publish(doc, now)
+		case posts.StatusDraft:
+			err = posts.Unpublish(doc)
+		case posts.StatusScheduled:
+			err = posts.Schedule(doc, atTime, now)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+
+		if err = doc.WriteFile(p); err != nil {
+			return err
+		}
+		hctx.Println(ctx, "%s: %s", p, status)
+	}
+
+	return nil
+}
+
+// ListDrafts prints the drafts and the scheduled posts of the template, or of all the templates.
+func ListDrafts(ctx context.Context, templateName string) error {
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+	loc, err := siteLocation(c)
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	if templateName != "" {
+		tp, err := c.SearchTemplate(templateName)
+		if err != nil {
+			return err
+		}
+		files, err = tp.PostFiles()
+		if err != nil {
+			return err
+		}
+	} else if files, err = c.PostFiles(); err != nil {
+		return err
+	}
+
+	drafts, err := posts.Drafts(files, time.Now().In(loc))
+	if err != nil {
+		return err
+	}
+	if len(drafts) == 0 {
+		hctx.Println(ctx, "no drafts")
+		return nil
+	}
+
+	hctx.Println(ctx, "%-8s %-10s %-40s %s", "AGE", "STATUS", "PATH", "TITLE")
+	for _, d := range drafts {
+		hctx.Println(ctx, "%-8s %-10s %-40s %s", posts.FormatAge(d.Age), d.Status, d.Path, d.Title)
+	}
+
+	return nil
+}
+
+// siteLocation returns the time zone of the Hugo site, the local one out of a site.
+func siteLocation(c config.CliConfig) (*time.Location, error) {
+	if c.Site == nil {
+		return time.Local, nil
+	}
+	return c.Site.Location()
+}
+
+// BundlePost converts the post name of the template to a page bundle, or back to a single file if unbundle. The
+// relative links of all the posts are rewritten.
+func BundlePost(ctx context.Context, templateName, name string, unbundle, dryRun bool) error {
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+	tp, err := c.SearchTemplate(templateName)
+	if err != nil {
+		return err
+	}
+	files, err := c.PostFiles()
+	if err != nil {
+		return err
+	}
+
+	args := posts.BundleArgs{Template: tp, Name: name, Posts: files}
+	if c.Site != nil {
+		args.ContentDir = c.Site.ContentDir
+	}
+
+	var plan *posts.Plan
+	if unbundle {
+		plan, err = posts.PlanUnbundle(args)
+	} else {
+		plan, err = posts.PlanBundle(args)
+	}
+	if err != nil {
+		return err
+	}
+	return applyPlan(ctx, plan, dryRun)
+}
+
+// MovePost moves the post from of the template to to, the links of all the posts are rewritten.
+func MovePost(ctx context.Context, templateName, from, to string, dryRun bool) error {
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+	tp, err := c.SearchTemplate(templateName)
+	if err != nil {
+		return err
+	}
+	files, err := c.PostFiles()
+	if err != nil {
+		return err
+	}
+
+	args := posts.MoveArgs{Template: tp, From: from, To: to, Posts: files, Site: c.Site}
+	if c.Site != nil {
+		args.ContentDir = c.Site.ContentDir
+	} else {
+		hctx.Warn(ctx, "no Hugo site, no alias is added and the content relative refs aren't rewritten")
+	}
+
+	plan, err := posts.PlanMove(args)
+	if err != nil {
+		return err
+	}
+	return applyPlan(ctx, plan, dryRun)
+}
+
+// applyPlan prints the changes of the plan, and makes them unless dryRun.
+func applyPlan(ctx context.Context, plan *posts.Plan, dryRun bool) error {
+	for _, r := range plan.Renames {
+		hctx.Println(ctx, "move %s -> %s", r.From, r.To)
+	}
+	for _, dir := range plan.Removes {
+		hctx.Println(ctx, "remove %s", dir)
+	}
+	for _, r := range plan.Rewrites {
+		hctx.Println(ctx, "%s:%d:%d: %s -> %s", r.File, r.Line, r.Col, r.From, r.To)
+	}
+	for _, a := range plan.Aliases {
+		hctx.Println(ctx, "%s: alias %s", a.File, a.Alias)
+	}
+
+	if dryRun {
+		hctx.Println(ctx, "dry run, nothing changed")
+		return nil
+	}
+	return plan.Apply()
+}
@@ -0,0 +1,150 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/prompts"
+	"os"
+	"strings"
+)
+
+var (
+	promptFile string
+	promptLang string
+	promptVars []string
+)
+
+func PromptsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prompts",
+		Short: "inspect the prompts of the LLM features",
+	}
+
+	cmd.AddCommand(
+		listPromptsCmd(),
+		showPromptCmd(),
+		renderPromptCmd(),
+	)
+
+	return cmd
+}
+
+func listPromptsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "list the prompts with their versions and sources",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListPrompts(cmd.Context())
+		},
+	}
+}
+
+func showPromptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "show a prompt template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ShowPrompt(cmd.Context(), args[0])
+		},
+	}
+}
+
+func renderPromptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render <name>",
+		Short: "render a prompt with the variables of a post",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RenderPrompt(cmd.Context(), args[0], promptFile, promptLang, promptVars)
+		},
+	}
+
+	cmd.Flags().StringVarP(&promptFile, "file", "f", "", "the post file the variables are read from")
+	cmd.Flags().StringVarP(&promptLang, "lang", "", "", "the lang variable")
+	cmd.Flags().StringArrayVarP(&promptVars, "var", "", nil, "extra variables in k=v format, they win over the post")
+
+	return cmd
+}
+
+func promptRegistry(ctx context.Context) (*prompts.Registry, error) {
+	c, err := readConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.PromptRegistry()
+}
+
+func ListPrompts(ctx context.Context) error {
+	reg, err := promptRegistry(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range reg.List() {
+		version := p.Version
+		if version == "" {
+			version = "-"
+		}
+		hctx.Println(ctx, "%-20s v%-4s %-10s %s", p.Name, version, p.Source, p.Description)
+	}
+
+	return nil
+}
+
+func ShowPrompt(ctx context.Context, name string) error {
+	reg, err := promptRegistry(ctx)
+	if err != nil {
+		return err
+	}
+
+	p, err := reg.Get(name)
+	if err != nil {
+		return err
+	}
+
+	hctx.Println(ctx, "name: %s", p.Name)
+	hctx.Println(ctx, "version: %s", p.Version)
+	hctx.Println(ctx, "source: %s", p.Source)
+	hctx.Println(ctx, "description: %s", p.Description)
+	hctx.Println(ctx, "variables: title, tags, categories, lang, excerpt, body")
+	hctx.Println(ctx, "---")
+	hctx.Println(ctx, "%s", p.Template)
+
+	return nil
+}
+
+func RenderPrompt(ctx context.Context, name, file, lang string, vars []string) error {
+	reg, err := promptRegistry(ctx)
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+	if file != "" {
+		if content, err = os.ReadFile(file); err != nil {
+			return err
+		}
+	}
+
+	values := prompts.PostVars(content, lang)
+	for _, v := range vars {
+		k, value, ok := strings.Cut(v, "=")
+		if !ok || k == "" {
+			return fmt.Errorf("wrong variable format, need k=v: %s", v)
+		}
+		values[k] = value
+	}
+
+	res, err := reg.Render(name, values)
+	if err != nil {
+		return err
+	}
+
+	hctx.Println(ctx, "%s", res)
+	return nil
+}
@@ -0,0 +1,206 @@
+package cmds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.io/uberate/hcli/pkg/config"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/prompts"
+	"github.io/uberate/hcli/pkg/seo"
+	"github.io/uberate/hcli/pkg/template"
+	"os"
+	"strings"
+)
+
+var (
+	seoTemplateName      string
+	seoAll               bool
+	seoOverwrite         bool
+	seoCheck             bool
+	seoDescriptionLength int
+	seoSummaryLength     int
+)
+
+// SEOOptions are the options of 'hcli seo'.
+type SEOOptions struct {
+	All       bool
+	Overwrite bool
+	// Check only reports the posts missing metadata, it fails if any does.
+	Check             bool
+	DescriptionLength int
+	SummaryLength     int
+}
+
+func SEOCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "seo [post]",
+		Short: "generate the description, summary and keywords of posts",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fileName := ""
+			if len(args) != 0 {
+				fileName = args[0]
+			}
+
+			return SEO(cmd.Context(), fileName, seoTemplateName, SEOOptions{
+				All:               seoAll,
+				Overwrite:         seoOverwrite,
+				Check:             seoCheck,
+				DescriptionLength: seoDescriptionLength,
+				SummaryLength:     seoSummaryLength,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&seoTemplateName, "template-name", "n", "", "the template name of the post")
+	cmd.Flags().BoolVarP(&seoAll, "all", "", false, "all the posts of all the templates")
+	cmd.Flags().BoolVarP(&seoOverwrite, "overwrite", "", false, "replace the existing non-empty values")
+	cmd.Flags().BoolVarP(&seoCheck, "check", "", false, "only report the posts missing the metadata, fail if any")
+	cmd.Flags().IntVarP(&seoDescriptionLength, "description-length", "", seo.DefaultDescriptionLength,
+		"the max characters of the description")
+	cmd.Flags().IntVarP(&seoSummaryLength, "summary-length", "", seo.DefaultSummaryLength,
+		"the max characters of the summary")
+
+	return cmd
+}
+
+// SEO generates the metadata of the post and its language files, or of all the posts.
+func SEO(ctx context.Context, fileName, templateName string, opts SEOOptions) error {
+	if (fileName == "") == !opts.All {
+		return errors.New("give either a post or '--all'")
+	}
+
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	if opts.All {
+		if paths, err = c.PostFiles(); err != nil {
+			return err
+		}
+	} else {
+		tp, err := c.SearchTemplate(templateName)
+		if err != nil {
+			return err
+		}
+		paths = langFiles(c, tp, fileName)
+	}
+
+	if opts.Check {
+		return checkSEO(ctx, paths)
+	}
+
+	llmTools, err := newLLMTools(ctx, c)
+	if err != nil {
+		return err
+	}
+	reg, err := c.PromptRegistry()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, p := range paths {
+		if err = seoPost(ctx, c, llmTools, reg, p, opts); err != nil {
+			hctx.Warn(ctx, "%s: %v", p, err)
+			errs = append(errs, fmt.Errorf("%s: %w", p, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// langFiles returns the post and its existing language files, of the template and of the site languages.
+func langFiles(c config.CliConfig, tp template.Template, fileName string) []string {
+	return tp.LangFiles(fileName, siteLangs(c)...)
+}
+
+// siteLangs returns the language codes of the Hugo site, none without a site.
+func siteLangs(c config.CliConfig) []string {
+	var res []string
+	if c.Site != nil {
+		for _, l := range c.Site.Languages {
+			res = append(res, l.Code)
+		}
+	}
+	return res
+}
+
+func checkSEO(ctx context.Context, paths []string) error {
+	incomplete := 0
+	for _, p := range paths {
+		doc, err := frontmatter.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		if doc.Format == frontmatter.FormatNone {
+			continue
+		}
+
+		if missing := seo.Missing(doc); len(missing) != 0 {
+			incomplete++
+			hctx.Println(ctx, "%s: missing %s", p, strings.Join(missing, ", "))
+		}
+	}
+
+	if incomplete != 0 {
+		return fmt.Errorf("%d of %d posts miss seo metadata", incomplete, len(paths))
+	}
+	hctx.Println(ctx, "all %d posts have seo metadata", len(paths))
+	return nil
+}
+
+func seoPost(ctx context.Context, c config.CliConfig, tools llms.LLMTools, reg *prompts.Registry, p string,
+	opts SEOOptions) error {
+
+	content, err := os.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	doc, err := frontmatter.Parse(content)
+	if err != nil {
+		return err
+	}
+	if doc.Format == frontmatter.FormatNone {
+		hctx.Debug(ctx, "%s has no front matter, skipped", p)
+		return nil
+	}
+	if !opts.Overwrite && len(seo.Missing(doc)) == 0 {
+		hctx.Debug(ctx, "%s has seo metadata, skipped", p)
+		return nil
+	}
+
+	lang := ""
+	if c.Site != nil {
+		lang = c.Site.FileLang(p)
+	}
+
+	m, err := seo.Generate(ctx, seo.GenerateArgs{
+		LLMTools:          tools,
+		Prompts:           reg,
+		Content:           content,
+		Lang:              lang,
+		DescriptionLength: opts.DescriptionLength,
+		SummaryLength:     opts.SummaryLength,
+	})
+	if err != nil {
+		return err
+	}
+
+	set, err := seo.Apply(doc, *m, opts.Overwrite)
+	if err != nil || len(set) == 0 {
+		return err
+	}
+	if err = doc.WriteFile(p); err != nil {
+		return err
+	}
+
+	hctx.Println(ctx, "%s: %s set", p, strings.Join(set, ", "))
+	return nil
+}
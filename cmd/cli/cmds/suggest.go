@@ -0,0 +1,190 @@
+package cmds
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/suggest"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	suggestTemplateName string
+	suggestMaxNew       int
+	suggestYes          bool
+)
+
+func SuggestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "suggest the metadata of a post with the LLM",
+	}
+
+	cmd.AddCommand(
+		suggestTagsCmd(),
+	)
+
+	return cmd
+}
+
+func suggestTagsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tags",
+		Short: "suggest tags and categories from the vocabulary of the site",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return SuggestTags(cmd.Context(), cmd.InOrStdin(), args[0], suggestTemplateName, suggestMaxNew,
+				suggestYes)
+		},
+	}
+
+	cmd.Flags().StringVarP(&suggestTemplateName, "template-name", "n", "", "the template name of the post")
+	cmd.Flags().IntVarP(&suggestMaxNew, "max-new", "", 2, "the max terms out of the site vocabulary, tags and "+
+		"categories together")
+	cmd.Flags().BoolVarP(&suggestYes, "yes", "y", false, "accept all the suggestions without asking")
+
+	return cmd
+}
+
+// SuggestTags suggests the tags and categories of the post and writes the accepted ones to its front matter, the
+// choices are read from in unless yes.
+func SuggestTags(ctx context.Context, in io.Reader, fileName, templateName string, maxNew int, yes bool) error {
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	tp, err := c.SearchTemplate(templateName)
+	if err != nil {
+		return err
+	}
+
+	content, err := tp.ReadIfExists(fileName)
+	if err != nil {
+		return err
+	}
+
+	paths, err := c.PostFiles()
+	if err != nil {
+		return err
+	}
+	vocabulary, err := suggest.CollectVocabulary(paths)
+	if err != nil {
+		return err
+	}
+	hctx.Debug(ctx, "vocabulary of %d posts: %d tags, %d categories", len(paths), len(vocabulary.Tags),
+		len(vocabulary.Categories))
+
+	llmTools, err := newLLMTools(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	reg, err := c.PromptRegistry()
+	if err != nil {
+		return err
+	}
+
+	s, err := suggest.SuggestTags(ctx, suggest.SuggestTagsArgs{
+		LLMTools:   llmTools,
+		Prompts:    reg,
+		Content:    content,
+		Vocabulary: vocabulary,
+		MaxNew:     maxNew,
+	})
+	if err != nil {
+		return err
+	}
+
+	choices := append(append([]suggest.Choice{}, s.Tags...), s.Categories...)
+	if len(choices) == 0 {
+		hctx.Println(ctx, "no new tags or categories suggested")
+		return nil
+	}
+
+	for i, choice := range choices {
+		if i == 0 && len(s.Tags) != 0 {
+			hctx.Println(ctx, "tags:")
+		}
+		if i == len(s.Tags) {
+			hctx.Println(ctx, "categories:")
+		}
+		mark := ""
+		if choice.New {
+			mark = " (new)"
+		}
+		hctx.Println(ctx, "  [%d] %s%s", i+1, choice.Name, mark)
+	}
+
+	accepted := make([]bool, len(choices))
+	for i := range accepted {
+		accepted[i] = true
+	}
+	if !yes {
+		hctx.Println(ctx, "accept [all, none or numbers like 1,3]:")
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if accepted, err = parseAccepted(strings.TrimSpace(line), len(choices)); err != nil {
+			return err
+		}
+	}
+
+	var tags, categories []string
+	for i, choice := range choices {
+		if !accepted[i] {
+			continue
+		}
+		if i < len(s.Tags) {
+			tags = append(tags, choice.Name)
+		} else {
+			categories = append(categories, choice.Name)
+		}
+	}
+
+	doc, err := frontmatter.Parse(content)
+	if err != nil {
+		return err
+	}
+	changed, err := suggest.Apply(doc, tags, categories)
+	if err != nil || !changed {
+		return err
+	}
+
+	p := tp.GetFilePath(fileName)
+	if err = doc.WriteFile(p); err != nil {
+		return err
+	}
+	hctx.Println(ctx, "front matter updated: %s", p)
+	return nil
+}
+
+// parseAccepted parses the accepted choices of n, 'all' or empty accepts all of them.
+func parseAccepted(line string, n int) ([]bool, error) {
+	res := make([]bool, n)
+	switch strings.ToLower(line) {
+	case "", "all", "a", "y", "yes":
+		for i := range res {
+			res[i] = true
+		}
+		return res, nil
+	case "none", "n", "no":
+		return res, nil
+	}
+
+	for _, item := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' }) {
+		i, err := strconv.Atoi(item)
+		if err != nil || i < 1 || i > n {
+			return nil, fmt.Errorf("invalid choice '%s', choose 1-%d", item, n)
+		}
+		res[i-1] = true
+	}
+	return res, nil
+}
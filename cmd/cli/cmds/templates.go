@@ -0,0 +1,86 @@
+package cmds
+
+import (
+	"context"
+	"errors"
+	"github.com/spf13/cobra"
+	"github.io/uberate/hcli/pkg/archetype"
+	"github.io/uberate/hcli/pkg/config"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/template"
+)
+
+var archetypeDir string
+var archetypeDryRun bool
+
+func TemplatesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "templates",
+		Aliases: []string{"tpl"},
+		Short:   "manage the post templates of the hcli config",
+	}
+
+	cmd.AddCommand(
+		importArchetypesCmd(),
+	)
+
+	return cmd
+}
+
+func importArchetypesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-archetypes",
+		Short: "import the Hugo archetypes as templates",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ImportArchetypes(cmd.Context(), archetypeDir, archetypeDryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(&archetypeDir, "dir", "", "", "the archetypes dir, empty means the archetypes dir of "+
+		"the Hugo site")
+	cmd.Flags().BoolVarP(&archetypeDryRun, "dry-run", "", false, "only show the imported templates")
+
+	return cmd
+}
+
+func ImportArchetypes(ctx context.Context, dir string, dryRun bool) error {
+	configPath := hctx.GetConfigPath(ctx)
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if dir == "" {
+		if c.Site == nil {
+			return errors.New("no Hugo site found, use --dir to specify the archetypes dir")
+		}
+		dir = c.Site.ArchetypeDir
+	}
+
+	archetypes, err := archetype.ImportDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var templates []template.Template
+	for _, a := range archetypes {
+		for _, w := range a.Warnings {
+			hctx.Warn(ctx, "%s:%d: %s", a.Path, w.Line, w.Message)
+		}
+
+		if _, err = c.SearchTemplate(a.Template.Name); err == nil {
+			hctx.Warn(ctx, "template %s already exists, skip %s", a.Template.Name, a.Path)
+			continue
+		}
+
+		hctx.Println(ctx, "import %s as template %s", a.Path, a.Template.Name)
+		templates = append(templates, a.Template)
+	}
+
+	if dryRun || len(templates) == 0 {
+		return nil
+	}
+
+	return config.AppendTemplates(configPath, templates...)
+}
@@ -0,0 +1,89 @@
+package cmds
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/translate"
+	"os"
+)
+
+var translateTemplateName string
+var translateFrom string
+var translateTo string
+var translateStream bool
+
+func TranslateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "translate",
+		Short: "translate a post to the sibling language file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return TranslatePost(cmd.Context(), args[0], translateTemplateName, translateFrom, translateTo,
+				translateStream)
+		},
+	}
+
+	cmd.Flags().StringVarP(&translateTemplateName, "template-name", "n", "", "the template name of the post")
+	cmd.Flags().StringVarP(&translateFrom, "from", "", "", "the language of the source post, empty means the post "+
+		"without language suffix")
+	cmd.Flags().StringVarP(&translateTo, "to", "", "", "the target language")
+	cmd.Flags().BoolVarP(&translateStream, "stream", "", true, "print the translated body as it arrives")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func TranslatePost(ctx context.Context, fileName, templateName, from, to string, stream bool) error {
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = validateSite(c, to); err != nil {
+		return err
+	}
+
+	llmTools, err := newLLMTools(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	tp, err := c.SearchTemplate(templateName)
+	if err != nil {
+		return err
+	}
+
+	reg, err := c.PromptRegistry()
+	if err != nil {
+		return err
+	}
+
+	args := translate.TranslatePostArgs{
+		TP:       &tp,
+		LLMTools: llmTools,
+		FileName: fileName,
+		From:     from,
+		To:       to,
+		Prompts:  reg,
+	}
+	if stream {
+		args.OnChunk = func(chunk string) {
+			hctx.Write(ctx, chunk)
+		}
+	}
+
+	res, err := translate.TranslatePost(ctx, args)
+	if stream {
+		hctx.Write(ctx, "\n")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = tp.WriteLangIfNotExists(fileName, to, res.Target); err != nil {
+		return err
+	}
+
+	return os.WriteFile(tp.GetLangFilePath(fileName, from), res.Source, 0644)
+}
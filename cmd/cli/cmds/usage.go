@@ -0,0 +1,98 @@
+package cmds
+
+import (
+	"context"
+	"errors"
+	"github.com/spf13/cobra"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/usage"
+	"time"
+)
+
+var usageSince string
+
+func UsageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "show the LLM usage recorded in the ledger",
+	}
+
+	cmd.AddCommand(
+		usageReportCmd(),
+	)
+
+	return cmd
+}
+
+func usageReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "sum the ledger by month, provider and model",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return UsageReport(cmd.Context(), usageSince)
+		},
+	}
+
+	cmd.Flags().StringVarP(&usageSince, "since", "", "30d", "the start of the report, like 30d, 12h or 2006-01-02")
+
+	return cmd
+}
+
+func UsageReport(ctx context.Context, sinceValue string) error {
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.Usage.Ledger == "" {
+		return errors.New("no usage ledger configured, set 'Usage.Ledger' in the config")
+	}
+
+	since, err := usage.ParseSince(sinceValue, time.Now())
+	if err != nil {
+		return err
+	}
+
+	records, err := usage.ReadLedger(c.Usage.Ledger, since)
+	if err != nil {
+		return err
+	}
+
+	hctx.Println(ctx, "usage since %s", since.Format(time.DateTime))
+	hctx.Println(ctx, "%-8s %-10s %-24s %6s %12s %12s %6s %10s",
+		"MONTH", "PROVIDER", "MODEL", "CALLS", "PROMPT", "COMPLETION", "IMAGES", "COST")
+	for _, row := range usage.Report(records) {
+		hctx.Println(ctx, "%-8s %-10s %-24s %6d %12d %12d %6d %10.4f", row.Month, row.Provider, row.Model,
+			row.Calls, row.PromptTokens, row.CompletionTokens, row.Images, row.Cost)
+	}
+
+	total := usage.Sum(records)
+	hctx.Println(ctx, "%-8s %-10s %-24s %6d %12d %12d %6d %10.4f", "TOTAL", "", "",
+		total.Calls, total.PromptTokens, total.CompletionTokens, total.Images, total.Cost)
+
+	return nil
+}
+
+// FlushUsage prints the usage of the run and appends it to the ledger of the config.
+func FlushUsage(ctx context.Context, r *usage.Recorder) error {
+	records := r.Records()
+	if len(records) == 0 {
+		return nil
+	}
+
+	c, err := readConfig(ctx)
+	if err != nil {
+		return err
+	}
+	c.Usage.PriceRecords(records)
+
+	total := usage.Sum(records)
+	hctx.Println(ctx, "usage: %d calls (%d cached), %d prompt tokens, %d completion tokens, %d images, cost %.4f",
+		total.Calls, total.Cached, total.PromptTokens, total.CompletionTokens, total.Images, total.Cost)
+
+	if c.Usage.Ledger == "" {
+		return nil
+	}
+	return usage.Append(c.Usage.Ledger, records)
+}
@@ -3,7 +3,10 @@ package main
 import "os"
 
 func main() {
-	if err := RootCmd().Execute(); err != nil {
+	err := RootCmd().Execute()
+	flushUsage()
+
+	if err != nil {
 		os.Exit(1)
 	}
 }
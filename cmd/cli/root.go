@@ -1,15 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"github.com/spf13/cobra"
 	"github.io/uberate/hcli/cmd/cli/cmds"
 	"github.io/uberate/hcli/pkg/config"
 	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/llms"
 	"github.io/uberate/hcli/pkg/output"
+	"github.io/uberate/hcli/pkg/usage"
+	"strings"
 )
 
 var configPath string
 var logLevel string
+var noCache bool
+var refreshCache bool
+
+// runCtx is the context of the command run, nil until a command runs.
+var runCtx context.Context
 
 func RootCmd() *cobra.Command {
 
@@ -24,10 +34,24 @@ func RootCmd() *cobra.Command {
 		VersionCmd(),
 		cmds.ConfigCmd(),
 		cmds.GenCmd(),
+		cmds.TranslateCmd(),
+		cmds.TemplatesCmd(),
+		cmds.PromptsCmd(),
+		cmds.UsageCmd(),
+		cmds.CacheCmd(),
+		cmds.SuggestCmd(),
+		cmds.SEOCmd(),
+		cmds.LintCmd(),
+		cmds.FmtCmd(),
+		cmds.CheckCmd(),
+		cmds.PostsCmd(),
 	)
 	cmd.PersistentFlags().StringVarP(&logLevel, "log-level", "l", "info", "log level, support: debug, info, warn, error, fatal")
 	cmd.PersistentFlags().StringVarP(&configPath, "config", "c", ".hcli_config.yaml", "config file path,"+
 		" if empty, use '.hcli_config.yaml''")
+	cmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "neither read nor write the LLM response cache")
+	cmd.PersistentFlags().BoolVar(&refreshCache, "refresh", false, "call the LLMs even if the responses are cached, "+
+		"and cache the new responses")
 
 	cmd.PersistentPreRunE = preRun
 
@@ -44,10 +68,34 @@ func preRun(cmd *cobra.Command, args []string) error {
 
 	ctx = hctx.SetOutputter(ctx, output.NewOutputter(l, cmd.OutOrStdout()))
 	ctx = hctx.SetConfigPath(ctx, configPath)
+
+	switch {
+	case noCache && refreshCache:
+		return errors.New("--no-cache and --refresh can't be used together")
+	case noCache:
+		ctx = llms.WithCacheMode(ctx, llms.CacheOff)
+	case refreshCache:
+		ctx = llms.WithCacheMode(ctx, llms.CacheRefresh)
+	}
+
+	ctx = usage.WithRecorder(ctx, usage.NewRecorder(strings.TrimSpace(cmd.CommandPath())))
+
+	runCtx = ctx
 	cmd.SetContext(ctx)
 	return nil
 }
 
+// flushUsage reports the LLM usage of the run, the run may have failed.
+func flushUsage() {
+	if runCtx == nil {
+		return
+	}
+
+	if err := cmds.FlushUsage(runCtx, usage.RecorderFrom(runCtx)); err != nil {
+		hctx.Warn(runCtx, "record usage failed: %v", err)
+	}
+}
+
 func readConfig() (config.CliConfig, error) {
 	if configPath == "" {
 		configPath = "./.hcli_config.yaml"
@@ -3,8 +3,13 @@ module github.io/uberate/hcli
 go 1.23.9
 
 require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/HugoSmits86/nativewebp v0.9.3
+	github.com/mozillazg/go-pinyin v0.21.0
 	github.com/spf13/cobra v1.9.1
 	github.com/volcengine/volcengine-go-sdk v1.1.30
+	golang.org/x/image v0.24.0
+	golang.org/x/text v0.22.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -0,0 +1,66 @@
+package golden
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden images in testdata")
+
+// Image fails the test if img differs from the golden image 'testdata/<name>.png', with '-update' it writes img as
+// the golden image instead.
+func Image(t testing.TB, name string, img image.Image) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".png")
+
+	if *update {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatalf("encode failed: %v", err)
+		}
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatalf("mkdir failed: %v", err)
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatalf("update golden failed: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden failed, run the tests with -update to create it: %v", err)
+	}
+	golden, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode golden failed: %v", err)
+	}
+
+	if golden.Bounds() != img.Bounds() {
+		t.Fatalf("%s: size %v, golden %v", name, img.Bounds(), golden.Bounds())
+	}
+	b := golden.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if color.RGBAModel.Convert(golden.At(x, y)) != color.RGBAModel.Convert(img.At(x, y)) {
+				t.Fatalf("%s: pixel (%d, %d) differs from the golden image", name, x, y)
+			}
+		}
+	}
+}
+
+// Encoded is Image for the encoded image data.
+func Encoded(t testing.TB, name string, data []byte) {
+	t.Helper()
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode output failed: %v", err)
+	}
+	Image(t, name, img)
+}
@@ -0,0 +1,5 @@
+// Package golden compares the images rendered in tests with the golden images of the testdata dir.
+//
+// Run the tests of a package with '-update' to write its golden images, then review the images before committing
+// them. The comparison is per pixel in RGBA, so the encoding of the golden file doesn't matter.
+package golden
@@ -0,0 +1,239 @@
+package archetype
+
+import (
+	"fmt"
+	"github.io/uberate/hcli/pkg/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultKind = "default"
+
+// ImportDir imports all the archetypes in dir, sorted by kind.
+func ImportDir(dir string) ([]Archetype, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Archetype
+	for _, entry := range entries {
+		p := filepath.Join(dir, entry.Name())
+
+		if !entry.IsDir() {
+			if filepath.Ext(entry.Name()) != ".md" {
+				continue
+			}
+			a, err := Import(p, strings.TrimSuffix(entry.Name(), ".md"), false)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, a)
+			continue
+		}
+
+		index := filepath.Join(p, "index.md")
+		if !template.FileExists(index) {
+			continue
+		}
+		a, err := Import(index, entry.Name(), true)
+		if err != nil {
+			return nil, err
+		}
+		a.Warnings = append(a.Warnings, bundleResourceWarnings(p)...)
+		res = append(res, a)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Template.Name < res[j].Template.Name
+	})
+
+	return res, nil
+}
+
+// Import reads the archetype at path as the template of kind, bundle archetypes need a dir.
+func Import(path, kind string, needDir bool) (Archetype, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Archetype{}, err
+	}
+
+	text, tags, categories, warnings := Translate(string(data))
+
+	dir := kind
+	if kind == defaultKind {
+		dir = ""
+	}
+
+	return Archetype{
+		Path: path,
+		Template: template.Template{
+			Name:            kind,
+			Categories:      categories,
+			Tags:            tags,
+			Template:        text,
+			Dir:             dir,
+			ContentRelative: true,
+			NeedDir:         needDir,
+		},
+		Warnings: warnings,
+	}, nil
+}
+
+var (
+	actionPattern   = regexp.MustCompile(`(?s)\{\{(-\s)?(.*?)(\s-)?\}\}`)
+	taxonomyPattern = regexp.MustCompile(`(?m)^(tags|categories)(\s*[=:]\s*)\[([^\]{}]*)\][ \t]*$`)
+	stringPattern   = regexp.MustCompile("\"(?:[^\"\\\\]|\\\\.)*\"|`[^`]*`")
+	tokenPattern    = regexp.MustCompile(`[$.]?[A-Za-z_]\w*(\.[A-Za-z_]\w*)*`)
+	formatPattern   = regexp.MustCompile(`\.createAt\.Format\s+(STR\d+)`)
+)
+
+var fieldReplacements = []struct {
+	pattern *regexp.Regexp
+	repl    string
+}{
+	{regexp.MustCompile(`\.Site\.Language\.Lang\b`), ".lang"},
+	{regexp.MustCompile(`\.Language\.Lang\b`), ".lang"},
+	{regexp.MustCompile(`\.Lang\b`), ".lang"},
+	{regexp.MustCompile(`\.File\.(ContentBaseName|BaseFileName|TranslationBaseName)\b`), ".title"},
+	{regexp.MustCompile(`\.(Name|Title)\b`), ".title"},
+	{regexp.MustCompile(`\.(Date|PublishDate|Lastmod)\b`), ".createAt"},
+	{regexp.MustCompile(`(^|[^\w.$])now\b`), "${1}.createAt"},
+}
+
+var allowedTokens = map[string]bool{
+	".title": true, ".createAt": true, ".lang": true, ".tags": true, ".categories": true,
+	"replace": true, "title": true, "lower": true, "upper": true, "humanize": true,
+	"true": true, "false": true,
+}
+
+// Translate converts the Hugo template syntax of an archetype to the hcli template syntax. It returns the static
+// tags and categories moved out of the front matter, and the warnings of the untranslated actions.
+func Translate(content string) (string, []string, []string, []Warning) {
+	var tags, categories []string
+	content = taxonomyPattern.ReplaceAllStringFunc(content, func(s string) string {
+		m := taxonomyPattern.FindStringSubmatch(s)
+		if m[1] == "tags" {
+			tags = parseList(m[3])
+		} else {
+			categories = parseList(m[3])
+		}
+		return m[1] + m[2] + "{{ ." + m[1] + " }}"
+	})
+
+	var warnings []Warning
+	var builder strings.Builder
+	last := 0
+	for _, loc := range actionPattern.FindAllStringSubmatchIndex(content, -1) {
+		builder.WriteString(content[last:loc[0]])
+		last = loc[1]
+
+		whole := content[loc[0]:loc[1]]
+		expr := content[loc[4]:loc[5]]
+		line := strings.Count(content[:loc[0]], "\n") + 1
+
+		// shortcodes and comments are no template actions
+		if strings.HasPrefix(expr, "<") || strings.HasPrefix(expr, "%") {
+			builder.WriteString(literal(whole))
+			continue
+		}
+		if strings.HasPrefix(expr, "/*") {
+			builder.WriteString(whole)
+			continue
+		}
+
+		translated, notes, ok := translateAction(expr)
+		if !ok {
+			warnings = append(warnings, Warning{
+				Line:    line,
+				Message: fmt.Sprintf("can't translate '%s', kept as text", whole),
+			})
+			builder.WriteString(literal(whole))
+			continue
+		}
+
+		for _, note := range notes {
+			warnings = append(warnings, Warning{Line: line, Message: note})
+		}
+
+		left, right := "{{ ", " }}"
+		if loc[2] >= 0 {
+			left = "{{- "
+		}
+		if loc[6] >= 0 {
+			right = " -}}"
+		}
+		builder.WriteString(left + translated + right)
+	}
+	builder.WriteString(content[last:])
+
+	return builder.String(), tags, categories, warnings
+}
+
+// translateAction maps the Hugo variables of expr, it fails if expr uses anything else.
+func translateAction(expr string) (string, []string, bool) {
+	// string literals are swapped out, so the replacements only see code
+	var literals []string
+	code := stringPattern.ReplaceAllStringFunc(strings.TrimSpace(expr), func(s string) string {
+		literals = append(literals, s)
+		return fmt.Sprintf("STR%d", len(literals)-1)
+	})
+
+	for _, r := range fieldReplacements {
+		code = r.pattern.ReplaceAllString(code, r.repl)
+	}
+
+	var notes []string
+	code = formatPattern.ReplaceAllStringFunc(code, func(s string) string {
+		m := formatPattern.FindStringSubmatch(s)
+		index, _ := strconv.Atoi(strings.TrimPrefix(m[1], "STR"))
+		notes = append(notes, fmt.Sprintf("date format %s is dropped, createAt is always RFC3339", literals[index]))
+		return ".createAt"
+	})
+
+	for _, token := range tokenPattern.FindAllString(code, -1) {
+		if !allowedTokens[token] && !strings.HasPrefix(token, "STR") {
+			return "", nil, false
+		}
+	}
+
+	// in reverse order, so STR1 doesn't match the prefix of STR10
+	for i := len(literals) - 1; i >= 0; i-- {
+		code = strings.Replace(code, fmt.Sprintf("STR%d", i), literals[i], 1)
+	}
+
+	return code, notes, true
+}
+
+// literal returns an action which outputs s as is.
+func literal(s string) string {
+	return "{{ " + strconv.Quote(s) + " }}"
+}
+
+func parseList(s string) []string {
+	var res []string
+	for _, item := range strings.Split(s, ",") {
+		item = strings.Trim(strings.TrimSpace(item), `"'`)
+		if item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+func bundleResourceWarnings(dir string) []Warning {
+	var res []Warning
+	_ = filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Base(p) == "index.md" {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, p)
+		res = append(res, Warning{Message: fmt.Sprintf("bundle resource %s is not imported", rel)})
+		return nil
+	})
+	return res
+}
@@ -0,0 +1,100 @@
+package archetype
+
+import (
+	"context"
+	"github.io/uberate/hcli/pkg/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const defaultArchetype = `+++
+date = '{{ .Date }}'
+draft = true
+title = '{{ replace .File.ContentBaseName "-" " " | title }}'
+tags = ["go", 'hugo']
+lang = '{{- .Site.Language.Lang -}}'
++++
+{{< figure src="feature.png" >}}
+{{ .Params.author }}
+`
+
+func TestTranslate(t *testing.T) {
+	text, tags, categories, warnings := Translate(defaultArchetype)
+
+	if len(tags) != 2 || tags[1] != "hugo" || categories != nil {
+		t.Fatalf("Unexpected taxonomies: %v %v", tags, categories)
+	}
+
+	for _, expected := range []string{
+		"date = '{{ .createAt }}'",
+		`title = '{{ replace .title "-" " " | title }}'`,
+		"tags = {{ .tags }}",
+		"lang = '{{- .lang -}}'",
+		`{{ "{{< figure src=\"feature.png\" >}}" }}`,
+		`{{ "{{ .Params.author }}" }}`,
+	} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("Expected %q in translated archetype:\n%s", expected, text)
+		}
+	}
+
+	if len(warnings) != 1 || warnings[0].Line != 9 {
+		t.Fatalf("Unexpected warnings: %+v", warnings)
+	}
+
+	// the translated archetype renders with the hcli renderer
+	res, err := template.RenderTemplate(context.Background(), &template.Template{Template: text, Tags: tags},
+		template.RenderOption{Title: "hello-world", Lang: "en"})
+	if err != nil {
+		t.Fatalf("RenderTemplate failed: %v", err)
+	}
+	for _, expected := range []string{"title = 'Hello World'", `tags = ["go", "hugo"]`, "lang = 'en'",
+		`{{< figure src="feature.png" >}}`, "{{ .Params.author }}"} {
+		if !strings.Contains(res, expected) {
+			t.Fatalf("Expected %q in rendered post:\n%s", expected, res)
+		}
+	}
+}
+
+func TestTranslateDateFormat(t *testing.T) {
+	text, _, _, warnings := Translate(`date: {{ now.Format "2006-01-02" }}`)
+	if text != "date: {{ .createAt }}" {
+		t.Fatalf("Unexpected text: %s", text)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Message, "2006-01-02") {
+		t.Fatalf("Unexpected warnings: %+v", warnings)
+	}
+}
+
+func TestImportDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "default.md"), []byte(defaultArchetype), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "gallery"), 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "gallery", "index.md"), []byte("+++\n+++\n"), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "gallery", "cover.png"), []byte("png"), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	res, err := ImportDir(dir)
+	if err != nil {
+		t.Fatalf("ImportDir failed: %v", err)
+	}
+
+	if len(res) != 2 || res[0].Template.Name != "default" || res[1].Template.Name != "gallery" {
+		t.Fatalf("Unexpected archetypes: %+v", res)
+	}
+	if res[0].Template.Dir != "" || res[0].Template.NeedDir || !res[0].Template.ContentRelative {
+		t.Fatalf("Unexpected default template: %+v", res[0].Template)
+	}
+	if res[1].Template.Dir != "gallery" || !res[1].Template.NeedDir || len(res[1].Warnings) != 1 {
+		t.Fatalf("Unexpected bundle archetype: %+v", res[1])
+	}
+}
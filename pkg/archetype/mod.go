@@ -0,0 +1,18 @@
+// Package archetype imports Hugo archetypes as hcli templates.
+//
+// Archetypes are read from the archetypes directory of the site: 'archetypes/<kind>.md' becomes a single file
+// template, 'archetypes/<kind>/index.md' becomes a bundle template with NeedDir. The template Dir is the section of
+// the same name, relative to the content dir.
+//
+// The Hugo template actions are translated into the variables of template.RenderTemplate:
+//
+//	.Name, .Title, .File.ContentBaseName, .File.BaseFileName, .File.TranslationBaseName -> .title
+//	.Date, .PublishDate, .Lastmod, now                                                -> .createAt
+//	.Lang, .Language.Lang, .Site.Language.Lang                                         -> .lang
+//
+// The functions replace, title, lower, upper and humanize are kept, they are provided by the hcli renderer too.
+// Static 'tags' and 'categories' lists in the front matter move to the template Tags and Categories.
+//
+// Actions which can't be translated are kept as literal text and reported as warnings. Shortcodes are kept as
+// literal text silently.
+package archetype
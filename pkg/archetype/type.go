@@ -0,0 +1,17 @@
+package archetype
+
+import "github.io/uberate/hcli/pkg/template"
+
+// Archetype is an imported archetype.
+type Archetype struct {
+	// Path is the archetype file.
+	Path     string
+	Template template.Template
+	Warnings []Warning
+}
+
+// Warning is a construct of the archetype which was not translated.
+type Warning struct {
+	Line    int
+	Message string
+}
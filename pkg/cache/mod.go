@@ -0,0 +1,8 @@
+// Package cache is a content-addressed on-disk store with a TTL and a size limit.
+//
+// An entry is a file named by the SHA-256 key of its content, under a two character shard dir. Entries are written
+// to a temp file and renamed into place, so concurrent hcli processes only ever read whole entries; the last writer
+// of a key wins. The modification time of an entry file is its last write or hit, entries unused for longer than the
+// TTL are misses. The store keeps a running total of its size, when a write makes it bigger than its size limit the
+// least recently used entries are removed first.
+package cache
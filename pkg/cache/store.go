@@ -0,0 +1,266 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const tempPrefix = ".tmp-"
+
+// staleTemp is the age of a temp file which is surely left by a crashed process.
+const staleTemp = time.Hour
+
+// New returns the store in dir.
+func New(dir string, ttl time.Duration, maxSize int64) *Store {
+	return &Store{dir: dir, ttl: ttl, maxSize: maxSize, now: time.Now}
+}
+
+// Dir returns the dir of the store.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Key returns the key of parts, the parts are length prefixed so ("ab", "c") and ("a", "bc") differ.
+func Key(parts ...string) string {
+	h := sha256.New()
+	var size [8]byte
+	for _, p := range parts {
+		binary.BigEndian.PutUint64(size[:], uint64(len(p)))
+		h.Write(size[:])
+		h.Write([]byte(p))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key[:2], key)
+}
+
+// Get returns the entry of key, the second result is false on a miss. Expired entries are removed, a hit touches
+// the modification time of the entry so the eviction and the TTL follow its last use.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	if len(key) < 3 {
+		return nil, false, errors.New("invalid cache key")
+	}
+
+	p := s.path(key)
+	info, err := os.Stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if s.expired(info) {
+		_ = os.Remove(p)
+		return nil, false, nil
+	}
+
+	data, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		// removed by another process after the stat
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	// a read-only cache still serves its entries
+	now := s.now()
+	_ = os.Chtimes(p, now, now)
+	return data, true, nil
+}
+
+// Put writes the entry of key and removes the least recently used entries if the store outgrows its size limit.
+func (s *Store) Put(key string, data []byte) error {
+	if len(key) < 3 {
+		return errors.New("invalid cache key")
+	}
+	if s.maxSize > 0 && int64(len(data)) > s.maxSize {
+		return nil
+	}
+
+	p := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	var replaced int64
+	if info, err := os.Stat(p); err == nil {
+		replaced = info.Size()
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(p), tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), p)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+
+	if s.maxSize > 0 {
+		err = s.grow(int64(len(data)) - replaced)
+	}
+	return err
+}
+
+// Prune removes the expired entries and the stale temp files, then the oldest entries until the store fits its size
+// limit. all removes every entry.
+func (s *Store) Prune(all bool) (PruneResult, error) {
+	var res PruneResult
+
+	entries, err := s.entries()
+	if err != nil {
+		return res, err
+	}
+
+	var kept []entry
+	for _, e := range entries {
+		remove := all || s.expired(e.info)
+		if e.temp {
+			// fresh temp files belong to running writers
+			remove = s.now().Sub(e.info.ModTime()) > staleTemp
+		}
+
+		if remove {
+			if removeEntry(e) {
+				res.Removed++
+				res.Freed += e.info.Size()
+			}
+			continue
+		}
+		kept = append(kept, e)
+	}
+
+	if s.maxSize > 0 {
+		removed, freed, _ := evict(kept, s.maxSize)
+		res.Removed += removed
+		res.Freed += freed
+	}
+
+	for _, e := range kept {
+		if _, err := os.Stat(e.path); err == nil && !e.temp {
+			res.Kept++
+			res.Size += e.info.Size()
+		}
+	}
+
+	// the next write walks the store again
+	s.mu.Lock()
+	s.sized = false
+	s.mu.Unlock()
+
+	return res, nil
+}
+
+// grow adds delta to the size of the store, and removes the least recently used entries if it is then over the size
+// limit. The size is walked on the first write and on eviction only, the writes of other processes in between are
+// counted at the next walk.
+func (s *Store) grow(delta int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sized {
+		s.size += delta
+		if s.size <= s.maxSize {
+			return nil
+		}
+	}
+
+	entries, err := s.entries()
+	if err != nil {
+		return err
+	}
+	_, _, s.size = evict(entries, s.maxSize)
+	s.sized = true
+	return nil
+}
+
+type entry struct {
+	path string
+	info fs.FileInfo
+	temp bool
+}
+
+// entries returns the files of the store, least recently used first.
+func (s *Store) entries() ([]entry, error) {
+	var res []entry
+	err := filepath.WalkDir(s.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			// removed by another process during the walk
+			return nil
+		}
+		res = append(res, entry{path: p, info: info, temp: strings.HasPrefix(d.Name(), tempPrefix)})
+		return nil
+	})
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].info.ModTime().Before(res[j].info.ModTime())
+	})
+
+	return res, err
+}
+
+// evict removes the least recently used entries until the total size is at most maxSize, it returns the removed
+// entries, their size and the total size left. Temp files count but are never removed, they belong to running writers.
+func evict(entries []entry, maxSize int64) (int, int64, int64) {
+	var total int64
+	for _, e := range entries {
+		total += e.info.Size()
+	}
+
+	var removed int
+	var freed int64
+	for _, e := range entries {
+		if total <= maxSize {
+			break
+		}
+		if e.temp {
+			continue
+		}
+		if removeEntry(e) {
+			removed++
+			freed += e.info.Size()
+		}
+		total -= e.info.Size()
+	}
+
+	return removed, freed, total
+}
+
+// removeEntry removes e, it reports false if another process removed it first.
+func removeEntry(e entry) bool {
+	return os.Remove(e.path) == nil
+}
+
+func (s *Store) expired(info fs.FileInfo) bool {
+	return s.ttl > 0 && s.now().Sub(info.ModTime()) > s.ttl
+}
@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKey(t *testing.T) {
+	if Key("ab", "c") == Key("a", "bc") {
+		t.Fatal("Expected the parts to be length prefixed")
+	}
+	if Key("a") != Key("a") || len(Key("a")) != 64 {
+		t.Fatal("Expected a stable sha256 key")
+	}
+}
+
+func TestGetPut(t *testing.T) {
+	s := New(t.TempDir(), time.Hour, 0)
+	key := Key("text", "hello")
+
+	if _, ok, err := s.Get(key); ok || err != nil {
+		t.Fatalf("Expected a miss, got %v, %v", ok, err)
+	}
+	if err := s.Put(key, []byte("world")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	data, ok, err := s.Get(key)
+	if !ok || err != nil || string(data) != "world" {
+		t.Fatalf("Expected a hit, got %q, %v, %v", data, ok, err)
+	}
+
+	// an expired entry is a miss and is removed
+	s.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, ok, _ = s.Get(key); ok {
+		t.Fatal("Expected the expired entry to miss")
+	}
+	if _, err = os.Stat(s.path(key)); !os.IsNotExist(err) {
+		t.Fatal("Expected the expired entry to be removed")
+	}
+}
+
+func TestSizeLimit(t *testing.T) {
+	s := New(t.TempDir(), 0, 10)
+	old := time.Now().Add(-time.Minute)
+
+	for i := 0; i < 3; i++ {
+		key := Key(fmt.Sprint(i))
+		if err := s.Put(key, []byte("1234")); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		// make the order of the entries deterministic
+		_ = os.Chtimes(s.path(key), old.Add(time.Duration(i)*time.Second), old.Add(time.Duration(i)*time.Second))
+	}
+
+	if _, ok, _ := s.Get(Key("0")); ok {
+		t.Fatal("Expected the oldest entry to be evicted")
+	}
+	for _, k := range []string{"1", "2"} {
+		if _, ok, _ := s.Get(Key(k)); !ok {
+			t.Fatalf("Expected entry %s to be kept", k)
+		}
+	}
+
+	if err := s.Put(Key("big"), make([]byte, 11)); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, ok, _ := s.Get(Key("big")); ok {
+		t.Fatal("Expected an entry over the size limit not to be stored")
+	}
+}
+
+func TestEvictLeastRecentlyUsed(t *testing.T) {
+	s := New(t.TempDir(), 0, 10)
+	old := time.Now().Add(-time.Minute)
+
+	for i := 0; i < 2; i++ {
+		key := Key(fmt.Sprint(i))
+		if err := s.Put(key, []byte("1234")); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		_ = os.Chtimes(s.path(key), old.Add(time.Duration(i)*time.Second), old.Add(time.Duration(i)*time.Second))
+	}
+	if s.size != 8 {
+		t.Fatalf("Expected the size to be tracked, got %d", s.size)
+	}
+
+	// the hit makes the older entry the most recently used one
+	if _, ok, _ := s.Get(Key("0")); !ok {
+		t.Fatal("Expected a hit")
+	}
+	if err := s.Put(Key("2"), []byte("1234")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, ok, _ := s.Get(Key("1")); ok {
+		t.Fatal("Expected the least recently used entry to be evicted")
+	}
+	for _, k := range []string{"0", "2"} {
+		if _, ok, _ := s.Get(Key(k)); !ok {
+			t.Fatalf("Expected entry %s to be kept", k)
+		}
+	}
+	if s.size != 8 {
+		t.Fatalf("Expected the size left after the eviction, got %d", s.size)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	s := New(dir, time.Hour, 0)
+
+	_ = s.Put(Key("fresh"), []byte("12"))
+	_ = s.Put(Key("expired"), []byte("123"))
+	expired := time.Now().Add(-2 * time.Hour)
+	_ = os.Chtimes(s.path(Key("expired")), expired, expired)
+
+	stale := filepath.Join(dir, "ab", tempPrefix+"1")
+	running := filepath.Join(dir, "ab", tempPrefix+"2")
+	_ = os.MkdirAll(filepath.Dir(stale), 0755)
+	_ = os.WriteFile(stale, []byte("x"), 0644)
+	_ = os.WriteFile(running, []byte("x"), 0644)
+	_ = os.Chtimes(stale, expired, expired)
+
+	res, err := s.Prune(false)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if res.Removed != 2 || res.Freed != 4 || res.Kept != 1 || res.Size != 2 {
+		t.Fatalf("Unexpected prune result: %+v", res)
+	}
+	if _, err = os.Stat(running); err != nil {
+		t.Fatal("Expected the temp file of a running writer to be kept")
+	}
+
+	if res, err = s.Prune(true); err != nil || res.Removed != 1 || res.Kept != 0 {
+		t.Fatalf("Unexpected prune all result: %+v, %v", res, err)
+	}
+
+	if _, err = New(filepath.Join(dir, "missing"), 0, 0).Prune(false); err != nil {
+		t.Fatalf("Expected a missing dir to prune nothing: %v", err)
+	}
+}
+
+func TestConcurrentPut(t *testing.T) {
+	dir := t.TempDir()
+	key := Key("same")
+
+	// separate stores stand for separate processes
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s := New(dir, time.Hour, 1024)
+			for j := 0; j < 20; j++ {
+				if err := s.Put(key, []byte(fmt.Sprintf("value-%d", i))); err != nil {
+					t.Errorf("Put failed: %v", err)
+				}
+				if data, ok, err := s.Get(key); err != nil || (ok && len(data) != len("value-0")) {
+					t.Errorf("Expected whole entries, got %q, %v", data, err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	entries, _ := New(dir, 0, 0).entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected one entry without temp files left, got %d", len(entries))
+	}
+}
@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Store is an on-disk cache, it is safe for concurrent use by goroutines and processes.
+type Store struct {
+	dir string
+	// ttl zero means entries never expire.
+	ttl time.Duration
+	// maxSize zero means no size limit.
+	maxSize int64
+
+	// mu guards size, the total size of the entries known to this store. sized is false until it was walked.
+	mu    sync.Mutex
+	size  int64
+	sized bool
+
+	now func() time.Time
+}
+
+// PruneResult is the result of Prune.
+type PruneResult struct {
+	Removed int
+	Freed   int64
+	Kept    int
+	Size    int64
+}
@@ -2,25 +2,165 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"github.io/uberate/hcli/pkg/cover"
 	"github.io/uberate/hcli/pkg/fileio"
+	"github.io/uberate/hcli/pkg/hugo"
+	"github.io/uberate/hcli/pkg/imaging"
+	"github.io/uberate/hcli/pkg/lint"
 	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/overlay"
+	"github.io/uberate/hcli/pkg/prompts"
 	"github.io/uberate/hcli/pkg/template"
+	"github.io/uberate/hcli/pkg/usage"
+	"path/filepath"
+	"strings"
 )
 
+const defaultPromptDir = "prompts"
+
 type CliConfig struct {
 	Templates []template.Template `yaml:"Templates" describe:"Define the template of posts."`
 	LLMs      llms.Config         `yaml:"LLMs" describe:"Define the LLMs configuration"`
+	Hugo      HugoConfig          `yaml:"Hugo" describe:"Define the Hugo site of posts"`
+
+	PromptDir string           `yaml:"PromptDir" describe:"The dir of the prompt files, relative to the config file." default:"prompts"`
+	Prompts   []prompts.Prompt `yaml:"Prompts" describe:"Define the prompts, they override the prompt files and the builtin prompts."`
+
+	Usage usage.Config `yaml:"Usage" describe:"Define the usage ledger and the model prices"`
+
+	// Site is the Hugo site found when the config is read, nil if hcli doesn't run in a Hugo site.
+	Site *hugo.Site `yaml:"-"`
+	// SiteErr is why no site was found upwards from the config dir, the commands report it.
+	SiteErr error `yaml:"-"`
+}
+
+type HugoConfig struct {
+	Root string `yaml:"Root" describe:"The Hugo site root, empty means search upwards from the config file directory."`
 }
 
 func (cc CliConfig) SearchTemplate(name string) (template.Template, error) {
 	for _, t := range cc.Templates {
 		if t.Name == name {
+			if t.ContentRelative && cc.Site == nil {
+				return t, fmt.Errorf("template %s needs a Hugo site for the content relative dir, but no site found", name)
+			}
 			return t, nil
 		}
 	}
 	return template.Template{}, errors.New("template not found")
 }
 
+// PostFiles returns the post files of all the templates, the dirs shared by templates are read once. The content
+// relative templates are skipped without a Hugo site.
+func (cc CliConfig) PostFiles() ([]string, error) {
+	seen := map[string]bool{}
+	var res []string
+	for _, t := range cc.Templates {
+		dir := filepath.Clean(t.Dir)
+		if t.Dir == "" || seen[dir] || (t.ContentRelative && cc.Site == nil) {
+			continue
+		}
+		seen[dir] = true
+
+		files, err := t.PostFiles()
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if !seen[f] {
+				seen[f] = true
+				res = append(res, f)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// TemplateOf returns the template whose dir holds the post at path, the deepest dir wins. The second result is
+// false if no template holds the post.
+func (cc CliConfig) TemplateOf(path string) (template.Template, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return template.Template{}, false
+	}
+
+	var res template.Template
+	found, depth := false, 0
+	for _, t := range cc.Templates {
+		if t.Dir == "" || (t.ContentRelative && cc.Site == nil) {
+			continue
+		}
+		dir, err := filepath.Abs(t.Dir)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(dir, abs); err != nil || rel == ".." ||
+			strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if !found || len(dir) > depth {
+			res, found, depth = t, true, len(dir)
+		}
+	}
+
+	return res, found
+}
+
+// PromptRegistry returns the builtin prompts overridden by the prompt dir and the config prompts.
+func (cc CliConfig) PromptRegistry() (*prompts.Registry, error) {
+	r := prompts.NewRegistry()
+	if err := r.LoadDir(cc.PromptDir); err != nil {
+		return nil, err
+	}
+
+	for _, p := range cc.Prompts {
+		p.Source = prompts.SourceConfig
+		if err := r.Add(p); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// loadSite finds the Hugo site and resolves the content relative template dirs. A site searched from the config dir
+// is optional: if it can't be loaded, hcli runs without a site unless a template is content relative.
+func (cc *CliConfig) loadSite(configDir string) error {
+	var err error
+	if cc.Hugo.Root != "" {
+		cc.Site, err = hugo.FindSite(cc.Hugo.Root)
+	} else {
+		cc.Site, err = hugo.FindSite(configDir)
+		if err != nil && (errors.Is(err, hugo.ErrSiteNotFound) || !cc.contentRelative()) {
+			cc.Site, cc.SiteErr = nil, err
+			return nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	for i, t := range cc.Templates {
+		if t.ContentRelative {
+			cc.Templates[i].Dir = cc.Site.ContentPath(t.Dir)
+		}
+	}
+
+	return nil
+}
+
+// contentRelative reports whether a template dir is relative to the content dir of the site.
+func (cc *CliConfig) contentRelative() bool {
+	for _, t := range cc.Templates {
+		if t.ContentRelative {
+			return true
+		}
+	}
+	return false
+}
+
 func DefaultCliConfig() CliConfig {
 	return CliConfig{}
 }
@@ -39,8 +179,42 @@ func ExampleCliConfig() CliConfig {
 				},
 				Template:         "",
 				Dir:              "",
+				ContentRelative:  false,
 				NeedDir:          false,
 				PicSummaryPrompt: "",
+				Poster: imaging.Config{
+					Width:      1200,
+					Height:     630,
+					Crop:       imaging.CropSmart,
+					Format:     imaging.FormatJPEG,
+					Quality:    85,
+					Thumbnails: []int{320, 640},
+				},
+				Overlay: overlay.Config{
+					Enabled:  false,
+					FontFile: "/usr/share/fonts/noto-cjk/NotoSansCJK-Bold.ttc",
+					Position: overlay.PositionBottom,
+					ShowDate: true,
+				},
+				Cover: cover.Config{
+					Palette: cover.DefaultPalette,
+					Pattern: cover.PatternAuto,
+				},
+				PosterFrontMatter: template.PosterFrontMatter{
+					Image:  []string{"cover.image"},
+					Alt:    []string{"cover.alt"},
+					Images: []string{"images"},
+				},
+				Languages: []template.LanguageTemplate{
+					{
+						Lang:        "en",
+						Template:    "",
+						FrontMatter: []string{"categories=Read"},
+					},
+				},
+				Lint: lint.Config{
+					RequiredFrontMatter: []string{"title", "date"},
+				},
 			},
 		},
 		LLMs: llms.Config{
@@ -50,11 +224,72 @@ func ExampleCliConfig() CliConfig {
 				TextModel: "123",
 				PicModel:  "123",
 			},
+			Retry: llms.RetryConfig{
+				MaxAttempts:    3,
+				InitialBackoff: "1s",
+				MaxBackoff:     "30s",
+				TextTimeout:    "2m",
+				PicTimeout:     "5m",
+			},
+			Profiles: []llms.Profile{
+				{
+					Name:     "cheap",
+					Provider: "volc",
+					VolcEngineConfig: llms.VolcEngineConfig{
+						TextModel: "456",
+					},
+				},
+			},
+			Routes: []llms.Route{
+				{Task: llms.TaskSummary, Profiles: []string{"cheap", llms.DefaultProfile}},
+			},
+		},
+		PromptDir: defaultPromptDir,
+		Prompts: []prompts.Prompt{
+			{
+				Name:        prompts.TranslateTitle,
+				Version:     "2",
+				Description: "translate a post title to lang",
+				Template:    "Translate the post title to '{{ .lang }}', output the title only.",
+			},
 		},
+		Usage: usage.Config{
+			Ledger: ".hcli_usage.jsonl",
+			Prices: []usage.Price{
+				{Provider: "volc", Model: "123", PromptPerMillion: 0.8, CompletionPerMillion: 2, PerImage: 0.2},
+			},
+		},
+	}
+}
+
+// AppendTemplates appends templates to the config file at path, the rest of the file is kept.
+func AppendTemplates(path string, templates ...template.Template) error {
+	items := make([]interface{}, 0, len(templates))
+	for _, t := range templates {
+		items = append(items, t)
 	}
+
+	return fileio.AppendYamlList(path, "Templates", items...)
 }
 
 func ReadConfig(path string) (CliConfig, error) {
 	c := DefaultCliConfig()
-	return c, fileio.ReadYaml(path, &c)
+	if err := fileio.ReadYaml(path, &c); err != nil {
+		return c, err
+	}
+
+	if c.PromptDir == "" {
+		c.PromptDir = defaultPromptDir
+	}
+	if !filepath.IsAbs(c.PromptDir) {
+		c.PromptDir = filepath.Join(filepath.Dir(path), c.PromptDir)
+	}
+	if c.Usage.Ledger != "" && !filepath.IsAbs(c.Usage.Ledger) {
+		c.Usage.Ledger = filepath.Join(filepath.Dir(path), c.Usage.Ledger)
+	}
+	if c.LLMs.Cache.Dir != "" && !filepath.IsAbs(c.LLMs.Cache.Dir) {
+		c.LLMs.Cache.Dir = filepath.Join(filepath.Dir(path), c.LLMs.Cache.Dir)
+	}
+
+	return c, c.loadSite(filepath.Dir(path))
 }
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"github.io/uberate/hcli/pkg/template"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadConfigWithSite(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "hugo.toml"), []byte("contentDir = 'docs'\n"), 0644); err != nil {
+		t.Fatalf("write hugo config failed: %v", err)
+	}
+
+	configPath := filepath.Join(root, ".hcli_config.yaml")
+	configData := "Templates:\n" +
+		"  - Name: rel\n    Dir: posts\n    ContentRelative: true\n" +
+		"  - Name: abs\n    Dir: /tmp/posts\n"
+	if err := os.WriteFile(configPath, []byte(configData), 0644); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	c, err := ReadConfig(configPath)
+	if err != nil {
+		t.Fatalf("ReadConfig failed: %v", err)
+	}
+	if c.Site == nil || c.Site.Root != root {
+		t.Fatalf("Expected site at %s, got %+v", root, c.Site)
+	}
+
+	tp, err := c.SearchTemplate("rel")
+	if err != nil {
+		t.Fatalf("SearchTemplate failed: %v", err)
+	}
+	if tp.Dir != filepath.Join(root, "docs", "posts") {
+		t.Fatalf("Unexpected template dir: %s", tp.Dir)
+	}
+
+	if tp, _ = c.SearchTemplate("abs"); tp.Dir != "/tmp/posts" {
+		t.Fatalf("Unexpected template dir: %s", tp.Dir)
+	}
+}
+
+func TestReadConfigIgnoresGenericConfig(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "config.yaml"), []byte("- a list\n- not a hugo config\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "blog"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := filepath.Join(root, "blog", ".hcli_config.yaml")
+	if err := os.WriteFile(configPath, []byte("Templates:\n  - Name: post\n    Dir: posts\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := ReadConfig(configPath)
+	if err != nil {
+		t.Fatalf("ReadConfig failed: %v", err)
+	}
+	if c.Site != nil || c.SiteErr == nil {
+		t.Fatalf("Expected no site and the reason, got %+v, %v", c.Site, c.SiteErr)
+	}
+	if _, err = c.SearchTemplate("post"); err != nil {
+		t.Fatalf("SearchTemplate failed: %v", err)
+	}
+
+	// a broken site is only fatal if a template dir depends on it.
+	if err = os.WriteFile(filepath.Join(root, "hugo.toml"), []byte("title = \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if c, err = ReadConfig(configPath); err != nil || c.SiteErr == nil {
+		t.Fatalf("Expected the broken site ignored: %v, %v", err, c.SiteErr)
+	}
+	rel := "Templates:\n  - Name: post\n    Dir: posts\n    ContentRelative: true\n"
+	if err = os.WriteFile(configPath, []byte(rel), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ReadConfig(configPath); err == nil {
+		t.Fatal("Expected the broken site to fail a content relative template")
+	}
+}
+
+func TestSearchTemplateWithoutSite(t *testing.T) {
+	c := CliConfig{Templates: ExampleCliConfig().Templates}
+	c.Templates[0].ContentRelative = true
+
+	if _, err := c.SearchTemplate("template-name"); err == nil {
+		t.Fatal("Expected error for content relative template without site")
+	}
+	if _, err := c.SearchTemplate("missing"); err == nil {
+		t.Fatal("Expected error for missing template")
+	}
+}
+
+func TestPromptRegistry(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "prompts", "translate"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"prompts/translate/body.md":  "+++\nversion = '2'\n+++\nfrom file",
+		"prompts/translate/title.md": "from file",
+		".hcli_config.yaml":          "Prompts:\n  - Name: translate.title\n    Template: from config\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err := ReadConfig(filepath.Join(root, ".hcli_config.yaml"))
+	if err != nil {
+		t.Fatalf("ReadConfig failed: %v", err)
+	}
+	if c.PromptDir != filepath.Join(root, "prompts") {
+		t.Fatalf("Expected the prompt dir relative to the config: %s", c.PromptDir)
+	}
+
+	r, err := c.PromptRegistry()
+	if err != nil {
+		t.Fatalf("PromptRegistry failed: %v", err)
+	}
+	for name, want := range map[string]string{"translate.body": "from file", "translate.title": "from config"} {
+		if res, _ := r.Render(name, nil); res != want {
+			t.Fatalf("Unexpected %s: %s", name, res)
+		}
+	}
+}
+
+func TestPostFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"posts/a.md", "posts/b/index.md", "notes/c.md"} {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := CliConfig{Templates: []template.Template{
+		{Name: "a", Dir: filepath.Join(root, "posts")},
+		{Name: "b", Dir: filepath.Join(root, "posts") + "/"},
+		{Name: "all", Dir: root},
+		{Name: "rel", Dir: "content", ContentRelative: true},
+	}}
+	files, err := c.PostFiles()
+	if err != nil {
+		t.Fatalf("PostFiles failed: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("Expected every post once, got %q", files)
+	}
+}
+
+func TestTemplateOf(t *testing.T) {
+	root := t.TempDir()
+	c := CliConfig{Templates: []template.Template{
+		{Name: "all", Dir: root},
+		{Name: "posts", Dir: filepath.Join(root, "posts")},
+		{Name: "rel", Dir: "content", ContentRelative: true},
+	}}
+
+	for path, want := range map[string]string{
+		filepath.Join(root, "posts", "a", "index.md"): "posts",
+		filepath.Join(root, "postscript.md"):          "all",
+		filepath.Join(root, "..", "other.md"):         "",
+	} {
+		tp, ok := c.TemplateOf(path)
+		if tp.Name != want || ok != (want != "") {
+			t.Fatalf("Unexpected template of %s: %s, %v", path, tp.Name, ok)
+		}
+	}
+}
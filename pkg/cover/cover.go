@@ -0,0 +1,146 @@
+package cover
+
+import (
+	"fmt"
+	"github.io/uberate/hcli/pkg/imaging"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"math"
+	"math/rand/v2"
+)
+
+var patterns = []string{PatternGradient, PatternStripes, PatternDots}
+
+// Validate checks the values of the config.
+func (c Config) Validate() error {
+	switch c.Pattern {
+	case "", PatternAuto, PatternGradient, PatternStripes, PatternDots:
+	default:
+		return fmt.Errorf("invalid cover pattern '%s', support: auto, gradient, stripes, dots", c.Pattern)
+	}
+
+	_, err := c.colors()
+	return err
+}
+
+func (c Config) colors() ([]color.RGBA, error) {
+	palette := c.Palette
+	if len(palette) == 0 {
+		palette = DefaultPalette
+	}
+
+	res := make([]color.RGBA, 0, len(palette))
+	for _, p := range palette {
+		v, err := imaging.ParseColor(p, color.RGBA{})
+		if err != nil {
+			return nil, err
+		}
+		v.A = 255
+		res = append(res, v)
+	}
+
+	return res, nil
+}
+
+// Render draws the background of the post with title. The same title always renders the same image.
+func Render(c Config, title string, width, height int) (image.Image, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid cover size %dx%d", width, height)
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(title))
+	seed := h.Sum64()
+	rng := rand.New(rand.NewPCG(seed, seed>>7|1))
+
+	colors, _ := c.colors()
+	rng.Shuffle(len(colors), func(i, j int) {
+		colors[i], colors[j] = colors[j], colors[i]
+	})
+	pick := func(i int) color.RGBA {
+		return colors[i%len(colors)]
+	}
+
+	pattern := c.Pattern
+	if pattern == "" || pattern == PatternAuto {
+		pattern = patterns[rng.IntN(len(patterns))]
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	gradient(img, pick(0), pick(1), rng.Float64()*2*math.Pi)
+
+	switch pattern {
+	case PatternStripes:
+		stripes(img, rng)
+	case PatternDots:
+		dots(img, pick(2), rng)
+	}
+
+	return img, nil
+}
+
+// gradient fills img with a linear gradient from c1 to c2 along angle.
+func gradient(img *image.RGBA, c1, c2 color.RGBA, angle float64) {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	half := (math.Abs(cos)*float64(w) + math.Abs(sin)*float64(h)) / 2
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			d := (float64(x)-float64(w)/2)*cos + (float64(y)-float64(h)/2)*sin
+			img.SetRGBA(x, y, lerp(c1, c2, (d/half+1)/2))
+		}
+	}
+}
+
+// stripes lightens diagonal bands of img.
+func stripes(img *image.RGBA, rng *rand.Rand) {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	period := float64(h) / float64(4+rng.IntN(6))
+	angle := math.Pi/6 + rng.Float64()*math.Pi/3
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			d := math.Mod(float64(x)*cos+float64(y)*sin, period)
+			if d < period/2 {
+				img.SetRGBA(x, y, lerp(img.RGBAAt(x, y), white, 0.12))
+			}
+		}
+	}
+}
+
+// dots draws a jittered grid of translucent circles with c.
+func dots(img *image.RGBA, c color.RGBA, rng *rand.Rand) {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	spacing := float64(w) / float64(6+rng.IntN(6))
+
+	for cy := spacing / 2; cy < float64(h)+spacing; cy += spacing {
+		for cx := spacing / 2; cx < float64(w)+spacing; cx += spacing {
+			x0 := cx + (rng.Float64()-0.5)*spacing/2
+			y0 := cy + (rng.Float64()-0.5)*spacing/2
+			r := spacing * (0.15 + rng.Float64()*0.25)
+
+			for y := max(0, int(y0-r)); y < min(h, int(y0+r)+1); y++ {
+				for x := max(0, int(x0-r)); x < min(w, int(x0+r)+1); x++ {
+					if math.Hypot(float64(x)-x0, float64(y)-y0) <= r {
+						img.SetRGBA(x, y, lerp(img.RGBAAt(x, y), c, 0.35))
+					}
+				}
+			}
+		}
+	}
+}
+
+func lerp(c1, c2 color.RGBA, t float64) color.RGBA {
+	t = math.Max(0, math.Min(1, t))
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a) + (float64(b)-float64(a))*t))
+	}
+	return color.RGBA{R: mix(c1.R, c2.R), G: mix(c1.G, c2.G), B: mix(c1.B, c2.B), A: 255}
+}
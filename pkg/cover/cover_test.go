@@ -0,0 +1,47 @@
+package cover
+
+import (
+	"bytes"
+	"github.io/uberate/hcli/internal/golden"
+	"image"
+	"testing"
+)
+
+func TestRenderGolden(t *testing.T) {
+	for _, pattern := range []string{PatternGradient, PatternStripes, PatternDots} {
+		t.Run(pattern, func(t *testing.T) {
+			img, err := Render(Config{Pattern: pattern}, "Hello hcli", 240, 126)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			golden.Image(t, pattern, img)
+		})
+	}
+}
+
+func TestRenderIsSeededByTitle(t *testing.T) {
+	c := Config{Palette: []string{"#000000", "#ffffff", "#ff0000"}}
+
+	a, _ := Render(c, "same title", 40, 20)
+	b, _ := Render(c, "same title", 40, 20)
+	other, _ := Render(c, "another title", 40, 20)
+
+	if !bytes.Equal(a.(*image.RGBA).Pix, b.(*image.RGBA).Pix) {
+		t.Fatal("The same title must render the same cover")
+	}
+	if bytes.Equal(a.(*image.RGBA).Pix, other.(*image.RGBA).Pix) {
+		t.Fatal("Different titles should render different covers")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (Config{Pattern: "noise"}).Validate(); err == nil {
+		t.Fatal("Expected an invalid pattern to fail")
+	}
+	if err := (Config{Palette: []string{"blue"}}).Validate(); err == nil {
+		t.Fatal("Expected an invalid palette to fail")
+	}
+	if _, err := Render(Config{}, "a", 0, 10); err == nil {
+		t.Fatal("Expected an invalid size to fail")
+	}
+}
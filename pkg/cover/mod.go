@@ -0,0 +1,12 @@
+// Package cover renders poster backgrounds procedurally, without any image model.
+//
+// The background is seeded from the hash of the post title, so the same post always gets the same cover and
+// different posts get different ones. Colors come from the template palette, the pattern is one of:
+//
+//	gradient  a linear gradient with a seeded angle
+//	stripes   a gradient crossed by translucent diagonal stripes
+//	dots      a gradient covered by a jittered grid of translucent circles
+//	auto      a pattern picked by the seed
+//
+// The title and the tags are typeset on the background by the overlay package.
+package cover
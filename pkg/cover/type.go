@@ -0,0 +1,20 @@
+package cover
+
+const (
+	PatternAuto     = "auto"
+	PatternGradient = "gradient"
+	PatternStripes  = "stripes"
+	PatternDots     = "dots"
+
+	DefaultWidth  = 1200
+	DefaultHeight = 630
+)
+
+// DefaultPalette is used when the template has no palette.
+var DefaultPalette = []string{"#264653", "#2a9d8f", "#e9c46a", "#f4a261", "#e76f51", "#6d597a"}
+
+// Config is the local cover setting of a template.
+type Config struct {
+	Palette []string `yaml:"Palette" describe:"The cover colors, #rrggbb. Empty means the built-in palette."`
+	Pattern string   `yaml:"Pattern" describe:"The cover background, support: auto, gradient, stripes, dots." default:"auto"`
+}
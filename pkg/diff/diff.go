@@ -0,0 +1,137 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Context is the number of unchanged lines around the changes of a hunk.
+const Context = 3
+
+// op is a line of the edit script: ' ' kept, '-' deleted from a or '+' inserted from b. a and b are the indexes
+// of the line in the texts, or the index of the next line for the text the line isn't in.
+type op struct {
+	kind byte
+	text string
+	a, b int
+}
+
+// Unified returns the unified diff of a and b named aName and bName, empty if they are equal.
+func Unified(aName, bName, a, b string) string {
+	if a == b {
+		return ""
+	}
+
+	ops := edits(splitLines(a), splitLines(b))
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", aName, bName))
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// the hunk takes the next changes until more than two contexts of kept lines
+		last := i
+		for j := i; j < len(ops) && j-last-1 <= 2*Context; j++ {
+			if ops[j].kind != ' ' {
+				last = j
+			}
+		}
+		start, end := max(0, i-Context), min(len(ops), last+Context+1)
+		writeHunk(&builder, ops[start:end])
+		i = end
+	}
+
+	return builder.String()
+}
+
+func writeHunk(builder *strings.Builder, ops []op) {
+	aLen, bLen := 0, 0
+	for _, o := range ops {
+		if o.kind != '+' {
+			aLen++
+		}
+		if o.kind != '-' {
+			bLen++
+		}
+	}
+
+	builder.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(ops[0].a, aLen), hunkRange(ops[0].b, bLen)))
+	for _, o := range ops {
+		builder.WriteByte(o.kind)
+		builder.WriteString(o.text)
+		builder.WriteByte('\n')
+	}
+}
+
+// hunkRange formats the 1-based range of the hunk of length n from the index start, an empty range is after the
+// line start.
+func hunkRange(start, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
+
+// splitLines splits s into lines, the final line break doesn't end an empty line.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// edits returns the edit script from a to b.
+func edits(a, b []string) []op {
+	head := 0
+	for head < len(a) && head < len(b) && a[head] == b[head] {
+		head++
+	}
+	tail := 0
+	for tail < len(a)-head && tail < len(b)-head && a[len(a)-1-tail] == b[len(b)-1-tail] {
+		tail++
+	}
+	am, bm := a[head:len(a)-tail], b[head:len(b)-tail]
+
+	// lcs[i][j] is the length of the longest common subsequence of am[i:] and bm[j:]
+	lcs := make([][]int, len(am)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bm)+1)
+	}
+	for i := len(am) - 1; i >= 0; i-- {
+		for j := len(bm) - 1; j >= 0; j-- {
+			if am[i] == bm[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var res []op
+	for i := 0; i < head; i++ {
+		res = append(res, op{kind: ' ', text: a[i], a: i, b: i})
+	}
+	i, j := 0, 0
+	for i < len(am) || j < len(bm) {
+		switch {
+		case i < len(am) && j < len(bm) && am[i] == bm[j]:
+			res = append(res, op{kind: ' ', text: am[i], a: head + i, b: head + j})
+			i++
+			j++
+		case j == len(bm) || (i < len(am) && lcs[i+1][j] >= lcs[i][j+1]):
+			res = append(res, op{kind: '-', text: am[i], a: head + i, b: head + j})
+			i++
+		default:
+			res = append(res, op{kind: '+', text: bm[j], a: head + i, b: head + j})
+			j++
+		}
+	}
+	for k := 0; k < tail; k++ {
+		res = append(res, op{kind: ' ', text: a[len(a)-tail+k], a: len(a) - tail + k, b: len(b) - tail + k})
+	}
+
+	return res
+}
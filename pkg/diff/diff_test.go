@@ -0,0 +1,37 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+
+	want := "--- a.md\n+++ b.md\n" +
+		"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+		"@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n"
+	if got := Unified("a.md", "b.md", a, b); got != want {
+		t.Fatalf("Unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := Unified("a", "b", a, a); got != "" {
+		t.Fatalf("Expected no diff of equal texts, got %s", got)
+	}
+}
+
+func TestUnifiedMergesHunks(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n"
+	b := "0\n1\n2\n3\n4\n5\n6\n8\n"
+
+	got := Unified("a", "b", a, b)
+	if strings.Count(got, "@@ ") != 1 || !strings.Contains(got, "@@ -1,8 +1,8 @@\n+0\n 1\n") ||
+		!strings.HasSuffix(got, " 6\n-7\n 8\n") {
+		t.Fatalf("Expected one hunk, got:\n%s", got)
+	}
+
+	if got = Unified("a", "b", "", "x\n"); got != "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+x\n" {
+		t.Fatalf("Unexpected diff from an empty text:\n%s", got)
+	}
+}
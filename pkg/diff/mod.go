@@ -0,0 +1,5 @@
+// Package diff renders the line differences of two texts in the unified format of 'diff -u' and 'git diff'.
+//
+// The differences are the longest common subsequence of the lines, computed after the common head and tail are
+// cut, which keeps the edits of posts cheap to compare.
+package diff
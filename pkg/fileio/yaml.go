@@ -1,6 +1,8 @@
 package fileio
 
 import (
+	"bytes"
+	"fmt"
 	"gopkg.in/yaml.v3"
 	"os"
 )
@@ -13,3 +15,58 @@ func ReadYaml(path string, obj interface{}) error {
 
 	return yaml.Unmarshal(data, obj)
 }
+
+// AppendYamlList appends items to the list at the top level key of the yaml file, the other content of the file
+// is kept. A missing file or key is created.
+func AppendYamlList(path string, key string, items ...interface{}) error {
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("the root of %s is not a mapping", path)
+	}
+
+	var list *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == key {
+			list = root.Content[i+1]
+		}
+	}
+	if list == nil || list.Tag == "!!null" {
+		if list == nil {
+			list = &yaml.Node{}
+			root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, list)
+		}
+		*list = yaml.Node{Kind: yaml.SequenceNode}
+	}
+	if list.Kind != yaml.SequenceNode {
+		return fmt.Errorf("the value of %s in %s is not a list", key, path)
+	}
+
+	for _, item := range items {
+		n := &yaml.Node{}
+		if err = n.Encode(item); err != nil {
+			return err
+		}
+		list.Content = append(list.Content, n)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(&doc); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
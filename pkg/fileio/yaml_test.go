@@ -0,0 +1,43 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type item struct {
+	Name string `yaml:"Name"`
+}
+
+func TestAppendYamlList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("# keep me\nOther: 1\nItems:\n  - Name: a\n"), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if err := AppendYamlList(path, "Items", item{Name: "b"}); err != nil {
+		t.Fatalf("AppendYamlList failed: %v", err)
+	}
+	if err := AppendYamlList(path, "New", item{Name: "c"}); err != nil {
+		t.Fatalf("AppendYamlList failed: %v", err)
+	}
+
+	var res struct {
+		Other int    `yaml:"Other"`
+		Items []item `yaml:"Items"`
+		New   []item `yaml:"New"`
+	}
+	if err := ReadYaml(path, &res); err != nil {
+		t.Fatalf("ReadYaml failed: %v", err)
+	}
+	if res.Other != 1 || len(res.Items) != 2 || res.Items[1].Name != "b" || len(res.New) != 1 {
+		t.Fatalf("Unexpected content: %+v", res)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# keep me") {
+		t.Fatalf("Comment was dropped:\n%s", data)
+	}
+}
@@ -0,0 +1,441 @@
+package frontmatter
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Set writes value to the dotted key. An existing key is replaced in place, a missing key is appended to the end of
+// its table. Documents without front matter get a new TOML block.
+//
+// Supported values: string, bool, integers, floats, time.Time and slices of them. A time keeps the style of the
+// value it replaces, a quoted string or a native datetime, a new one is a TOML datetime or a YAML quoted string.
+func (d *Document) Set(key string, value interface{}) error {
+	parts := strings.Split(key, ".")
+	if len(parts) > 2 {
+		return fmt.Errorf("key %s is nested too deep, only 'table.key' is supported", key)
+	}
+
+	encoded, err := encodeValue(value)
+	if err != nil {
+		return fmt.Errorf("encode value of %s: %w", key, err)
+	}
+
+	if d.Format == FormatNone {
+		d.Format = FormatTOML
+		d.BodyLine = 3
+	}
+
+	if t, ok := value.(time.Time); ok {
+		quoted := d.Format == FormatYAML
+		if current, found := d.rawValue(parts); found {
+			quoted = strings.HasPrefix(current, `"`) || strings.HasPrefix(current, "'")
+		}
+		if !quoted {
+			encoded = t.Format(time.RFC3339)
+		}
+	}
+
+	switch d.Format {
+	case FormatTOML:
+		d.header = setTOML(d.header, parts, encoded)
+	case FormatYAML:
+		d.header = setYAML(d.header, parts, encoded)
+	}
+
+	return nil
+}
+
+// SetIfAbsent writes value only if the key does not exist yet, it reports whether the value was written.
+func (d *Document) SetIfAbsent(key string, value interface{}) (bool, error) {
+	if d.Has(key) {
+		return false, nil
+	}
+
+	return true, d.Set(key, value)
+}
+
+// Delete removes the key from the front matter, it reports whether the key was found.
+func (d *Document) Delete(key string) bool {
+	parts := strings.Split(key, ".")
+
+	start, end, ok := -1, -1, false
+	switch d.Format {
+	case FormatTOML:
+		table, k := splitTableKey(parts)
+		start, end, ok = findTOML(d.header, table, k)
+	case FormatYAML:
+		start, end, ok = findYAML(d.header, parts)
+	}
+
+	if !ok {
+		return false
+	}
+
+	d.header = replaceLines(d.header, start, end)
+	return true
+}
+
+// rawValue returns the value of the key as written on its first line.
+func (d *Document) rawValue(parts []string) (string, bool) {
+	start, separator, ok := -1, "", false
+	switch d.Format {
+	case FormatTOML:
+		table, k := splitTableKey(parts)
+		start, _, ok = findTOML(d.header, table, k)
+		separator = "="
+	case FormatYAML:
+		start, _, ok = findYAML(d.header, parts)
+		separator = ":"
+	}
+	if !ok {
+		return "", false
+	}
+
+	_, value, _ := strings.Cut(d.header[start], separator)
+	return strings.TrimSpace(value), true
+}
+
+// ---------------------- toml
+
+func setTOML(lines []string, parts []string, encoded string) []string {
+	table, key := splitTableKey(parts)
+
+	if start, end, ok := findTOML(lines, table, key); ok {
+		return replaceLines(lines, start, end, keyPrefix(lines[start], "=")+" = "+encoded)
+	}
+
+	if table == "" {
+		return insertLines(lines, lastContentLine(lines, 0, tomlTableStart(lines, 0)), key+" = "+encoded)
+	}
+
+	for i, line := range lines {
+		if tomlTableName(line) == table {
+			return insertLines(lines, lastContentLine(lines, i+1, tomlTableStart(lines, i+1)), key+" = "+encoded)
+		}
+	}
+
+	return append(lines, "["+table+"]", key+" = "+encoded)
+}
+
+// findTOML returns the line range of key in table, an empty table means the root table.
+func findTOML(lines []string, table, key string) (int, int, bool) {
+	current := ""
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if name := tomlTableName(lines[i]); name != "" {
+			current = name
+			continue
+		}
+
+		k, value, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			continue
+		}
+
+		end := tomlValueEnd(lines, i, value)
+		k = unquoteKey(k)
+		if (current == table && k == key) || (current == "" && table != "" && k == table+"."+key) {
+			return i, end, true
+		}
+		i = end
+	}
+
+	return -1, -1, false
+}
+
+// tomlValueEnd returns the last line of a value which starts at line start.
+func tomlValueEnd(lines []string, start int, value string) int {
+	value = strings.TrimSpace(value)
+	for _, quote := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(value, quote) && !strings.Contains(value[len(quote):], quote) {
+			for i := start + 1; i < len(lines); i++ {
+				if strings.Contains(lines[i], quote) {
+					return i
+				}
+			}
+			return len(lines) - 1
+		}
+	}
+
+	depth := bracketDepth(value)
+	i := start
+	for depth > 0 && i+1 < len(lines) {
+		i++
+		depth += bracketDepth(lines[i])
+	}
+
+	return i
+}
+
+// tomlTableStart returns the index of the first table header at or after from.
+func tomlTableStart(lines []string, from int) int {
+	for i := from; i < len(lines); i++ {
+		if tomlTableName(lines[i]) != "" {
+			return i
+		}
+		trimmed := strings.TrimSpace(lines[i])
+		if k, value, ok := strings.Cut(trimmed, "="); ok && !strings.HasPrefix(trimmed, "#") && k != "" {
+			i = tomlValueEnd(lines, i, value)
+		}
+	}
+
+	return len(lines)
+}
+
+func tomlTableName(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "[[") || strings.Contains(trimmed, "=") {
+		return ""
+	}
+
+	end := strings.Index(trimmed, "]")
+	if end < 0 {
+		return ""
+	}
+
+	return unquoteKey(trimmed[1:end])
+}
+
+// ---------------------- yaml
+
+func setYAML(lines []string, parts []string, encoded string) []string {
+	parentStart, parentEnd, ok := findYAML(lines, parts[:1])
+
+	if len(parts) == 1 {
+		if ok {
+			return replaceLines(lines, parentStart, parentEnd, keyPrefix(lines[parentStart], ":")+": "+encoded)
+		}
+		return insertLines(lines, lastContentLine(lines, 0, len(lines)), parts[0]+": "+encoded)
+	}
+
+	if !ok {
+		return insertLines(lines, lastContentLine(lines, 0, len(lines)), parts[0]+":", "  "+parts[1]+": "+encoded)
+	}
+
+	if start, end, found := findYAML(lines, parts); found {
+		return replaceLines(lines, start, end, keyPrefix(lines[start], ":")+": "+encoded)
+	}
+
+	indent := yamlChildIndent(lines, parentStart, parentEnd)
+	if _, inline, _ := strings.Cut(lines[parentStart], ":"); strings.TrimSpace(stripComment(inline)) != "" {
+		// the parent holds a scalar or flow value, it is replaced by a block mapping.
+		return replaceLines(lines, parentStart, parentEnd, keyPrefix(lines[parentStart], ":")+":",
+			indent+parts[1]+": "+encoded)
+	}
+
+	return insertLines(lines, parentEnd+1, indent+parts[1]+": "+encoded)
+}
+
+// findYAML returns the line range of a top level key, or of a key nested one level below it.
+func findYAML(lines []string, parts []string) (int, int, bool) {
+	start, end, ok := findYAMLBlock(lines, 0, len(lines), "", parts[0])
+	if !ok || len(parts) == 1 {
+		return start, end, ok
+	}
+
+	return findYAMLBlock(lines, start+1, end+1, yamlChildIndent(lines, start, end), parts[1])
+}
+
+// findYAMLBlock searches the key with the exact indent in lines[from:to], the block ends before the next line
+// which is indented at most as much as the key.
+func findYAMLBlock(lines []string, from, to int, indent string, key string) (int, int, bool) {
+	for i := from; i < to; i++ {
+		line := lines[i]
+		if !strings.HasPrefix(line, indent) || isIndented(line[len(indent):]) {
+			continue
+		}
+
+		k, _, ok := strings.Cut(line[len(indent):], ":")
+		if !ok || strings.HasPrefix(k, "#") || strings.HasPrefix(k, "-") || unquoteKey(k) != key {
+			continue
+		}
+
+		end := i
+		for j := i + 1; j < to; j++ {
+			next := lines[j]
+			if strings.TrimSpace(next) == "" {
+				continue
+			}
+			rest := strings.TrimPrefix(next, indent)
+			if len(rest) == len(next) && indent != "" {
+				break
+			}
+			if !isIndented(rest) && !strings.HasPrefix(rest, "- ") && rest != "-" {
+				break
+			}
+			end = j
+		}
+
+		return i, end, true
+	}
+
+	return -1, -1, false
+}
+
+// yamlChildIndent returns the indent used by the children of the block, two spaces if it has none.
+func yamlChildIndent(lines []string, start, end int) string {
+	for i := start + 1; i <= end && i < len(lines); i++ {
+		trimmed := strings.TrimLeft(lines[i], " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "-") {
+			continue
+		}
+		if indent := lines[i][:len(lines[i])-len(trimmed)]; indent != "" {
+			return indent
+		}
+	}
+
+	return "  "
+}
+
+// ---------------------- helpers
+
+func splitTableKey(parts []string) (string, string) {
+	if len(parts) == 1 {
+		return "", parts[0]
+	}
+	return parts[0], parts[1]
+}
+
+// keyPrefix returns the key as written in line, so quoting and indent survive a replacement.
+func keyPrefix(line, separator string) string {
+	k, _, _ := strings.Cut(line, separator)
+	return strings.TrimRight(k, " \t")
+}
+
+func unquoteKey(key string) string {
+	key = strings.TrimSpace(key)
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		return key[1 : len(key)-1]
+	}
+	return key
+}
+
+func isIndented(s string) bool {
+	return strings.HasPrefix(s, " ") || strings.HasPrefix(s, "\t")
+}
+
+func stripComment(s string) string {
+	if idx := strings.Index(s, " #"); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
+
+// bracketDepth counts unclosed '[' and '{' outside of quoted strings.
+func bracketDepth(s string) int {
+	depth := 0
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' && quote == '"' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return depth
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// lastContentLine returns the index after the last non-blank line in lines[from:to].
+func lastContentLine(lines []string, from, to int) int {
+	for to > from && strings.TrimSpace(lines[to-1]) == "" {
+		to--
+	}
+	return to
+}
+
+func replaceLines(lines []string, start, end int, newLines ...string) []string {
+	res := append([]string{}, lines[:start]...)
+	res = append(res, newLines...)
+	return append(res, lines[end+1:]...)
+}
+
+func insertLines(lines []string, at int, newLines ...string) []string {
+	res := append([]string{}, lines[:at]...)
+	res = append(res, newLines...)
+	return append(res, lines[at:]...)
+}
+
+// encodeValue encodes value as inline literal, the output is valid in both TOML and YAML.
+func encodeValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", fmt.Errorf("nil value is not supported")
+	case string:
+		return quoteString(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		return quoteString(v.Format(time.RFC3339)), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v), nil
+	case float32, float64:
+		return fmt.Sprintf("%v", v), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return "", fmt.Errorf("unsupported value type %T", value)
+	}
+
+	items := make([]string, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		item, err := encodeValue(rv.Index(i).Interface())
+		if err != nil {
+			return "", err
+		}
+		items = append(items, item)
+	}
+
+	return "[" + strings.Join(items, ", ") + "]", nil
+}
+
+// quoteString quotes s with the escapes shared by TOML basic strings and YAML double-quoted strings.
+func quoteString(s string) string {
+	var builder strings.Builder
+	builder.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			builder.WriteString(`\"`)
+		case '\\':
+			builder.WriteString(`\\`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				builder.WriteString(fmt.Sprintf(`\u%04x`, r))
+			} else {
+				builder.WriteRune(r)
+			}
+		}
+	}
+	builder.WriteByte('"')
+
+	return builder.String()
+}
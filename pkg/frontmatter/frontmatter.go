@@ -0,0 +1,235 @@
+package frontmatter
+
+import (
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strings"
+	"time"
+)
+
+// Parse splits data into the front matter block and the body.
+//
+// A document without a leading '+++' or '---' line is returned with FormatNone and the whole data as body.
+func Parse(data []byte) (*Document, error) {
+	content := string(data)
+	firstLine, rest, _ := strings.Cut(content, "\n")
+
+	format := FormatNone
+	switch strings.TrimRight(firstLine, " \r") {
+	case tomlDelimiter:
+		format = FormatTOML
+	case yamlDelimiter:
+		format = FormatYAML
+	}
+
+	if format == FormatNone {
+		return &Document{Format: FormatNone, Body: content, BodyLine: 1}, nil
+	}
+
+	delimiter := delimiterOf(format)
+	lines := strings.Split(rest, "\n")
+	for i, line := range lines {
+		if strings.TrimRight(line, " \r") != delimiter {
+			continue
+		}
+
+		return &Document{
+			Format:   format,
+			Body:     strings.Join(lines[i+1:], "\n"),
+			BodyLine: i + 3,
+			header:   append([]string{}, lines[:i]...),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("front matter is not closed, missing '%s'", delimiter)
+}
+
+// ReadFile reads and parses the post at path.
+func ReadFile(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+// WriteFile writes the document back to path.
+func (d *Document) WriteFile(path string) error {
+	return os.WriteFile(path, d.Bytes(), 0644)
+}
+
+// Bytes renders the document, front matter block first.
+func (d *Document) Bytes() []byte {
+	if d.Format == FormatNone {
+		return []byte(d.Body)
+	}
+
+	delimiter := delimiterOf(d.Format)
+	var builder strings.Builder
+	builder.WriteString(delimiter + "\n")
+	for _, line := range d.header {
+		builder.WriteString(line + "\n")
+	}
+	builder.WriteString(delimiter + "\n")
+	builder.WriteString(d.Body)
+
+	return []byte(builder.String())
+}
+
+// Values decodes the front matter block.
+func (d *Document) Values() (map[string]interface{}, error) {
+	res := map[string]interface{}{}
+	raw := strings.Join(d.header, "\n")
+
+	switch d.Format {
+	case FormatTOML:
+		if _, err := toml.Decode(raw, &res); err != nil {
+			return nil, fmt.Errorf("decode toml front matter: %w", err)
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal([]byte(raw), &res); err != nil {
+			return nil, fmt.Errorf("decode yaml front matter: %w", err)
+		}
+		if res == nil {
+			res = map[string]interface{}{}
+		}
+	}
+
+	return res, nil
+}
+
+// Get returns the value of a dotted key, the second result reports whether the key exists.
+func (d *Document) Get(key string) (interface{}, bool) {
+	values, err := d.Values()
+	if err != nil {
+		return nil, false
+	}
+
+	var current interface{} = values
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// Has reports whether the key exists in the front matter.
+func (d *Document) Has(key string) bool {
+	_, ok := d.Get(key)
+	return ok
+}
+
+// GetString returns the value of key as string, an empty string is returned if the key is absent.
+func (d *Document) GetString(key string) string {
+	v, ok := d.Get(key)
+	if !ok || v == nil {
+		return ""
+	}
+
+	switch value := v.(type) {
+	case string:
+		return value
+	case time.Time:
+		return value.Format(time.RFC3339)
+	}
+
+	return fmt.Sprintf("%v", v)
+}
+
+// GetStrings returns the value of key as string slice, a scalar value is returned as a one element slice.
+func (d *Document) GetStrings(key string) []string {
+	v, ok := d.Get(key)
+	if !ok || v == nil {
+		return nil
+	}
+
+	switch value := v.(type) {
+	case []string:
+		return value
+	case []interface{}:
+		res := make([]string, 0, len(value))
+		for _, item := range value {
+			res = append(res, fmt.Sprintf("%v", item))
+		}
+		return res
+	}
+
+	return []string{fmt.Sprintf("%v", v)}
+}
+
+// GetBool returns the value of key as bool, the second result is false if the key is absent or not a bool.
+func (d *Document) GetBool(key string) (bool, bool) {
+	v, ok := d.Get(key)
+	if !ok {
+		return false, false
+	}
+
+	switch value := v.(type) {
+	case bool:
+		return value, true
+	case string:
+		return strings.EqualFold(value, "true"), strings.EqualFold(value, "true") || strings.EqualFold(value, "false")
+	}
+
+	return false, false
+}
+
+// GetTime returns the value of key as time, string values are parsed with the layouts Hugo accepts.
+func (d *Document) GetTime(key string) (time.Time, bool) {
+	v, ok := d.Get(key)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	switch value := v.(type) {
+	case time.Time:
+		return value, true
+	case string:
+		return ParseTime(value)
+	}
+
+	return time.Time{}, false
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	time.DateOnly,
+}
+
+// ParseTime parses the date formats commonly used in Hugo front matter.
+func ParseTime(value string) (time.Time, bool) {
+	return ParseTimeIn(value, time.Local)
+}
+
+// ParseTimeIn is ParseTime with the dates without offset in loc.
+func ParseTimeIn(value string, loc *time.Location) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func delimiterOf(format Format) string {
+	if format == FormatYAML {
+		return yamlDelimiter
+	}
+	return tomlDelimiter
+}
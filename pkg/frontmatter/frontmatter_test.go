@@ -0,0 +1,195 @@
+package frontmatter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTOML(t *testing.T) {
+	data := "+++\ntitle = 'Hello'\ntags = [\"a\", \"b\"]\ndraft = true\ndate = 2025-01-02T03:04:05Z\n+++\nbody\n"
+
+	doc, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if doc.Format != FormatTOML {
+		t.Fatalf("Expected toml format, got %q", doc.Format)
+	}
+	if doc.Body != "body\n" {
+		t.Fatalf("Unexpected body: %q", doc.Body)
+	}
+	if doc.BodyLine != 7 {
+		t.Fatalf("Expected body line 7, got %d", doc.BodyLine)
+	}
+	if doc.GetString("title") != "Hello" {
+		t.Fatalf("Unexpected title: %q", doc.GetString("title"))
+	}
+	if tags := doc.GetStrings("tags"); len(tags) != 2 || tags[1] != "b" {
+		t.Fatalf("Unexpected tags: %v", tags)
+	}
+	if draft, ok := doc.GetBool("draft"); !ok || !draft {
+		t.Fatal("Expected draft to be true")
+	}
+	if date, ok := doc.GetTime("date"); !ok || date.Year() != 2025 {
+		t.Fatalf("Unexpected date: %v", date)
+	}
+	if string(doc.Bytes()) != data {
+		t.Fatalf("Round trip changed the document:\n%s", doc.Bytes())
+	}
+}
+
+func TestParseYAMLAndNone(t *testing.T) {
+	doc, err := Parse([]byte("---\ntitle: Hello\ndate: '2025-01-02'\n---\nbody"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if doc.Format != FormatYAML || doc.GetString("title") != "Hello" {
+		t.Fatalf("Unexpected yaml document: %+v", doc)
+	}
+	if date, ok := doc.GetTime("date"); !ok || date.Day() != 2 {
+		t.Fatalf("Unexpected date: %v", date)
+	}
+
+	doc, err = Parse([]byte("just body"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if doc.Format != FormatNone || doc.Body != "just body" {
+		t.Fatalf("Unexpected plain document: %+v", doc)
+	}
+
+	if _, err = Parse([]byte("+++\ntitle = 'x'\n")); err == nil {
+		t.Fatal("Expected error for unclosed front matter")
+	}
+}
+
+func TestSetTOML(t *testing.T) {
+	data := "+++\n# comment\ntitle = 'Hello'\ntags = [\n  \"a\",\n]\n\n[cover]\nimage = 'old.png'\n+++\nbody\n"
+	doc, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	steps := []struct {
+		key   string
+		value interface{}
+	}{
+		{"tags", []string{"x", "y"}},
+		{"draft", false},
+		{"cover.image", "feature.png"},
+		{"cover.alt", "a \"quoted\" alt"},
+		{"params.lang", "zh"},
+	}
+	for _, step := range steps {
+		if err = doc.Set(step.key, step.value); err != nil {
+			t.Fatalf("Set %s failed: %v", step.key, err)
+		}
+	}
+
+	expected := "+++\n# comment\ntitle = 'Hello'\ntags = [\"x\", \"y\"]\ndraft = false\n\n[cover]\n" +
+		"image = \"feature.png\"\nalt = \"a \\\"quoted\\\" alt\"\n[params]\nlang = \"zh\"\n+++\nbody\n"
+	if string(doc.Bytes()) != expected {
+		t.Fatalf("Unexpected document:\n%s\nexpected:\n%s", doc.Bytes(), expected)
+	}
+
+	if doc.GetString("cover.alt") != "a \"quoted\" alt" {
+		t.Fatalf("Unexpected alt: %q", doc.GetString("cover.alt"))
+	}
+}
+
+func TestSetYAML(t *testing.T) {
+	data := "---\ntitle: Hello\ntags:\n  - a\n  - b\ncover:\n  image: old.png\n---\nbody\n"
+	doc, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if err = doc.Set("tags", []string{"c"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err = doc.Set("cover.image", "feature.png"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err = doc.Set("cover.alt", "alt"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err = doc.Set("date", time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	expected := "---\ntitle: Hello\ntags: [\"c\"]\ncover:\n  image: \"feature.png\"\n  alt: \"alt\"\n" +
+		"date: \"2025-01-02T03:04:05Z\"\n---\nbody\n"
+	if string(doc.Bytes()) != expected {
+		t.Fatalf("Unexpected document:\n%s\nexpected:\n%s", doc.Bytes(), expected)
+	}
+}
+
+func TestSetTime(t *testing.T) {
+	at := time.Date(2026, 10, 19, 9, 0, 0, 0, time.FixedZone("", 8*3600))
+	for _, c := range []struct {
+		data     string
+		expected string
+	}{
+		{"+++\ndate = 2024-01-02T03:04:05+08:00\n+++\n", "+++\ndate = 2026-10-19T09:00:00+08:00\n+++\n"},
+		{"+++\ndate = '2024-01-02'\n+++\n", "+++\ndate = \"2026-10-19T09:00:00+08:00\"\n+++\n"},
+		{"+++\ntitle = 'a'\n+++\n", "+++\ntitle = 'a'\ndate = 2026-10-19T09:00:00+08:00\n+++\n"},
+		{"---\ndate: 2024-01-02\n---\n", "---\ndate: 2026-10-19T09:00:00+08:00\n---\n"},
+		{"---\ntitle: a\n---\n", "---\ntitle: a\ndate: \"2026-10-19T09:00:00+08:00\"\n---\n"},
+	} {
+		doc, err := Parse([]byte(c.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = doc.Set("date", at); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if string(doc.Bytes()) != c.expected {
+			t.Fatalf("Unexpected document:\n%s\nexpected:\n%s", doc.Bytes(), c.expected)
+		}
+		if got, ok := doc.GetTime("date"); !ok || !got.Equal(at) {
+			t.Fatalf("Unexpected date %v in:\n%s", got, doc.Bytes())
+		}
+	}
+}
+
+func TestSetIfAbsentAndDelete(t *testing.T) {
+	doc, err := Parse([]byte("no front matter\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	written, err := doc.SetIfAbsent("translationKey", "k")
+	if err != nil || !written {
+		t.Fatalf("Expected key to be written, err: %v", err)
+	}
+	written, err = doc.SetIfAbsent("translationKey", "other")
+	if err != nil || written {
+		t.Fatalf("Expected existing key to be kept, err: %v", err)
+	}
+
+	if string(doc.Bytes()) != "+++\ntranslationKey = \"k\"\n+++\nno front matter\n" {
+		t.Fatalf("Unexpected document:\n%s", doc.Bytes())
+	}
+
+	if !doc.Delete("translationKey") || doc.Has("translationKey") {
+		t.Fatal("Expected key to be deleted")
+	}
+
+	if err = doc.Set("a.b.c", "x"); err == nil {
+		t.Fatal("Expected error for deeply nested key")
+	}
+}
+
+func TestParseTimeIn(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	if got, ok := ParseTimeIn("2026-11-01T09:00", loc); !ok || got.UTC().Hour() != 1 {
+		t.Fatalf("Expected the time in the location, got %s", got)
+	}
+	if got, ok := ParseTimeIn("2026-11-01T09:00:00Z", loc); !ok || got.UTC().Hour() != 9 {
+		t.Fatalf("Expected the offset of the value kept, got %s", got)
+	}
+	if _, ok := ParseTimeIn("tomorrow", loc); ok {
+		t.Fatal("Expected an invalid time to fail")
+	}
+}
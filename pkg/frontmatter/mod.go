@@ -0,0 +1,19 @@
+// Package frontmatter reads and edits the front matter block of Hugo posts.
+//
+// Both the TOML ('+++') and the YAML ('---') front matter formats are supported. Values are decoded with the
+// standard TOML and YAML parsers, but edits are applied line by line on the raw block, so comments, key order and
+// the formatting of untouched keys are kept as they were written.
+//
+// Example:
+//
+//	doc, err := frontmatter.Parse(data)
+//	if err != nil {
+//	    return err
+//	}
+//	if err = doc.Set("cover.image", "feature.png"); err != nil {
+//	    return err
+//	}
+//	os.WriteFile(path, doc.Bytes(), 0644)
+//
+// Keys are dotted paths, and at most one level of nesting ('table.key') is supported.
+package frontmatter
@@ -0,0 +1,30 @@
+package frontmatter
+
+// Format is the encoding of a front matter block.
+type Format string
+
+const (
+	// FormatNone means the document has no front matter block.
+	FormatNone Format = ""
+	// FormatTOML is the '+++' delimited front matter.
+	FormatTOML Format = "toml"
+	// FormatYAML is the '---' delimited front matter.
+	FormatYAML Format = "yaml"
+)
+
+const (
+	tomlDelimiter = "+++"
+	yamlDelimiter = "---"
+)
+
+// Document is a parsed post: the raw front matter lines and the body after it.
+type Document struct {
+	Format Format
+
+	// Body is the content after the closing front matter delimiter.
+	Body string
+	// BodyLine is the 1-based line number of the first body line in the parsed file.
+	BodyLine int
+
+	header []string
+}
@@ -48,6 +48,16 @@ func Println(ctx context.Context, format string, args ...interface{}) {
 	op.Println(fmt.Sprintf(format, args...))
 }
 
+// Write prints s without adding a line break.
+func Write(ctx context.Context, s string) {
+	getOutputter(ctx).Write(s)
+}
+
+func Warn(ctx context.Context, format string, args ...interface{}) {
+	op := getOutputter(ctx)
+	op.Warn(format, args...)
+}
+
 func Err(ctx context.Context, format string, args ...interface{}) {
 	op := getOutputter(ctx)
 	op.Error(format, args...)
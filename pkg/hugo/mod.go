@@ -0,0 +1,13 @@
+// Package hugo reads the layout of the Hugo site hcli runs in.
+//
+// The site root is the nearest directory, from the start path upwards, that holds one of the Hugo config files
+// ('hugo.toml', 'hugo.yaml', 'hugo.yml', 'hugo.json', 'config.toml', 'config.yaml', 'config.yml', 'config.json') or a
+// 'config/_default/' directory. A generic 'config.*' file only counts with a Hugo setting like 'baseURL' or 'title',
+// other tools use these names too. Split config directories follow the Hugo rules: 'hugo.*' and 'config.*' hold the
+// root keys, every other file holds the key named by its base name, like 'languages.toml'.
+//
+// Only the settings hcli needs are read: the content and archetype directories, the languages, the taxonomies, the
+// time zone and the permalinks. Unset settings take the Hugo defaults, 'content', 'archetypes', 'en' and the
+// 'tags'/'categories' taxonomies. The page URLs are resolved from the permalinks like Hugo does for the common
+// tokens, the others are reported as errors.
+package hugo
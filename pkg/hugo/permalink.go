@@ -0,0 +1,155 @@
+package hugo
+
+import (
+	"fmt"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var permalinkToken = regexp.MustCompile(`:[a-z]+`)
+
+// PageURL returns the URL Hugo gives the content file at path with the front matter doc: its 'url', the permalink
+// pattern of its section, or its path in the content dir with the 'slug' as last part. The URL of a page out of the
+// default language is prefixed with the language code, as all of them with defaultContentLanguageInSubdir.
+//
+// An error is returned for a file out of the content dirs and for the permalink tokens which are not supported.
+func (s *Site) PageURL(file string, doc *frontmatter.Document) (string, error) {
+	if u := doc.GetString("url"); u != "" {
+		return "/" + strings.TrimPrefix(u, "/"), nil
+	}
+
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	lang := s.FileLang(abs)
+	rel, err := filepath.Rel(s.contentRoot(abs), abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is not in the content dir", file)
+	}
+
+	rel = filepath.ToSlash(rel)
+	dir, name := path.Dir(rel), strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+	if ext := path.Ext(name); ext != "" && s.HasLanguage(ext[1:]) {
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	prefix := ""
+	if lang != s.DefaultContentLanguage || s.DefaultContentLanguageInSubdir {
+		prefix = "/" + lang
+	}
+
+	switch name {
+	case "_index":
+		return strings.ToLower(prefix + "/" + strings.TrimPrefix(dir+"/", "./")), nil
+	case "index":
+		dir, name = path.Dir(dir), path.Base(dir)
+	}
+	dir = strings.TrimPrefix(dir, ".")
+
+	section, _, _ := strings.Cut(strings.TrimPrefix(dir, "/"), "/")
+	slug := urlize(doc.GetString("slug"))
+	pattern, ok := s.Permalinks[section]
+	if !ok {
+		if slug == "" {
+			slug = urlize(name)
+		}
+		return strings.ToLower(prefix + path.Clean("/"+dir+"/"+slug) + "/"), nil
+	}
+
+	var unknown []string
+	expanded := permalinkToken.ReplaceAllStringFunc(pattern, func(token string) string {
+		v, err := s.permalinkValue(token[1:], doc, dir, name, slug)
+		if err != nil {
+			unknown = append(unknown, err.Error())
+		}
+		return v
+	})
+	if len(unknown) != 0 {
+		return "", fmt.Errorf("can't resolve the permalink %s of %s: %s", pattern, file, strings.Join(unknown, ", "))
+	}
+
+	return strings.ToLower(prefix + "/" + strings.TrimPrefix(expanded, "/")), nil
+}
+
+// permalinkValue returns the value of the permalink token of a page in the section dir, named name.
+func (s *Site) permalinkValue(token string, doc *frontmatter.Document, dir, name, slug string) (string, error) {
+	switch token {
+	case "section":
+		section, _, _ := strings.Cut(strings.TrimPrefix(dir, "/"), "/")
+		return section, nil
+	case "sections":
+		return strings.TrimPrefix(dir, "/"), nil
+	case "title":
+		return urlize(doc.GetString("title")), nil
+	case "slug":
+		if slug == "" {
+			return urlize(doc.GetString("title")), nil
+		}
+		return slug, nil
+	case "filename", "contentbasename":
+		return urlize(name), nil
+	case "slugorfilename", "slugorcontentbasename":
+		if slug == "" {
+			return urlize(name), nil
+		}
+		return slug, nil
+	}
+
+	date, ok := doc.GetTime("date")
+	if !ok {
+		if date, ok = doc.GetTime("publishDate"); !ok {
+			return "", fmt.Errorf(":%s needs a date", token)
+		}
+	}
+	switch token {
+	case "year":
+		return strconv.Itoa(date.Year()), nil
+	case "month":
+		return fmt.Sprintf("%02d", int(date.Month())), nil
+	case "monthname":
+		return date.Month().String(), nil
+	case "day":
+		return fmt.Sprintf("%02d", date.Day()), nil
+	case "weekday":
+		return strconv.Itoa(int(date.Weekday())), nil
+	case "weekdayname":
+		return date.Weekday().String(), nil
+	case "yearday":
+		return fmt.Sprintf("%03d", date.YearDay()), nil
+	}
+
+	return "", fmt.Errorf(":%s is not supported", token)
+}
+
+// contentRoot returns the content dir of the language the file at abs is in.
+func (s *Site) contentRoot(abs string) string {
+	for _, l := range s.Languages {
+		if l.ContentDir == "" {
+			continue
+		}
+		if rel, err := filepath.Rel(l.ContentDir, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			return l.ContentDir
+		}
+	}
+	return s.ContentDir
+}
+
+// urlize makes s a URL path part like Hugo: lower case, spaces as '-', the punctuation removed.
+func urlize(s string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case unicode.IsSpace(r):
+			builder.WriteRune('-')
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_.", r):
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
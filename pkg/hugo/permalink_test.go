@@ -0,0 +1,57 @@
+package hugo
+
+import (
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"path/filepath"
+	"testing"
+)
+
+func TestPageURL(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "hugo.toml"), "defaultContentLanguage = 'zh'\n"+
+		"[permalinks]\nposts = '/:year/:month/:slug/'\n[permalinks.page]\nnotes = '/n/:sections/:filename/'\n"+
+		"[languages.zh]\nweight = 1\n[languages.en]\nweight = 2\n")
+	site, err := FindSite(root)
+	if err != nil {
+		t.Fatalf("FindSite failed: %v", err)
+	}
+
+	dated := "+++\ntitle = 'Hello, World'\ndate = 2024-03-05T10:00:00+08:00\n+++\n"
+	for _, c := range []struct {
+		file string
+		fm   string
+		want string
+	}{
+		{"posts/old/index.md", dated, "/2024/03/hello-world/"},
+		{"posts/old/index.en.md", dated, "/en/2024/03/hello-world/"},
+		{"posts/old.en.md", "+++\ndate = 2024-03-05\nslug = 'My Slug'\n+++\n", "/en/2024/03/my-slug/"},
+		{"notes/go/Tips.md", "", "/n/notes/go/tips/"},
+		{"docs/a b/index.md", "", "/docs/a-b/"},
+		{"docs/old.md", "+++\nslug = 'kept'\n+++\n", "/docs/kept/"},
+		{"docs/_index.md", "", "/docs/"},
+		{"posts/x.md", "+++\nurl = 'custom/path/'\n+++\n", "/custom/path/"},
+	} {
+		doc, err := frontmatter.Parse([]byte(c.fm))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := site.PageURL(filepath.Join(site.ContentDir, c.file), doc)
+		if err != nil || got != c.want {
+			t.Fatalf("PageURL(%s) = %q, %v, want %q", c.file, got, err, c.want)
+		}
+	}
+
+	doc, _ := frontmatter.Parse([]byte("+++\ntitle = 'a'\n+++\n"))
+	if _, err = site.PageURL(filepath.Join(site.ContentDir, "posts", "a.md"), doc); err == nil {
+		t.Fatal("Expected an error for :year without a date")
+	}
+	if _, err = site.PageURL(filepath.Join(root, "a.md"), doc); err == nil {
+		t.Fatal("Expected an error for a file out of the content dir")
+	}
+
+	site.Permalinks["posts"] = "/:year/:unknown/"
+	doc, _ = frontmatter.Parse([]byte(dated))
+	if _, err = site.PageURL(filepath.Join(site.ContentDir, "posts", "a.md"), doc); err == nil {
+		t.Fatal("Expected an error for an unknown token")
+	}
+}
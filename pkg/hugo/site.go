@@ -0,0 +1,380 @@
+package hugo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var configFileNames = []string{
+	"hugo.toml", "hugo.yaml", "hugo.yml", "hugo.json",
+	"config.toml", "config.yaml", "config.yml", "config.json",
+}
+
+// hugoKeys are the settings of which a generic 'config.*' file needs one to be taken for a Hugo config.
+var hugoKeys = []string{
+	"baseURL", "title", "theme", "contentDir", "languageCode", "defaultContentLanguage", "languages",
+	"permalinks", "taxonomies", "markup",
+}
+
+var defaultTaxonomies = map[string]string{
+	"tag":      "tags",
+	"category": "categories",
+}
+
+// ErrSiteNotFound is returned when no Hugo config is found from the start path upwards.
+var ErrSiteNotFound = errors.New("hugo site not found, missing hugo.toml, config.toml or config/_default/")
+
+// FindRoot returns the nearest directory from start upwards which holds a Hugo config, and the config path.
+//
+// The generic 'config.*' file names are used by other tools too, such a file is only taken for a Hugo config if it
+// decodes to a map with a Hugo setting, see hugoKeys. The files skipped are named in the ErrSiteNotFound returned
+// when no site is found.
+func FindRoot(start string) (string, string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", "", err
+	}
+
+	var skipped []string
+	for {
+		configPath, skip := findConfig(dir)
+		if configPath != "" {
+			return dir, configPath, nil
+		}
+		skipped = append(skipped, skip...)
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			if len(skipped) != 0 {
+				return "", "", fmt.Errorf("%w, skipped: %s", ErrSiteNotFound, strings.Join(skipped, "; "))
+			}
+			return "", "", ErrSiteNotFound
+		}
+		dir = parent
+	}
+}
+
+// findConfig returns the Hugo config in dir, and the reasons the generic config files of dir were skipped.
+func findConfig(dir string) (string, []string) {
+	var skipped []string
+	for _, name := range configFileNames {
+		p := filepath.Join(dir, name)
+		if info, err := os.Stat(p); err != nil || info.IsDir() {
+			continue
+		}
+
+		if strings.HasPrefix(name, "config.") {
+			values := map[string]interface{}{}
+			if err := readConfigFile(p, &values); err != nil {
+				skipped = append(skipped, err.Error())
+				continue
+			}
+			if !hasHugoKey(values) {
+				skipped = append(skipped, fmt.Sprintf("%s has none of the Hugo settings %v", p, hugoKeys))
+				continue
+			}
+		}
+		return p, nil
+	}
+
+	p := filepath.Join(dir, "config", "_default")
+	if info, err := os.Stat(p); err == nil && info.IsDir() {
+		return p, nil
+	}
+
+	return "", skipped
+}
+
+// FindSite searches the site root from start upwards and loads it.
+func FindSite(start string) (*Site, error) {
+	root, configPath, err := FindRoot(start)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadSite(root, configPath)
+}
+
+// LoadSite reads the site config at configPath, which is a config file or a split config directory.
+func LoadSite(root, configPath string) (*Site, error) {
+	values := map[string]interface{}{}
+
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		values, err = readConfigDir(configPath)
+	} else {
+		err = readConfigFile(configPath, &values)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	site := &Site{
+		Root:                   root,
+		ConfigPath:             configPath,
+		ContentDir:             filepath.Join(root, stringValue(values, "contentDir", "content")),
+		ArchetypeDir:           filepath.Join(root, stringValue(values, "archetypeDir", "archetypes")),
+		DefaultContentLanguage: stringValue(values, "defaultContentLanguage", "en"),
+		TimeZone:               stringValue(values, "timeZone", ""),
+		Taxonomies:             maps.Clone(defaultTaxonomies),
+		Permalinks:             map[string]string{},
+	}
+	site.DefaultContentLanguageInSubdir, _ = lookup(values, "defaultContentLanguageInSubdir").(bool)
+
+	// the permalinks are '[permalinks] section = pattern', or the page patterns of '[permalinks.page]'.
+	if permalinks, ok := lookup(values, "permalinks").(map[string]interface{}); ok {
+		for section, v := range permalinks {
+			if pattern, ok := v.(string); ok {
+				site.Permalinks[section] = pattern
+			}
+		}
+		if page, ok := lookup(permalinks, "page").(map[string]interface{}); ok {
+			for section, v := range page {
+				if pattern, ok := v.(string); ok {
+					site.Permalinks[section] = pattern
+				}
+			}
+		}
+	}
+
+	if taxonomies, ok := lookup(values, "taxonomies").(map[string]interface{}); ok {
+		site.Taxonomies = map[string]string{}
+		for singular, plural := range taxonomies {
+			site.Taxonomies[singular] = fmt.Sprintf("%v", plural)
+		}
+	}
+
+	if languages, ok := lookup(values, "languages").(map[string]interface{}); ok {
+		for code, v := range languages {
+			l := Language{Code: code}
+			if m, ok := v.(map[string]interface{}); ok {
+				l.Name = stringValue(m, "languageName", "")
+				l.Weight = intValue(m, "weight")
+				if dir := stringValue(m, "contentDir", ""); dir != "" {
+					l.ContentDir = filepath.Join(root, dir)
+				}
+			}
+			site.Languages = append(site.Languages, l)
+		}
+		sort.Slice(site.Languages, func(i, j int) bool {
+			if site.Languages[i].Weight != site.Languages[j].Weight {
+				return site.Languages[i].Weight < site.Languages[j].Weight
+			}
+			return site.Languages[i].Code < site.Languages[j].Code
+		})
+	}
+
+	return site, nil
+}
+
+// Location returns the location of the TimeZone of the site, the local one if the site sets none.
+func (s *Site) Location() (*time.Location, error) {
+	if s.TimeZone == "" {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeZone %s in %s: %w", s.TimeZone, s.ConfigPath, err)
+	}
+	return loc, nil
+}
+
+// ContentPath joins rel with the content directory, an absolute rel is returned as is.
+func (s *Site) ContentPath(rel string) string {
+	if filepath.IsAbs(rel) {
+		return rel
+	}
+	return filepath.Join(s.ContentDir, rel)
+}
+
+// TaxonomyKeys returns the front matter keys of the site taxonomies, sorted.
+func (s *Site) TaxonomyKeys() []string {
+	res := make([]string, 0, len(s.Taxonomies))
+	for _, plural := range s.Taxonomies {
+		res = append(res, plural)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// HasTaxonomy reports whether key is the front matter key of a site taxonomy.
+func (s *Site) HasTaxonomy(key string) bool {
+	for _, plural := range s.Taxonomies {
+		if plural == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTaxonomies returns an error naming the keys which are not taxonomies of the site.
+func (s *Site) ValidateTaxonomies(keys ...string) error {
+	var unknown []string
+	for _, key := range keys {
+		if !s.HasTaxonomy(key) {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) != 0 {
+		return fmt.Errorf("taxonomies %v are not defined in %s, defined: %v", unknown, s.ConfigPath, s.TaxonomyKeys())
+	}
+
+	return nil
+}
+
+// HasLanguage reports whether lang is a language of the site. A site without the 'languages' config only has its
+// default content language.
+func (s *Site) HasLanguage(lang string) bool {
+	if len(s.Languages) == 0 {
+		return lang == s.DefaultContentLanguage
+	}
+
+	for _, l := range s.Languages {
+		if l.Code == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// FileLang returns the language of the content file at path: the language of its file name suffix,
+// 'index.en.md', or of the language content dir it is in, otherwise the default content language.
+func (s *Site) FileLang(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if ext := filepath.Ext(name); ext != "" && s.HasLanguage(ext[1:]) {
+		return ext[1:]
+	}
+
+	abs, err := filepath.Abs(path)
+	if err == nil {
+		for _, l := range s.Languages {
+			if l.ContentDir == "" {
+				continue
+			}
+			if rel, err := filepath.Rel(l.ContentDir, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				return l.Code
+			}
+		}
+	}
+
+	return s.DefaultContentLanguage
+}
+
+func readConfigDir(dir string) (map[string]interface{}, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	for _, entry := range entries {
+		if entry.IsDir() || configFormat(entry.Name()) == "" {
+			continue
+		}
+
+		fileValues := map[string]interface{}{}
+		if err = readConfigFile(filepath.Join(dir, entry.Name()), &fileValues); err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if name == "hugo" || name == "config" {
+			for k, v := range fileValues {
+				values[k] = v
+			}
+		} else {
+			values[name] = fileValues
+		}
+	}
+
+	return values, nil
+}
+
+func readConfigFile(path string, values *map[string]interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch configFormat(path) {
+	case "toml":
+		_, err = toml.Decode(string(data), values)
+	case "yaml":
+		err = yaml.Unmarshal(data, values)
+	case "json":
+		err = json.Unmarshal(data, values)
+	default:
+		err = fmt.Errorf("unsupported config format")
+	}
+
+	if err != nil {
+		return fmt.Errorf("read hugo config %s: %w", path, err)
+	}
+	return nil
+}
+
+func configFormat(path string) string {
+	switch filepath.Ext(path) {
+	case ".toml":
+		return "toml"
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "json"
+	}
+	return ""
+}
+
+func hasHugoKey(values map[string]interface{}) bool {
+	for _, key := range hugoKeys {
+		if lookup(values, key) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns the value of key, Hugo config keys are case-insensitive.
+func lookup(values map[string]interface{}, key string) interface{} {
+	for k, v := range values {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return nil
+}
+
+func stringValue(values map[string]interface{}, key, defaultValue string) string {
+	if v, ok := lookup(values, key).(string); ok && v != "" {
+		return v
+	}
+	return defaultValue
+}
+
+func intValue(values map[string]interface{}, key string) int {
+	switch v := lookup(values, key).(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
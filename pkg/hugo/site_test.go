@@ -0,0 +1,152 @@
+package hugo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+}
+
+func TestFindSiteTOML(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "hugo.toml"), `
+contentDir = "posts"
+defaultContentLanguage = "zh"
+timeZone = "Asia/Shanghai"
+
+[taxonomies]
+tag = "tags"
+series = "series"
+
+[languages.zh]
+weight = 1
+languageName = "中文"
+
+[languages.en]
+weight = 2
+contentDir = "content/en"
+`)
+	sub := filepath.Join(root, "posts", "a")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+
+	site, err := FindSite(sub)
+	if err != nil {
+		t.Fatalf("FindSite failed: %v", err)
+	}
+
+	if site.ContentDir != filepath.Join(root, "posts") {
+		t.Fatalf("Unexpected content dir: %s", site.ContentDir)
+	}
+	if site.ArchetypeDir != filepath.Join(root, "archetypes") {
+		t.Fatalf("Unexpected archetype dir: %s", site.ArchetypeDir)
+	}
+	if loc, err := site.Location(); err != nil || loc.String() != "Asia/Shanghai" {
+		t.Fatalf("Unexpected location: %v, %v", loc, err)
+	}
+	if len(site.Languages) != 2 || site.Languages[0].Code != "zh" || site.Languages[1].ContentDir == "" {
+		t.Fatalf("Unexpected languages: %+v", site.Languages)
+	}
+	if !site.HasLanguage("en") || site.HasLanguage("fr") {
+		t.Fatal("Unexpected language check result")
+	}
+	if err = site.ValidateTaxonomies("tags", "series"); err != nil {
+		t.Fatalf("Expected taxonomies to be valid: %v", err)
+	}
+	if err = site.ValidateTaxonomies("categories"); err == nil {
+		t.Fatal("Expected categories to be rejected")
+	}
+	if p := site.ContentPath("blog"); p != filepath.Join(root, "posts", "blog") {
+		t.Fatalf("Unexpected content path: %s", p)
+	}
+
+	for path, want := range map[string]string{
+		filepath.Join(root, "posts", "a", "index.md"):    "zh",
+		filepath.Join(root, "posts", "a", "index.en.md"): "en",
+		filepath.Join(root, "posts", "b.fr.md"):          "zh",
+		filepath.Join(root, "content", "en", "c.md"):     "en",
+	} {
+		if lang := site.FileLang(path); lang != want {
+			t.Fatalf("Expected language %s of %s, got %s", want, path, lang)
+		}
+	}
+}
+
+func TestFindSiteConfigDir(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "config", "_default", "hugo.yaml"), "title: demo\n")
+	writeFile(t, filepath.Join(root, "config", "_default", "languages.yaml"), "en:\n  weight: 1\n")
+
+	site, err := FindSite(root)
+	if err != nil {
+		t.Fatalf("FindSite failed: %v", err)
+	}
+
+	if site.ContentDir != filepath.Join(root, "content") {
+		t.Fatalf("Unexpected content dir: %s", site.ContentDir)
+	}
+	if len(site.Languages) != 1 || site.Languages[0].Code != "en" || site.Languages[0].Weight != 1 {
+		t.Fatalf("Unexpected languages: %+v", site.Languages)
+	}
+	if !site.HasTaxonomy("tags") || !site.HasTaxonomy("categories") {
+		t.Fatal("Expected the default taxonomies")
+	}
+}
+
+func TestFindSiteNotFound(t *testing.T) {
+	_, err := FindSite(t.TempDir())
+	if err == nil {
+		t.Skip("a hugo site exists above the temp dir")
+	}
+	if !errors.Is(err, ErrSiteNotFound) {
+		t.Fatalf("Expected ErrSiteNotFound, got: %v", err)
+	}
+}
+
+func TestFindSiteSkipsGenericConfig(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "site", "hugo.toml"), "title = 'demo'\n")
+	writeFile(t, filepath.Join(root, "site", "blog", "config.yaml"), "- not\n- hugo\n")
+	writeFile(t, filepath.Join(root, "site", "blog", "config.json"), `{"name": "another tool", "port": 8080}`)
+
+	site, err := FindSite(filepath.Join(root, "site", "blog"))
+	if err != nil {
+		t.Fatalf("FindSite failed: %v", err)
+	}
+	if site.Root != filepath.Join(root, "site") {
+		t.Fatalf("Expected the site above the generic configs, got %s", site.Root)
+	}
+
+	writeFile(t, filepath.Join(root, "site", "blog", "config.toml"), "baseURL = 'https://example.com/'\n")
+	if site, err = FindSite(filepath.Join(root, "site", "blog")); err != nil || filepath.Base(site.Root) != "blog" {
+		t.Fatalf("Expected the generic config with a Hugo setting, got %+v, %v", site, err)
+	}
+
+	// the sites don't share the default taxonomies.
+	site.Taxonomies["series"] = "series"
+	if _, ok := defaultTaxonomies["series"]; ok {
+		t.Fatal("Expected the default taxonomies unchanged")
+	}
+
+	_, err = FindSite(t.TempDir())
+	if err == nil {
+		t.Skip("a hugo site exists above the temp dir")
+	}
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "config.json"), "[1, 2]")
+	if _, err = FindSite(dir); !errors.Is(err, ErrSiteNotFound) || !strings.Contains(err.Error(), "config.json") {
+		t.Fatalf("Expected ErrSiteNotFound naming the skipped file, got: %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package hugo
+
+// Site is the layout of a Hugo site.
+type Site struct {
+	// Root is the site root directory.
+	Root string
+	// ConfigPath is the config file or the 'config/_default' directory the site was read from.
+	ConfigPath string
+
+	// ContentDir is the content directory, joined with Root.
+	ContentDir string
+	// ArchetypeDir is the archetypes directory, joined with Root.
+	ArchetypeDir string
+
+	DefaultContentLanguage string
+	// DefaultContentLanguageInSubdir puts the pages of the default language under its code too, '/en/posts/a/'.
+	DefaultContentLanguageInSubdir bool
+	Languages                      []Language
+
+	// Permalinks maps a section to the URL pattern of its pages, '/:year/:month/:slug/'.
+	Permalinks map[string]string
+
+	// TimeZone is the 'timeZone' of the config, the IANA name the front matter dates without offset are in.
+	TimeZone string
+
+	// Taxonomies maps the singular taxonomy name to its plural, the plural is the front matter key.
+	Taxonomies map[string]string
+}
+
+// Language is a language defined in the 'languages' config of the site.
+type Language struct {
+	Code   string
+	Name   string
+	Weight int
+	// ContentDir is the content directory of the language joined with the site root, empty if the language
+	// shares the site content directory.
+	ContentDir string
+}
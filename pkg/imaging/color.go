@@ -0,0 +1,46 @@
+package imaging
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// ColorNone is the transparent color value of configs.
+const ColorNone = "none"
+
+// ParseColor parses #rgb, #rrggbb and #rrggbbaa, an empty value returns def and 'none' a transparent color.
+func ParseColor(s string, def color.RGBA) (color.RGBA, error) {
+	switch s {
+	case "":
+		return def, nil
+	case ColorNone:
+		return color.RGBA{}, nil
+	}
+
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 8 || err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color '%s', need #rrggbb or #rrggbbaa", s)
+	}
+
+	// the image package works with premultiplied alpha
+	a := uint32(v & 0xff)
+	premultiply := func(c uint32) uint8 {
+		return uint8(c * a / 0xff)
+	}
+	return color.RGBA{
+		R: premultiply(uint32(v >> 24)),
+		G: premultiply(uint32(v>>16) & 0xff),
+		B: premultiply(uint32(v>>8) & 0xff),
+		A: uint8(a),
+	}, nil
+}
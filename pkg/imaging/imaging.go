@@ -0,0 +1,216 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+// Validate checks the values of the config.
+func (c Config) Validate() error {
+	if c.Width < 0 || c.Height < 0 {
+		return fmt.Errorf("invalid poster size %dx%d", c.Width, c.Height)
+	}
+
+	switch c.Crop {
+	case "", CropCenter, CropSmart:
+	default:
+		return fmt.Errorf("invalid crop mode '%s', support: center, smart", c.Crop)
+	}
+
+	switch c.Format {
+	case "", FormatPNG, FormatJPEG, FormatWebP:
+	default:
+		return fmt.Errorf("invalid poster format '%s', support: png, jpeg, webp", c.Format)
+	}
+
+	if c.Quality < 0 || c.Quality > 100 {
+		return fmt.Errorf("invalid poster quality %d, need 1-100", c.Quality)
+	}
+	if c.Quality != 0 && c.Format == FormatWebP {
+		return fmt.Errorf("poster quality %d is not supported by webp, which is encoded lossless, "+
+			"use jpeg to shrink the posters", c.Quality)
+	}
+
+	for _, w := range c.Thumbnails {
+		if w <= 0 {
+			return fmt.Errorf("invalid thumbnail width %d", w)
+		}
+	}
+
+	return nil
+}
+
+// Ext returns the file extension of the configured format.
+func (c Config) Ext() string {
+	switch c.Format {
+	case FormatJPEG:
+		return ".jpg"
+	case FormatWebP:
+		return ".webp"
+	}
+	return ".png"
+}
+
+// Process decodes data, fits it to the configured size, runs the stages and encodes the poster and its thumbnails.
+func Process(data []byte, c Config, stages ...Stage) (*Output, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode poster: %w", err)
+	}
+
+	return ProcessImage(src, c, stages...)
+}
+
+// ProcessImage is Process for a decoded image.
+func ProcessImage(src image.Image, c Config, stages ...Stage) (*Output, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	img := Fit(src, c.Width, c.Height, c.Crop)
+	for _, stage := range stages {
+		if img, err = stage(img); err != nil {
+			return nil, err
+		}
+	}
+
+	out := &Output{
+		Ext:    c.Ext(),
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+	}
+	if out.Data, err = Encode(img, c.Format, c.Quality); err != nil {
+		return nil, err
+	}
+
+	if out.Thumbnails, err = MakeThumbnails(img, c); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// MakeThumbnails encodes the configured thumbnails of a processed poster.
+func MakeThumbnails(img image.Image, c Config) ([]Thumbnail, error) {
+	var res []Thumbnail
+	for _, w := range c.Thumbnails {
+		thumb := Resize(img, w, 0)
+		thumbData, err := Encode(thumb, c.Format, c.Quality)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, Thumbnail{
+			Width:  thumb.Bounds().Dx(),
+			Height: thumb.Bounds().Dy(),
+			Data:   thumbData,
+		})
+	}
+
+	return res, nil
+}
+
+// Fit crops src to the aspect ratio of width x height and resizes it. A zero width or height keeps the source
+// ratio, both zero keeps the source size.
+func Fit(src image.Image, width, height int, crop string) image.Image {
+	if src.Bounds().Dx() == width && src.Bounds().Dy() == height {
+		return src
+	}
+
+	if width == 0 || height == 0 {
+		if width == 0 && height == 0 {
+			return src
+		}
+		return Resize(src, width, height)
+	}
+
+	var rect image.Rectangle
+	if crop == CropSmart {
+		rect = SmartCropRect(src, width, height)
+	} else {
+		rect = CenterCropRect(src.Bounds(), width, height)
+	}
+
+	return Resize(subImage(src, rect), width, height)
+}
+
+// Resize scales src to width x height, a zero width or height is computed from the source ratio.
+func Resize(src image.Image, width, height int) image.Image {
+	b := src.Bounds()
+	if width == 0 {
+		width = max(1, b.Dx()*height/b.Dy())
+	}
+	if height == 0 {
+		height = max(1, b.Dy()*width/b.Dx())
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+
+	return dst
+}
+
+// CenterCropRect returns the largest centered rectangle in bounds with the aspect ratio of width x height.
+func CenterCropRect(bounds image.Rectangle, width, height int) image.Rectangle {
+	w, h := cropSize(bounds, width, height)
+	x := bounds.Min.X + (bounds.Dx()-w)/2
+	y := bounds.Min.Y + (bounds.Dy()-h)/2
+
+	return image.Rect(x, y, x+w, y+h)
+}
+
+// cropSize returns the largest size in bounds with the aspect ratio of width x height.
+func cropSize(bounds image.Rectangle, width, height int) (int, int) {
+	w, h := bounds.Dx(), bounds.Dy()
+	if w*height > h*width {
+		w = h * width / height
+	} else {
+		h = w * height / width
+	}
+
+	return max(1, w), max(1, h)
+}
+
+func subImage(src image.Image, rect image.Rectangle) image.Image {
+	if s, ok := src.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return s.SubImage(rect)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Copy(dst, image.Point{}, src, rect, draw.Src, nil)
+	return dst
+}
+
+// Encode encodes img in format, quality only applies to jpeg.
+func Encode(img image.Image, format string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+
+	switch format {
+	case "", FormatPNG:
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		err = encoder.Encode(&buf, img)
+	case FormatJPEG:
+		if quality == 0 {
+			quality = defaultQuality
+		}
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	case FormatWebP:
+		err = nativewebp.Encode(&buf, img, nil)
+	default:
+		err = fmt.Errorf("unsupported format %s", format)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("encode poster: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,128 @@
+package imaging
+
+import (
+	"bytes"
+	"github.io/uberate/hcli/internal/golden"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// sourceImage is a smooth gradient with a detailed checker block on the right side.
+func sourceImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			c := color.RGBA{R: uint8(x * 255 / 400), G: uint8(y * 255 / 200), B: 128, A: 255}
+			if x >= 290 && x < 370 && y >= 60 && y < 140 && (x/8+y/8)%2 == 0 {
+				c = color.RGBA{A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func sourcePNG(t *testing.T) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, sourceImage()); err != nil {
+		t.Fatalf("encode source failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcessGolden(t *testing.T) {
+	cases := []struct {
+		name   string
+		config Config
+	}{
+		{"center_square", Config{Width: 100, Height: 100, Crop: CropCenter}},
+		{"smart_square", Config{Width: 100, Height: 100, Crop: CropSmart}},
+		{"scale_width", Config{Width: 120}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out, err := Process(sourcePNG(t), c.config)
+			if err != nil {
+				t.Fatalf("Process failed: %v", err)
+			}
+			golden.Encoded(t, c.name, out.Data)
+		})
+	}
+}
+
+func TestSmartCropRect(t *testing.T) {
+	rect := SmartCropRect(sourceImage(), 1, 1)
+	if rect.Dx() != 200 || rect.Dy() != 200 {
+		t.Fatalf("Unexpected crop size: %v", rect)
+	}
+	// the crop must cover the checker block at x 290-370
+	if rect.Min.X > 290 || rect.Max.X < 370 {
+		t.Fatalf("Smart crop missed the detailed region: %v", rect)
+	}
+
+	if center := CenterCropRect(image.Rect(0, 0, 400, 200), 1, 1); center != image.Rect(100, 0, 300, 200) {
+		t.Fatalf("Unexpected center crop: %v", center)
+	}
+}
+
+func TestProcessFormatsAndThumbnails(t *testing.T) {
+	for _, c := range []Config{{Format: FormatJPEG, Quality: 70}, {Format: FormatWebP}} {
+		format := c.Format
+		c.Width, c.Height, c.Thumbnails = 160, 90, []int{80, 40}
+		out, err := Process(sourcePNG(t), c)
+		if err != nil {
+			t.Fatalf("Process %s failed: %v", format, err)
+		}
+
+		img, decodedFormat, err := image.Decode(bytes.NewReader(out.Data))
+		if err != nil {
+			t.Fatalf("decode %s failed: %v", format, err)
+		}
+		if decodedFormat != format || img.Bounds().Dx() != 160 || img.Bounds().Dy() != 90 {
+			t.Fatalf("Unexpected %s output: %s %v", format, decodedFormat, img.Bounds())
+		}
+
+		if len(out.Thumbnails) != 2 || out.Thumbnails[0].Width != 80 || out.Thumbnails[1].Height != 22 {
+			t.Fatalf("Unexpected thumbnails: %+v", out.Thumbnails)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	invalid := []Config{
+		{Width: -1},
+		{Crop: "edge"},
+		{Format: "gif"},
+		{Quality: 101},
+		{Format: FormatWebP, Quality: 80},
+		{Thumbnails: []int{0}},
+	}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Fatalf("Expected %+v to be invalid", c)
+		}
+	}
+
+	if (Config{}).Ext() != ".png" || (Config{Format: FormatJPEG}).Ext() != ".jpg" {
+		t.Fatal("Unexpected extensions")
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	c, err := ParseColor("#ff000080", color.RGBA{})
+	if err != nil || c != (color.RGBA{R: 128, A: 128}) {
+		t.Fatalf("Unexpected color %v, err: %v", c, err)
+	}
+	if c, _ = ParseColor("#fff", color.RGBA{}); c != (color.RGBA{R: 255, G: 255, B: 255, A: 255}) {
+		t.Fatalf("Unexpected color %v", c)
+	}
+	if c, _ = ParseColor(ColorNone, color.RGBA{A: 1}); c.A != 0 {
+		t.Fatalf("Unexpected color %v", c)
+	}
+	if _, err = ParseColor("red", color.RGBA{}); err == nil {
+		t.Fatal("Expected an invalid color to fail")
+	}
+}
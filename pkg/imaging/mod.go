@@ -0,0 +1,13 @@
+// Package imaging post-processes generated posters in pure Go.
+//
+// The pipeline of Process is:
+//  1. Decode the source, png, jpeg and webp are supported.
+//  2. Crop the source to the aspect ratio of the target size, from the center or from the most detailed region
+//     (smart crop).
+//  3. Resize to the target size with the Catmull-Rom filter.
+//  4. Encode as png, jpeg or webp. The webp encoder is lossless, often larger than jpeg for generated art, so
+//     Quality only applies to jpeg and is refused with webp. Use jpeg to shrink the posters.
+//  5. Resize the result to every thumbnail width, keeping the aspect ratio, and encode them in the same format.
+//
+// A zero Config keeps the source size and encodes png.
+package imaging
@@ -0,0 +1,118 @@
+package imaging
+
+import (
+	"golang.org/x/image/draw"
+	"image"
+)
+
+// analysisSize is the longest side of the image the smart crop detects details on.
+const analysisSize = 256
+
+// SmartCropRect returns the largest rectangle in src with the aspect ratio of width x height, placed on the region
+// with the most details. Details are the sum of the luminance gradients, which is high on edges and textures and
+// low on plain backgrounds. Equal regions are resolved to the one closest to the center.
+func SmartCropRect(src image.Image, width, height int) image.Rectangle {
+	b := src.Bounds()
+	w, h := cropSize(b, width, height)
+	if w == b.Dx() && h == b.Dy() {
+		return b
+	}
+
+	scale := float64(analysisSize) / float64(max(b.Dx(), b.Dy()))
+	if scale > 1 {
+		scale = 1
+	}
+	aw, ah := max(1, int(float64(b.Dx())*scale)), max(1, int(float64(b.Dy())*scale))
+	small := image.NewGray(image.Rect(0, 0, aw, ah))
+	draw.ApproxBiLinear.Scale(small, small.Bounds(), src, b, draw.Src, nil)
+
+	energy := gradientEnergy(small)
+	horizontal := w < b.Dx()
+
+	// project the energy on the axis the crop window moves along
+	var profile []float64
+	if horizontal {
+		profile = make([]float64, aw)
+		for y := 0; y < ah; y++ {
+			for x := 0; x < aw; x++ {
+				profile[x] += energy[y*aw+x]
+			}
+		}
+	} else {
+		profile = make([]float64, ah)
+		for y := 0; y < ah; y++ {
+			for x := 0; x < aw; x++ {
+				profile[y] += energy[y*aw+x]
+			}
+		}
+	}
+
+	window := int(float64(w) * scale)
+	full, srcLen := b.Dx(), w
+	if !horizontal {
+		window = int(float64(h) * scale)
+		full, srcLen = b.Dy(), h
+	}
+	offset := bestWindow(profile, max(1, min(window, len(profile))))
+
+	// map the offset back to the source, the window keeps inside the image
+	pos := int(float64(offset) / scale)
+	pos = max(0, min(pos, full-srcLen))
+
+	if horizontal {
+		return image.Rect(b.Min.X+pos, b.Min.Y, b.Min.X+pos+w, b.Min.Y+h)
+	}
+	return image.Rect(b.Min.X, b.Min.Y+pos, b.Min.X+w, b.Min.Y+pos+h)
+}
+
+func gradientEnergy(img *image.Gray) []float64 {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	res := make([]float64, w*h)
+	at := func(x, y int) float64 {
+		x = max(0, min(x, w-1))
+		y = max(0, min(y, h-1))
+		return float64(img.GrayAt(x, y).Y)
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dx := at(x+1, y) - at(x-1, y)
+			dy := at(x, y+1) - at(x, y-1)
+			if dx < 0 {
+				dx = -dx
+			}
+			if dy < 0 {
+				dy = -dy
+			}
+			res[y*w+x] = dx + dy
+		}
+	}
+
+	return res
+}
+
+// bestWindow returns the start of the window with the highest sum, ties go to the window closest to the center.
+func bestWindow(profile []float64, window int) int {
+	prefix := make([]float64, len(profile)+1)
+	for i, v := range profile {
+		prefix[i+1] = prefix[i] + v
+	}
+
+	center := (len(profile) - window) / 2
+	best, bestSum := center, -1.0
+	for start := 0; start+window <= len(profile); start++ {
+		sum := prefix[start+window] - prefix[start]
+		if sum > bestSum || (sum == bestSum && abs(start-center) < abs(best-center)) {
+			best, bestSum = start, sum
+		}
+	}
+
+	return best
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
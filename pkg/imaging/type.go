@@ -0,0 +1,47 @@
+package imaging
+
+import "image"
+
+const (
+	CropCenter = "center"
+	CropSmart  = "smart"
+
+	FormatPNG  = "png"
+	FormatJPEG = "jpeg"
+	FormatWebP = "webp"
+
+	defaultQuality = 85
+)
+
+// Config is the poster post-processing setting of a template.
+type Config struct {
+	Width  int `yaml:"Width" describe:"The poster width in pixels, 0 means keep the source width or scale by Height." default:"0"`
+	Height int `yaml:"Height" describe:"The poster height in pixels, 0 means keep the source height or scale by Width." default:"0"`
+
+	Crop string `yaml:"Crop" describe:"How to crop when the aspect ratio changes, support: center, smart." default:"center"`
+
+	Format  string `yaml:"Format" describe:"The poster format, support: png, jpeg, webp. webp is lossless, use jpeg for small posters." default:"png"`
+	Quality int    `yaml:"Quality" describe:"The jpeg quality, 1-100, an error with webp which is always lossless." default:"85"`
+
+	Thumbnails []int `yaml:"Thumbnails" describe:"The thumbnail widths in pixels, thumbnails keep the poster aspect ratio."`
+}
+
+// Stage changes the poster after it is fitted to the target size and before it is encoded.
+type Stage func(img image.Image) (image.Image, error)
+
+// Output is the processed poster.
+type Output struct {
+	Data []byte
+	// Ext is the file extension of Data, with the leading dot.
+	Ext        string
+	Width      int
+	Height     int
+	Thumbnails []Thumbnail
+}
+
+// Thumbnail is a smaller copy of the poster.
+type Thumbnail struct {
+	Width  int
+	Height int
+	Data   []byte
+}
@@ -0,0 +1,7 @@
+// Package jsonschema generates JSON Schemas from Go types and validates JSON documents against them.
+//
+// Only the subset of JSON Schema needed to describe the answers of the LLM tasks is supported: the types, the
+// properties and required fields of objects, the items of arrays, enums and descriptions. The properties are named
+// by their 'json' tags, fields with 'omitempty' are optional and the 'describe' tag becomes the description, like
+// the config structs of hcli. An 'enum' tag lists the allowed values, separated by commas.
+package jsonschema
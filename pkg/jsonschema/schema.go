@@ -0,0 +1,262 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// For returns the schema of the type of v, v may be a pointer.
+func For(v interface{}) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, errors.New("no schema of nil")
+	}
+	return FromType(t)
+}
+
+// FromType returns the schema of t.
+func FromType(t reflect.Type) (*Schema, error) {
+	return fromType(t, map[reflect.Type]bool{})
+}
+
+func fromType(t reflect.Type, visiting map[reflect.Type]bool) (*Schema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: TypeString}, nil
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return &Schema{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: TypeString}, nil
+	case reflect.Bool:
+		return &Schema{Type: TypeBoolean}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: TypeInteger}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeNumber}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: TypeString}, nil
+		}
+		items, err := fromType(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: TypeArray, Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("no schema of %s, map keys must be strings", t)
+		}
+		values, err := fromType(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: TypeObject, AdditionalProperties: values}, nil
+	case reflect.Struct:
+		if visiting[t] {
+			return nil, fmt.Errorf("no schema of the recursive type %s", t)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		s := &Schema{Type: TypeObject, Properties: map[string]*Schema{}, AdditionalProperties: false}
+		if err := addFields(s, t, visiting); err != nil {
+			return nil, err
+		}
+		sort.Strings(s.Required)
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("no schema of %s", t)
+}
+
+// addFields adds the fields of the struct t to s, the fields of embedded structs without a name are flattened.
+func addFields(s *Schema, t reflect.Type, visiting map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := addFields(s, ft, visiting); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		p, err := fromType(f.Type, visiting)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		p.Description = f.Tag.Get("describe")
+		if enum := f.Tag.Get("enum"); enum != "" {
+			p.Enum = strings.Split(enum, ",")
+		}
+
+		s.Properties[name] = p
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the JSON document data against s, the error names the path of the first invalid value.
+func (s *Schema) Validate(data []byte) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if d.More() {
+		return errors.New("invalid JSON: more than one value")
+	}
+
+	return s.validate("$", v)
+}
+
+func (s *Schema) validate(path string, v interface{}) error {
+	switch s.Type {
+	case "":
+		return nil
+	case TypeObject:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return typeError(path, s.Type, v)
+		}
+		return s.validateObject(path, obj)
+	case TypeArray:
+		arr, ok := v.([]interface{})
+		if !ok {
+			return typeError(path, s.Type, v)
+		}
+		for i, item := range arr {
+			if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case TypeString:
+		str, ok := v.(string)
+		if !ok {
+			return typeError(path, s.Type, v)
+		}
+		if len(s.Enum) != 0 && !slices.Contains(s.Enum, str) {
+			return fmt.Errorf("%s: '%s' is not one of %s", path, str, strings.Join(s.Enum, ", "))
+		}
+		return nil
+	case TypeInteger, TypeNumber:
+		n, ok := v.(json.Number)
+		if !ok {
+			return typeError(path, s.Type, v)
+		}
+		if _, err := n.Int64(); s.Type == TypeInteger && err != nil {
+			return fmt.Errorf("%s: expected integer, got %s", path, n)
+		}
+		return nil
+	case TypeBoolean:
+		if _, ok := v.(bool); !ok {
+			return typeError(path, s.Type, v)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%s: unknown schema type %s", path, s.Type)
+}
+
+func (s *Schema) validateObject(path string, obj map[string]interface{}) error {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			return fmt.Errorf("%s: missing required property '%s'", path, name)
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p, ok := s.Properties[name]
+		if !ok {
+			switch extra := s.AdditionalProperties.(type) {
+			case *Schema:
+				p = extra
+			case bool:
+				if !extra {
+					return fmt.Errorf("%s: unknown property '%s'", path, name)
+				}
+				continue
+			default:
+				continue
+			}
+		}
+		if err := p.validate(path+"."+name, obj[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func typeError(path, want string, v interface{}) error {
+	return fmt.Errorf("%s: expected %s, got %s", path, want, typeName(v))
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return TypeObject
+	case []interface{}:
+		return TypeArray
+	case string:
+		return TypeString
+	case json.Number:
+		return TypeNumber
+	case bool:
+		return TypeBoolean
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,98 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type base struct {
+	ID string `json:"id"`
+}
+
+type answer struct {
+	base
+	Title   string            `json:"title" describe:"The title"`
+	Tags    []string          `json:"tags,omitempty"`
+	Score   int               `json:"score"`
+	Ratio   float64           `json:"ratio,omitempty"`
+	Draft   bool              `json:"draft"`
+	Kind    string            `json:"kind" enum:"post,page"`
+	Extra   map[string]int    `json:"extra,omitempty"`
+	Date    time.Time         `json:"date,omitempty"`
+	Nested  *struct{ A bool } `json:"nested,omitempty"`
+	Ignored string            `json:"-"`
+	hidden  string
+}
+
+func TestFor(t *testing.T) {
+	s, err := For(&answer{})
+	if err != nil {
+		t.Fatalf("For failed: %v", err)
+	}
+
+	if s.Type != TypeObject || s.AdditionalProperties != false {
+		t.Fatalf("Unexpected root: %+v", s)
+	}
+	if got := strings.Join(s.Required, ","); got != "draft,id,kind,score,title" {
+		t.Fatalf("Unexpected required: %s", got)
+	}
+	if len(s.Properties) != 10 {
+		t.Fatalf("Unexpected properties: %d", len(s.Properties))
+	}
+	if p := s.Properties["title"]; p.Type != TypeString || p.Description != "The title" {
+		t.Fatalf("Unexpected title: %+v", p)
+	}
+	if p := s.Properties["tags"]; p.Type != TypeArray || p.Items.Type != TypeString {
+		t.Fatalf("Unexpected tags: %+v", p)
+	}
+	if p := s.Properties["date"]; p.Type != TypeString {
+		t.Fatalf("Expected a text marshaler as string: %+v", p)
+	}
+	if p := s.Properties["nested"]; p.Properties["A"].Type != TypeBoolean {
+		t.Fatalf("Unexpected nested: %+v", p)
+	}
+
+	if _, err = json.Marshal(s); err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	type node struct {
+		Next *node `json:"next"`
+	}
+	if _, err = For(node{}); err == nil {
+		t.Fatal("Expected recursive types to fail")
+	}
+	if _, err = For(map[int]string{}); err == nil {
+		t.Fatal("Expected maps with non-string keys to fail")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s, err := For(answer{})
+	if err != nil {
+		t.Fatalf("For failed: %v", err)
+	}
+
+	valid := `{"id":"1","title":"t","tags":["a"],"score":3,"ratio":0.5,"draft":false,"kind":"post","extra":{"a":1}}`
+	if err = s.Validate([]byte(valid)); err != nil {
+		t.Fatalf("Expected valid, got %v", err)
+	}
+
+	for doc, want := range map[string]string{
+		`{"id":"1","title":"t","score":3,"draft":false}`:                                 "missing required property 'kind'",
+		`{"id":"1","title":"t","score":3.5,"draft":false,"kind":"post"}`:                 "$.score: expected integer",
+		`{"id":"1","title":"t","score":3,"draft":false,"kind":"blog"}`:                   "$.kind: 'blog' is not one of",
+		`{"id":"1","title":"t","score":3,"draft":false,"kind":"post","tags":[1]}`:        "$.tags[0]: expected string, got number",
+		`{"id":"1","title":"t","score":3,"draft":false,"kind":"post","x":1}`:             "unknown property 'x'",
+		`{"id":"1","title":"t","score":3,"draft":false,"kind":"post","extra":{"a":"b"}}`: "$.extra.a: expected integer",
+		`[]`:          "$: expected object, got array",
+		`{"id":`:      "invalid JSON",
+		`{"id":1} {}`: "more than one value",
+	} {
+		if err = s.Validate([]byte(doc)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("Expected %q for %s, got %v", want, doc, err)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package jsonschema
+
+// The JSON types of a schema.
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+)
+
+// Schema is a JSON Schema. A Schema without Type accepts any value.
+type Schema struct {
+	Type        string             `json:"type,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	// AdditionalProperties is false for the objects of structs, or the schema of the values of maps.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+}
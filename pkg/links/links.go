@@ -0,0 +1,347 @@
+package links
+
+import (
+	"context"
+	"fmt"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/markdown"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	refPattern       = regexp.MustCompile(`\{\{[<%]\s*(?:rel)?ref\s+(?:"([^"]*)"|(\S+?))\s*[>%]\}\}`)
+	linkPattern      = regexp.MustCompile(`\]\(\s*(<[^>]*>|[^)\s]+)`)
+	referencePattern = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s*(<[^>]*>|\S+)`)
+	schemePattern    = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+	idPattern        = regexp.MustCompile(`\b(?:id|name)\s*=\s*["']([^"']+)["']`)
+)
+
+// Extract returns the links of the post data at path, the links in code are skipped.
+func Extract(path string, data []byte) ([]Link, error) {
+	doc, err := frontmatter.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	code, _ := markdown.CodeBlocks(lines, doc.BodyLine-1)
+
+	var res []Link
+	add := func(i, offset, targetOffset int, kind, target string) {
+		res = append(res, Link{
+			File:   path,
+			Line:   i + 1,
+			Col:    utf8.RuneCountInString(lines[i][:offset]) + 1,
+			Kind:   kind,
+			Target: target,
+			Offset: targetOffset,
+		})
+	}
+
+	for i := doc.BodyLine - 1; i < len(lines); i++ {
+		if code[i] {
+			continue
+		}
+		text := markdown.MaskInlineCode(lines[i])
+
+		for _, m := range refPattern.FindAllStringSubmatchIndex(text, -1) {
+			if m[2] >= 0 {
+				add(i, m[0], m[2], KindRef, text[m[2]:m[3]])
+			} else {
+				add(i, m[0], m[4], KindRef, text[m[4]:m[5]])
+			}
+		}
+
+		var targets [][]int
+		targets = append(targets, linkPattern.FindAllStringSubmatchIndex(text, -1)...)
+		if m := referencePattern.FindStringSubmatchIndex(text); m != nil {
+			targets = append(targets, m)
+		}
+		for _, m := range targets {
+			offset, target := m[2], text[m[2]:m[3]]
+			if strings.HasPrefix(target, "<") {
+				offset, target = offset+1, strings.TrimSuffix(target[1:], ">")
+			}
+			if kind := kindOf(target); kind != "" {
+				add(i, m[2], offset, kind, target)
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Line != res[j].Line {
+			return res[i].Line < res[j].Line
+		}
+		return res[i].Col < res[j].Col
+	})
+	return res, nil
+}
+
+// kindOf returns the kind of the link target, empty for the targets which aren't checked: the shortcodes, the
+// protocol relative URLs and the schemes other than http and https.
+func kindOf(target string) string {
+	switch {
+	case target == "" || strings.Contains(target, "{{") || strings.HasPrefix(target, "//"):
+		return ""
+	case strings.HasPrefix(target, "#"):
+		return KindAnchor
+	case strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://"):
+		return KindExternal
+	case schemePattern.MatchString(target):
+		return ""
+	}
+	return KindRelative
+}
+
+// Check returns the broken links of the files, in the order of the files.
+func Check(ctx context.Context, args CheckArgs) ([]Broken, error) {
+	c := &checker{args: args, anchors: map[string]map[string]bool{}}
+
+	var res []Broken
+	var external []Link
+	for _, f := range args.Files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		links, err := Extract(f, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+
+		for _, l := range links {
+			if l.Kind == KindExternal {
+				external = append(external, l)
+				continue
+			}
+			if reason := c.check(l); reason != "" {
+				res = append(res, Broken{Link: l, Reason: reason})
+			}
+		}
+	}
+
+	if args.External && len(external) != 0 {
+		res = append(res, checkExternal(ctx, args, external)...)
+		sort.SliceStable(res, func(i, j int) bool {
+			a, b := res[i], res[j]
+			if a.File != b.File {
+				return slices.Index(args.Files, a.File) < slices.Index(args.Files, b.File)
+			}
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Col < b.Col
+		})
+	}
+
+	return res, nil
+}
+
+// checker resolves the internal links, the anchors of the posts are read once.
+type checker struct {
+	args    CheckArgs
+	anchors map[string]map[string]bool
+}
+
+// check returns why the internal link l is broken, empty if it isn't.
+func (c *checker) check(l Link) string {
+	target, fragment, _ := strings.Cut(l.Target, "#")
+	target, _, _ = strings.Cut(target, "?")
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+
+	page := l.File
+	if target != "" {
+		dir := filepath.Dir(l.File)
+		var bases []string
+		switch {
+		case l.Kind == KindRef && strings.HasPrefix(target, "/"):
+			if c.args.ContentDir == "" {
+				return "no Hugo site to resolve the ref from"
+			}
+			bases = []string{filepath.Join(c.args.ContentDir, target)}
+		case l.Kind == KindRef:
+			bases = []string{filepath.Join(dir, target)}
+			if c.args.ContentDir != "" {
+				bases = append(bases, filepath.Join(c.args.ContentDir, target))
+			}
+		case strings.HasPrefix(target, "/"):
+			if c.args.ContentDir == "" {
+				return ""
+			}
+			bases = []string{filepath.Join(c.args.ContentDir, target)}
+			if c.args.StaticDir != "" {
+				bases = append(bases, filepath.Join(c.args.StaticDir, target))
+			}
+		default:
+			bases = []string{filepath.Join(dir, target)}
+		}
+
+		page = ""
+		for _, base := range bases {
+			if p, ok := resolve(base); ok {
+				page = p
+				break
+			}
+		}
+		if page == "" {
+			if l.Kind == KindRef {
+				return "no such page"
+			}
+			return "no such file"
+		}
+	}
+
+	if fragment == "" || !strings.EqualFold(filepath.Ext(page), ".md") {
+		return ""
+	}
+	anchors, err := c.anchorsOf(page)
+	if err != nil {
+		return err.Error()
+	}
+	if !anchors[fragment] {
+		return fmt.Sprintf("no heading '#%s' in %s", fragment, page)
+	}
+	return ""
+}
+
+// resolve returns the file a link to base leads to: base itself, the Markdown file of the page base, or the index
+// of the bundle dir base. The second result is false if none exists.
+func resolve(base string) (string, bool) {
+	if info, err := os.Stat(base); err == nil {
+		if !info.IsDir() {
+			return base, true
+		}
+		for _, index := range []string{"index.md", "_index.md"} {
+			if p := filepath.Join(base, index); isFile(p) {
+				return p, true
+			}
+		}
+		return base, true
+	}
+
+	if p := strings.TrimSuffix(base, string(filepath.Separator)) + ".md"; isFile(p) {
+		return p, true
+	}
+	return "", false
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// anchorsOf returns the anchors of the post at path: the ids of the headings, with the '-1', '-2' suffixes Hugo
+// adds to the duplicates, and the ids of the HTML elements.
+func (c *checker) anchorsOf(path string) (map[string]bool, error) {
+	if anchors, ok := c.anchors[path]; ok {
+		return anchors, nil
+	}
+
+	doc, err := frontmatter.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	anchors := map[string]bool{}
+	lines := strings.Split(doc.Body, "\n")
+	for _, h := range markdown.Headings(lines, 0) {
+		anchor := h.Anchor()
+		for n := 1; anchors[anchor]; n++ {
+			anchor = fmt.Sprintf("%s-%d", h.Anchor(), n)
+		}
+		anchors[anchor] = true
+	}
+	for _, m := range idPattern.FindAllStringSubmatch(doc.Body, -1) {
+		anchors[m[1]] = true
+	}
+
+	c.anchors[path] = anchors
+	return anchors, nil
+}
+
+// checkExternal requests every URL of the links once, with HEAD, or GET if the server doesn't serve HEAD.
+func checkExternal(ctx context.Context, args CheckArgs, links []Link) []Broken {
+	client := args.Client
+	if client == nil {
+		timeout := args.Timeout
+		if timeout == 0 {
+			timeout = DefaultTimeout
+		}
+		client = &http.Client{Timeout: timeout}
+	}
+	concurrency := args.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	urls := map[string]string{}
+	for _, l := range links {
+		urls[l.Target] = ""
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for u := range urls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(u string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			reason := request(ctx, client, u)
+			mu.Lock()
+			urls[u] = reason
+			mu.Unlock()
+		}(u)
+	}
+	wg.Wait()
+
+	var res []Broken
+	for _, l := range links {
+		if reason := urls[l.Target]; reason != "" {
+			res = append(res, Broken{Link: l, Reason: reason})
+		}
+	}
+	return res
+}
+
+// request returns why the URL u is broken, empty if it isn't.
+func request(ctx context.Context, client *http.Client, u string) string {
+	status := 0
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, u, nil)
+		if err != nil {
+			return err.Error()
+		}
+		req.Header.Set("User-Agent", "hcli-link-checker")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err.Error()
+		}
+		resp.Body.Close()
+
+		status = resp.StatusCode
+		if status != http.StatusMethodNotAllowed && status != http.StatusNotImplemented &&
+			status != http.StatusForbidden {
+			break
+		}
+	}
+
+	if status >= http.StatusBadRequest {
+		return fmt.Sprintf("HTTP %d", status)
+	}
+	return ""
+}
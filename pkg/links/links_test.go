@@ -0,0 +1,125 @@
+package links
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSite writes the files of the map under a temp dir and returns the dir.
+func writeSite(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestExtract(t *testing.T) {
+	content := "+++\ntitle = 'a'\n+++\n中文 [a]({{< ref \"b.md#x\" >}}) {{% relref b %}} ![](feature.png)\n" +
+		"`[c](no.md)` [d](mailto:a@b.c) [e](https://x.io) [f](#top)\n```\n[g](no.md)\n```\n[h]: <../b/>\n"
+
+	links, err := Extract("a.md", []byte(content))
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var got []string
+	for _, l := range links {
+		got = append(got, strings.Join([]string{l.Kind, l.Target}, " "))
+	}
+	want := "ref b.md#x,ref b,relative feature.png,external https://x.io,anchor #top,relative ../b/"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("Unexpected links:\n%s\nwant:\n%s", strings.Join(got, ","), want)
+	}
+	if links[0].Line != 4 || links[0].Col != 8 || links[2].Col != 51 || links[5].Offset != 6 ||
+		links[0].Offset != len("中文 [a]({{< ref \"") {
+		t.Fatalf("Unexpected positions: %+v", links)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	root := writeSite(t, map[string]string{
+		"content/posts/a/index.md": "+++\n+++\n## Intro\n" +
+			"[1]({{< ref \"/posts/b\" >}}) [2]({{< relref \"../b.md#setup-go\" >}}) [3]({{< ref \"nope\" >}})\n" +
+			"![4](feature.png) ![5](missing.png) [6](#intro) [7](#nope) [8](../b/#intro-1)\n" +
+			"[9](/images/logo.png) [10](/posts/c/) [11]({{< ref \"/posts/b.md#nope\" >}})\n",
+		"content/posts/a/feature.png": "",
+		"content/posts/b.md":          "---\ntitle: b\n---\n# Setup Go\n## Intro\n## Intro\n",
+		"static/images/logo.png":      "",
+	})
+	post := filepath.Join(root, "content", "posts", "a", "index.md")
+
+	broken, err := Check(context.Background(), CheckArgs{
+		Files:      []string{post},
+		ContentDir: filepath.Join(root, "content"),
+		StaticDir:  filepath.Join(root, "static"),
+	})
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+
+	var got []string
+	for _, b := range broken {
+		got = append(got, b.Target)
+	}
+	if strings.Join(got, ",") != "nope,missing.png,#nope,/posts/c/,/posts/b.md#nope" {
+		t.Fatalf("Unexpected broken links: %v", broken)
+	}
+	if s := broken[0].String(); !strings.HasSuffix(s, "index.md:4:72: broken ref link 'nope': no such page") {
+		t.Fatalf("Unexpected format: %s", s)
+	}
+
+	// without a site the site absolute links aren't checked, and the absolute refs can't be resolved
+	broken, _ = Check(context.Background(), CheckArgs{Files: []string{post}})
+	if len(broken) != 5 {
+		t.Fatalf("Unexpected broken links without a site: %v", broken)
+	}
+}
+
+func TestCheckExternal(t *testing.T) {
+	var heads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				heads++
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	root := writeSite(t, map[string]string{
+		"a.md": "[a](" + server.URL + "/ok) [b](" + server.URL + "/get-only) [c](" + server.URL + "/gone)\n" +
+			"[d](" + server.URL + "/gone)\n",
+	})
+	args := CheckArgs{Files: []string{filepath.Join(root, "a.md")}, Client: server.Client()}
+
+	if broken, _ := Check(context.Background(), args); len(broken) != 0 {
+		t.Fatalf("Expected the external links unchecked, got %v", broken)
+	}
+
+	args.External = true
+	broken, err := Check(context.Background(), args)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if len(broken) != 2 || broken[0].Reason != "HTTP 404" || broken[1].Line != 2 || heads != 1 {
+		t.Fatalf("Unexpected broken links: %v, %d heads", broken, heads)
+	}
+}
@@ -0,0 +1,17 @@
+// Package links finds the broken links of Hugo posts: the ref and relref shortcodes, the relative links and
+// images, the page bundle resources, the anchors to headings and, on demand, the external URLs.
+//
+// A ref is resolved the way Hugo does it, relative to the page first and then to the content dir, and a link to a
+// page may name its file, its bundle dir or its URL dir. The anchors are checked against the ids Hugo generates
+// for the headings of the target page.
+//
+// Example:
+//
+//	broken, err := links.Check(ctx, links.CheckArgs{Files: files, ContentDir: site.ContentDir})
+//	if err != nil {
+//	    return err
+//	}
+//	for _, b := range broken {
+//	    fmt.Println(b)
+//	}
+package links
@@ -0,0 +1,65 @@
+package links
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// The kinds of links.
+const (
+	// KindRef is a '{{< ref >}}' or '{{< relref >}}' shortcode.
+	KindRef = "ref"
+	// KindRelative is a link or an image to a file of the site, relative to the post or to the site root.
+	KindRelative = "relative"
+	// KindAnchor is a link to a heading of the post, '#id'.
+	KindAnchor = "anchor"
+	// KindExternal is an http or https URL.
+	KindExternal = "external"
+)
+
+const (
+	DefaultTimeout     = 10 * time.Second
+	DefaultConcurrency = 8
+)
+
+// Link is a link of a post.
+type Link struct {
+	File string `json:"file"`
+	// Line and Col are 1-based, Col counts characters.
+	Line   int    `json:"line"`
+	Col    int    `json:"col"`
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+	// Offset is the byte offset of Target in the line, the line without its line break.
+	Offset int `json:"-"`
+}
+
+// Broken is a link whose target is missing.
+type Broken struct {
+	Link
+	Reason string `json:"reason"`
+}
+
+// String formats the broken link as 'file:line:col: broken kind link 'target': reason'.
+func (b Broken) String() string {
+	return fmt.Sprintf("%s:%d:%d: broken %s link '%s': %s", b.File, b.Line, b.Col, b.Kind, b.Target, b.Reason)
+}
+
+type CheckArgs struct {
+	// Files are the posts to check.
+	Files []string
+	// ContentDir is the content dir of the Hugo site, the refs and the site absolute links resolve from it.
+	// Empty means the refs resolve relative to the post only, and the site absolute links aren't checked.
+	ContentDir string
+	// StaticDir is the static dir of the Hugo site, the site absolute links may resolve from it too.
+	StaticDir string
+
+	// External checks the external URLs with HTTP requests.
+	External bool
+	// Client sends the requests, nil means a client with Timeout.
+	Client  *http.Client
+	Timeout time.Duration
+	// Concurrency is the number of requests sent at once, zero means DefaultConcurrency.
+	Concurrency int
+}
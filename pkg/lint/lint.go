@@ -0,0 +1,106 @@
+package lint
+
+import (
+	"fmt"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/markdown"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validate checks the disabled rules are known.
+func (c Config) Validate() error {
+	for _, name := range c.Disabled {
+		if _, ok := RuleByName(name); !ok {
+			return fmt.Errorf("unknown lint rule '%s'", name)
+		}
+	}
+	return nil
+}
+
+// RuleByName returns the rule named name, the second result is false if there is no such rule.
+func RuleByName(name string) (Rule, bool) {
+	for _, r := range Rules {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// File lints the post at path.
+func File(path string, c Config) ([]Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Lint(path, data, c)
+}
+
+// Lint lints data, the content of the post at path. The relative links are resolved from the dir of path. The
+// issues are sorted by their position, a front matter which can't be parsed is the only issue.
+func Lint(path string, data []byte, c Config) ([]Issue, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	doc, err := frontmatter.Parse(data)
+	if err != nil {
+		return []Issue{{File: path, Line: 1, Col: 1, Rule: RuleFrontMatter, Message: err.Error()}}, nil
+	}
+	p := newPost(path, data, doc)
+
+	var res []Issue
+	for _, r := range Rules {
+		if slices.Contains(c.Disabled, r.Name) {
+			continue
+		}
+		for _, issue := range r.Check(p, c) {
+			issue.File = path
+			issue.Rule = r.Name
+			res = append(res, issue)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Line != res[j].Line {
+			return res[i].Line < res[j].Line
+		}
+		return res[i].Col < res[j].Col
+	})
+	return res, nil
+}
+
+func newPost(path string, data []byte, doc *frontmatter.Document) *Post {
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	p := &Post{Path: path, Doc: doc, Lines: strings.Split(content, "\n")}
+	p.code, p.fences = markdown.CodeBlocks(p.Lines, doc.BodyLine-1)
+	return p
+}
+
+// body calls fn with the index and the text of the body lines out of the code blocks. The inline code of the
+// text is masked with spaces, the byte offsets are kept.
+func (p *Post) body(fn func(i int, text string)) {
+	for i := p.Doc.BodyLine - 1; i < len(p.Lines); i++ {
+		if !p.code[i] {
+			fn(i, markdown.MaskInlineCode(p.Lines[i]))
+		}
+	}
+}
+
+// issue returns the issue at the byte offset of the line index i.
+func (p *Post) issue(i, offset int, format string, args ...interface{}) Issue {
+	line := p.Lines[i]
+	if offset > len(line) {
+		offset = len(line)
+	}
+	return Issue{
+		Line:    i + 1,
+		Col:     utf8.RuneCountInString(line[:offset]) + 1,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
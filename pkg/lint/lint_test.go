@@ -0,0 +1,75 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// lintString lints content as the post 'index.md' of a temp dir, and returns the issues as 'line:col rule'.
+func lintString(t *testing.T, content string, c Config) []string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "index.md")
+	issues, err := Lint(path, []byte(content), c)
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+
+	var res []string
+	for _, issue := range issues {
+		if issue.File != path {
+			t.Fatalf("Unexpected file of %+v", issue)
+		}
+		res = append(res, fmt.Sprintf("%d:%d %s", issue.Line, issue.Col, issue.Rule))
+	}
+	return res
+}
+
+func TestLintSortsAndDisables(t *testing.T) {
+	content := "+++\ntitle = 'post'\n+++\n\n# Title \n```\ncode\n```\n"
+
+	got := strings.Join(lintString(t, content, Config{RequiredFrontMatter: []string{"title", "date"}}), ",")
+	want := "1:1 required-front-matter,5:1 no-body-h1,5:8 no-trailing-whitespace,6:1 fenced-code-language"
+	if got != want {
+		t.Fatalf("Unexpected issues:\n%s\nwant:\n%s", got, want)
+	}
+
+	c := Config{Disabled: []string{RuleNoBodyH1, RuleNoTrailingWhitespace, RuleFencedCodeLanguage}}
+	if got := lintString(t, content, c); len(got) != 0 {
+		t.Fatalf("Expected the rules disabled, got %v", got)
+	}
+
+	if _, err := Lint("a.md", []byte(content), Config{Disabled: []string{"no-such-rule"}}); err == nil {
+		t.Fatal("Expected an unknown rule to fail")
+	}
+
+	if got := lintString(t, "+++\ntitle = 'post'\n", Config{}); strings.Join(got, ",") != "1:1 front-matter" {
+		t.Fatalf("Expected the unclosed front matter reported, got %v", got)
+	}
+}
+
+func TestCodeBlocks(t *testing.T) {
+	content := "# A\n\n~~~~go\n### in code\n```\n~~~~\n\n    # indented\n\n### B `![](x.png)`\n"
+
+	got := strings.Join(lintString(t, content, Config{}), ",")
+	if got != "10:1 heading-increment" {
+		t.Fatalf("Expected the code ignored, got %s", got)
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "post.md")
+	if err := os.WriteFile(path, []byte("![](missing.png)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := File(path, Config{})
+	if err != nil || len(issues) != 2 {
+		t.Fatalf("Expected the alt and link issues, got %v, %v", issues, err)
+	}
+	if s := issues[0].String(); s != path+":1:1: image without alt text (image-alt)" {
+		t.Fatalf("Unexpected issue format: %s", s)
+	}
+}
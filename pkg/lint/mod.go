@@ -0,0 +1,17 @@
+// Package lint checks Markdown posts offline with Hugo aware rules: the front matter, the headings, the code
+// fences, the images, the relative links and the shortcodes of a post.
+//
+// Every rule reports the issues it finds with the line and the column in the file, front matter included, so the
+// output can be read by editors and CI the way compiler errors are. The rules are configured per template: a
+// template lists the front matter keys its posts need and the rules it turns off.
+//
+// Example:
+//
+//	issues, err := lint.File("content/posts/hello/index.md", lint.Config{RequiredFrontMatter: []string{"title"}})
+//	if err != nil {
+//	    return err
+//	}
+//	for _, issue := range issues {
+//	    fmt.Println(issue)
+//	}
+package lint
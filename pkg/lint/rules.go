@@ -0,0 +1,315 @@
+package lint
+
+import (
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/markdown"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Rules are all the rules, in the order they run.
+var Rules = []Rule{
+	{
+		Name:        RuleRequiredFrontMatter,
+		Description: "the front matter has the keys required by the template",
+		Check:       checkRequiredFrontMatter,
+	},
+	{
+		Name:        RuleHeadingIncrement,
+		Description: "heading levels increase one at a time, the title in front matter is the level 1",
+		Check:       checkHeadingIncrement,
+	},
+	{
+		Name:        RuleNoBodyH1,
+		Description: "no level 1 heading in the body when the title is in front matter",
+		Check:       checkNoBodyH1,
+	},
+	{
+		Name:        RuleFencedCodeLanguage,
+		Description: "code fences name their language",
+		Check:       checkFencedCodeLanguage,
+	},
+	{
+		Name:        RuleImageAlt,
+		Description: "images, <img> tags and figure shortcodes have an alt text",
+		Check:       checkImageAlt,
+	},
+	{
+		Name:        RuleNoTrailingWhitespace,
+		Description: "no whitespace at the end of lines",
+		Check:       checkNoTrailingWhitespace,
+	},
+	{
+		Name:        RuleRelativeLinks,
+		Description: "the relative links and images resolve to files",
+		Check:       checkRelativeLinks,
+	},
+	{
+		Name:        RuleBalancedShortcodes,
+		Description: "the Hugo shortcodes are closed and nested in order",
+		Check:       checkBalancedShortcodes,
+	},
+}
+
+var (
+	imagePattern     = regexp.MustCompile(`!\[([^\]]*)\]\(`)
+	imgTagPattern    = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	figurePattern    = regexp.MustCompile(`\{\{[<%]\s*figure\b.*?[>%]\}\}`)
+	altPattern       = regexp.MustCompile(`(?i)\balt\s*=\s*("[^"]*[^"\s][^"]*"|'[^']*[^'\s][^']*'|[^\s"'>]+)`)
+	linkPattern      = regexp.MustCompile(`\]\(\s*(<[^>]*>|[^)\s]+)`)
+	referencePattern = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s*(<[^>]*>|\S+)`)
+	schemePattern    = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+)
+
+func checkRequiredFrontMatter(p *Post, c Config) []Issue {
+	var res []Issue
+	for _, key := range c.RequiredFrontMatter {
+		if p.Doc.Format == frontmatter.FormatNone || !p.Doc.Has(key) {
+			res = append(res, p.issue(0, 0, "front matter key '%s' is missing", key))
+		}
+	}
+	return res
+}
+
+// headings calls fn with the index, the byte offset and the level of the ATX headings of the body.
+func (p *Post) headings(fn func(i, offset, level int)) {
+	p.body(func(i int, text string) {
+		if h, ok := markdown.ParseHeading(text); ok {
+			fn(i, h.Offset, h.Level)
+		}
+	})
+}
+
+func checkHeadingIncrement(p *Post, c Config) []Issue {
+	prev := 0
+	if p.Doc.GetString("title") != "" {
+		prev = 1
+	}
+
+	var res []Issue
+	p.headings(func(i, offset, level int) {
+		if prev != 0 && level > prev+1 {
+			res = append(res, p.issue(i, offset, "heading level %d skips level %d", level, prev+1))
+		}
+		prev = level
+	})
+	return res
+}
+
+func checkNoBodyH1(p *Post, c Config) []Issue {
+	if p.Doc.GetString("title") == "" {
+		return nil
+	}
+
+	var res []Issue
+	p.headings(func(i, offset, level int) {
+		if level == 1 {
+			res = append(res, p.issue(i, offset, "level 1 heading in the body, the title is in front matter"))
+		}
+	})
+	return res
+}
+
+func checkFencedCodeLanguage(p *Post, c Config) []Issue {
+	var res []Issue
+	for _, f := range p.fences {
+		if f.Info == "" {
+			res = append(res, p.issue(f.Line, f.Indent, "code fence without a language"))
+		}
+	}
+	return res
+}
+
+func checkImageAlt(p *Post, c Config) []Issue {
+	var res []Issue
+	p.body(func(i int, text string) {
+		for _, m := range imagePattern.FindAllStringSubmatchIndex(text, -1) {
+			if strings.TrimSpace(text[m[2]:m[3]]) == "" {
+				res = append(res, p.issue(i, m[0], "image without alt text"))
+			}
+		}
+		for _, m := range imgTagPattern.FindAllStringIndex(text, -1) {
+			if !altPattern.MatchString(text[m[0]:m[1]]) {
+				res = append(res, p.issue(i, m[0], "<img> without alt text"))
+			}
+		}
+		for _, m := range figurePattern.FindAllStringIndex(text, -1) {
+			if !altPattern.MatchString(text[m[0]:m[1]]) {
+				res = append(res, p.issue(i, m[0], "figure shortcode without alt text"))
+			}
+		}
+	})
+	return res
+}
+
+func checkNoTrailingWhitespace(p *Post, c Config) []Issue {
+	var res []Issue
+	for i, line := range p.Lines {
+		trimmed := strings.TrimRight(line, " \t")
+		if len(trimmed) != len(line) {
+			res = append(res, p.issue(i, len(trimmed), "trailing whitespace"))
+		}
+	}
+	return res
+}
+
+func checkRelativeLinks(p *Post, c Config) []Issue {
+	var res []Issue
+	check := func(i int, offset int, target string) {
+		target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+		if !resolves(p.Path, target) {
+			res = append(res, p.issue(i, offset, "relative link '%s' doesn't resolve", target))
+		}
+	}
+
+	p.body(func(i int, text string) {
+		for _, m := range linkPattern.FindAllStringSubmatchIndex(text, -1) {
+			check(i, m[2], text[m[2]:m[3]])
+		}
+		if m := referencePattern.FindStringSubmatchIndex(text); m != nil {
+			check(i, m[2], text[m[2]:m[3]])
+		}
+	})
+	return res
+}
+
+// resolves reports whether target, a link of the post at path, is a file or dir relative to the post. The links
+// with a scheme, the anchors, the site absolute links and the templated links aren't checked.
+func resolves(path, target string) bool {
+	if target == "" || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "/") ||
+		schemePattern.MatchString(target) || strings.Contains(target, "{{") {
+		return true
+	}
+
+	target, _, _ = strings.Cut(target, "#")
+	target, _, _ = strings.Cut(target, "?")
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+
+	p := filepath.Join(filepath.Dir(path), filepath.FromSlash(target))
+	if _, err := os.Stat(p); err == nil {
+		return true
+	}
+	// a link to the page of a single file post, '../other/' of 'other.md'
+	_, err := os.Stat(strings.TrimSuffix(p, string(filepath.Separator)) + ".md")
+	return err == nil
+}
+
+// shortcode is a '{{< name >}}' or '{{% name %}}' tag of the body.
+type shortcode struct {
+	line   int
+	offset int
+	name   string
+	// closing is '{{< /name >}}', selfClosing is '{{< name />}}'.
+	closing     bool
+	selfClosing bool
+}
+
+// shortcodeClosers maps the opening delimiter of a shortcode, after '{{', to the closing one, before '}}'.
+var shortcodeClosers = map[byte]byte{'<': '>', '%': '%'}
+
+// shortcodes returns the shortcode tags of the body, and the issues of the tags which aren't well formed. The
+// commented out tags, '{{</* name */>}}', are skipped.
+func (p *Post) shortcodes() ([]shortcode, []Issue) {
+	var tags []shortcode
+	var issues []Issue
+	p.body(func(i int, text string) {
+		for from := 0; ; {
+			start := strings.Index(text[from:], "{{")
+			if start < 0 || from+start+2 >= len(text) {
+				return
+			}
+			start += from
+			closer, ok := shortcodeClosers[text[start+2]]
+			if !ok {
+				from = start + 2
+				continue
+			}
+
+			end := strings.Index(text[start+3:], "}}")
+			if end < 0 {
+				issues = append(issues, p.issue(i, start, "shortcode isn't closed with '%c}}'", closer))
+				return
+			}
+			end += start + 3
+			from = end + 2
+
+			inner := text[start+3 : end]
+			if len(inner) == 0 || (text[end-1] != '>' && text[end-1] != '%') {
+				issues = append(issues, p.issue(i, start, "shortcode isn't closed with '%c}}'", closer))
+				continue
+			}
+			if text[end-1] != closer {
+				issues = append(issues, p.issue(i, start, "shortcode opened with '{{%c' is closed with '%c}}'",
+					text[start+2], text[end-1]))
+				continue
+			}
+
+			inner = strings.TrimSpace(inner[:len(inner)-1])
+			if strings.HasPrefix(inner, "/*") {
+				continue
+			}
+			tag := shortcode{line: i, offset: start}
+			if strings.HasPrefix(inner, "/") {
+				tag.closing = true
+				inner = strings.TrimSpace(inner[1:])
+			} else if strings.HasSuffix(inner, "/") {
+				tag.selfClosing = true
+				inner = strings.TrimSuffix(inner, "/")
+			}
+			fields := strings.Fields(inner)
+			if len(fields) == 0 {
+				issues = append(issues, p.issue(i, start, "shortcode without name"))
+				continue
+			}
+			tag.name = fields[0]
+			tags = append(tags, tag)
+		}
+	})
+	return tags, issues
+}
+
+// checkBalancedShortcodes checks the shortcodes closed anywhere in the post are closed everywhere and nested in
+// order. Shortcodes never closed in the post, like figure, don't take an inner content and are left alone.
+func checkBalancedShortcodes(p *Post, c Config) []Issue {
+	tags, res := p.shortcodes()
+
+	paired := map[string]bool{}
+	for _, tag := range tags {
+		if tag.closing {
+			paired[tag.name] = true
+		}
+	}
+
+	var stack []shortcode
+	for _, tag := range tags {
+		switch {
+		case tag.selfClosing || !paired[tag.name]:
+		case !tag.closing:
+			stack = append(stack, tag)
+		default:
+			open := len(stack) - 1
+			for open >= 0 && stack[open].name != tag.name {
+				open--
+			}
+			if open < 0 {
+				res = append(res, p.issue(tag.line, tag.offset, "closing shortcode '%s' without opening", tag.name))
+				continue
+			}
+			for _, unclosed := range stack[open+1:] {
+				res = append(res, p.issue(unclosed.line, unclosed.offset,
+					"shortcode '%s' isn't closed before '%s'", unclosed.name, tag.name))
+			}
+			stack = stack[:open]
+		}
+	}
+	for _, unclosed := range stack {
+		res = append(res, p.issue(unclosed.line, unclosed.offset, "shortcode '%s' isn't closed", unclosed.name))
+	}
+
+	return res
+}
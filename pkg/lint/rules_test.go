@@ -0,0 +1,81 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// only returns the config running the rule name alone.
+func only(name string) Config {
+	var c Config
+	for _, r := range Rules {
+		if r.Name != name {
+			c.Disabled = append(c.Disabled, r.Name)
+		}
+	}
+	return c
+}
+
+func TestHeadingIncrement(t *testing.T) {
+	for content, want := range map[string]string{
+		"+++\ntitle = 't'\n+++\n### skips\n":     "4:1 heading-increment",
+		"## a\n#### b\n### c\n##### d\n":         "2:1 heading-increment,4:1 heading-increment",
+		"# a\n## b\n### c\n## d\n#not heading\n": "",
+		"  ## a\n  #### 中文\n":                    "2:3 heading-increment",
+	} {
+		if got := strings.Join(lintString(t, content, only(RuleHeadingIncrement)), ","); got != want {
+			t.Fatalf("Unexpected issues of %q: %s, want %s", content, got, want)
+		}
+	}
+}
+
+func TestImageAlt(t *testing.T) {
+	content := "中 ![](a.png) ![ok](b.png)\n<img src=\"c.png\"> <img alt=\"c\" src=\"c.png\">\n" +
+		"{{< figure src=\"d.png\" >}} {{< figure src=\"d.png\" alt=\"d\" >}}\n<img alt=\"\">\n"
+
+	got := strings.Join(lintString(t, content, only(RuleImageAlt)), ",")
+	if got != "1:3 image-alt,2:1 image-alt,3:1 image-alt,4:1 image-alt" {
+		t.Fatalf("Unexpected issues: %s", got)
+	}
+}
+
+func TestRelativeLinks(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"post/feature.png", "other.md", "my file.md"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	content := "[a](feature.png) [b](../other/#top) [c](https://x.io) [d](#top) [e](/posts/)\n" +
+		"[f](../my%20file.md) ![g](missing.png \"title\")\n[ref]: ./nope.md\n"
+	issues, err := Lint(filepath.Join(dir, "post", "index.md"), []byte(content), only(RuleRelativeLinks))
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	if len(issues) != 2 || issues[0].Line != 2 || issues[0].Col != 27 || issues[1].Line != 3 {
+		t.Fatalf("Unexpected issues: %v", issues)
+	}
+	if !strings.Contains(issues[0].Message, "'missing.png'") {
+		t.Fatalf("Expected the target in the message: %s", issues[0].Message)
+	}
+}
+
+func TestBalancedShortcodes(t *testing.T) {
+	for content, want := range map[string]string{
+		"{{< figure src=\"a.png\" >}}\n{{% note %}}\n{{< tab >}}x{{< /tab >}}\n{{% /note %}}\n": "",
+		"{{< note >}}\n{{< tab >}}\n{{< /note >}}\n{{< tab />}}{{< /tab >}}\n":                  "2:1 balanced-shortcodes,4:13 balanced-shortcodes",
+		"{{< /note >}}\n{{< note >}}\n": "1:1 balanced-shortcodes,2:1 balanced-shortcodes",
+		"{{< note %}}\n{{< note\n{{</* note */>}}\n```\n{{< /note >}}\n```\n{{< >}}\n": "1:1 balanced-shortcodes,2:1 balanced-shortcodes,7:1 balanced-shortcodes",
+		"{{ .Title }} {{< details summary=\"a > b\" >}}\n{{< /details >}}\n":           "",
+	} {
+		if got := strings.Join(lintString(t, content, only(RuleBalancedShortcodes)), ","); got != want {
+			t.Fatalf("Unexpected issues of %q: %s, want %s", content, got, want)
+		}
+	}
+}
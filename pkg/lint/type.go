@@ -0,0 +1,62 @@
+package lint
+
+import (
+	"fmt"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/markdown"
+)
+
+// The names of the rules.
+const (
+	RuleRequiredFrontMatter  = "required-front-matter"
+	RuleHeadingIncrement     = "heading-increment"
+	RuleNoBodyH1             = "no-body-h1"
+	RuleFencedCodeLanguage   = "fenced-code-language"
+	RuleImageAlt             = "image-alt"
+	RuleNoTrailingWhitespace = "no-trailing-whitespace"
+	RuleRelativeLinks        = "relative-links"
+	RuleBalancedShortcodes   = "balanced-shortcodes"
+
+	// RuleFrontMatter reports the front matter which can't be parsed, it can't be disabled.
+	RuleFrontMatter = "front-matter"
+)
+
+// Config is the lint setting of a template.
+type Config struct {
+	RequiredFrontMatter []string `yaml:"RequiredFrontMatter" describe:"The front matter keys every post must have, e.g. title, date."`
+	Disabled            []string `yaml:"Disabled" describe:"The rules not checked, support: required-front-matter, heading-increment,\nno-body-h1, fenced-code-language, image-alt, no-trailing-whitespace, relative-links,\nbalanced-shortcodes"`
+}
+
+// Issue is a problem found in a post.
+type Issue struct {
+	File string `json:"file"`
+	// Line and Col are 1-based, Col counts characters.
+	Line    int    `json:"line"`
+	Col     int    `json:"col"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// String formats the issue as 'file:line:col: message (rule)'.
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s (%s)", i.File, i.Line, i.Col, i.Message, i.Rule)
+}
+
+// Rule is a check of posts. Check returns the issues without File and Rule, they are set by the caller.
+type Rule struct {
+	Name        string
+	Description string
+	Check       func(p *Post, c Config) []Issue
+}
+
+// Post is a post being linted.
+type Post struct {
+	Path string
+	Doc  *frontmatter.Document
+	// Lines are the lines of the file, front matter included, without line breaks.
+	Lines []string
+
+	// code marks the lines of the fenced code blocks, the fences included.
+	code   []bool
+	fences []markdown.Fence
+}
@@ -0,0 +1,194 @@
+package llms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.io/uberate/hcli/pkg/cache"
+	"github.io/uberate/hcli/pkg/hctx"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	defaultCacheTTL       = 30 * 24 * time.Hour
+	defaultCacheMaxSizeMB = 512
+)
+
+// CacheMode controls how the calls of a context use the cache.
+type CacheMode int
+
+const (
+	// CacheOn reads and writes the cache.
+	CacheOn CacheMode = iota
+	// CacheOff neither reads nor writes the cache.
+	CacheOff
+	// CacheRefresh skips the cached responses and writes the new ones.
+	CacheRefresh
+)
+
+type cacheModeKey struct{}
+type cacheVariantKey struct{}
+
+// WithCacheMode sets the cache mode of the calls of ctx.
+func WithCacheMode(ctx context.Context, mode CacheMode) context.Context {
+	return context.WithValue(ctx, cacheModeKey{}, mode)
+}
+
+func cacheModeOf(ctx context.Context) CacheMode {
+	mode, _ := ctx.Value(cacheModeKey{}).(CacheMode)
+	return mode
+}
+
+// WithCacheVariant separates the cache entries of identical calls which must give different responses, like the
+// candidates of a poster.
+func WithCacheVariant(ctx context.Context, variant string) context.Context {
+	return context.WithValue(ctx, cacheVariantKey{}, variant)
+}
+
+func cacheVariantOf(ctx context.Context) string {
+	variant, _ := ctx.Value(cacheVariantKey{}).(string)
+	return variant
+}
+
+// CacheConfig is the on-disk cache of the LLM responses.
+type CacheConfig struct {
+	Disabled  bool   `yaml:"Disabled" describe:"Whether to disable the cache of the LLM responses." default:"false"`
+	Dir       string `yaml:"Dir" describe:"The cache dir, relative to the config file. Empty means 'hcli/llm' in the user\ncache dir."`
+	TTL       string `yaml:"TTL" describe:"How long an unused response is kept." default:"720h"`
+	MaxSizeMB int    `yaml:"MaxSizeMB" describe:"The max size of the cache in MB, the oldest responses are removed first." default:"512"`
+}
+
+// Store opens the cache store of the config.
+func (c CacheConfig) Store() (*cache.Store, error) {
+	ttl := defaultCacheTTL
+	if c.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(c.TTL); err != nil || ttl < 0 {
+			return nil, fmt.Errorf("invalid cache TTL '%s'", c.TTL)
+		}
+	}
+
+	maxSize := c.MaxSizeMB
+	if maxSize == 0 {
+		maxSize = defaultCacheMaxSizeMB
+	}
+	if maxSize < 0 {
+		return nil, fmt.Errorf("invalid cache max size %d", c.MaxSizeMB)
+	}
+
+	dir := c.Dir
+	if dir == "" {
+		userDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(userDir, "hcli", "llm")
+	}
+
+	return cache.New(dir, ttl, int64(maxSize)<<20), nil
+}
+
+// WithCache wraps tools so identical calls are answered from store. The entries are keyed by provider, model,
+// system prompt and input. Cache hits return a usage without tokens and images, marked as cached.
+func WithCache(tools LLMTools, store *cache.Store, provider, textModel, picModel string) LLMTools {
+	return &cached{tools: tools, store: store, provider: provider, textModel: textModel, picModel: picModel}
+}
+
+type cached struct {
+	tools     LLMTools
+	store     *cache.Store
+	provider  string
+	textModel string
+	picModel  string
+}
+
+// cacheEntry is the stored response of a call.
+type cacheEntry struct {
+	Text string `json:"text,omitempty"`
+	Data []byte `json:"data,omitempty"`
+}
+
+func (c *cached) Text(ctx context.Context, sysPrompt, input string) (string, Usage, error) {
+	key := cache.Key("text", c.provider, c.textModel, sysPrompt, input, cacheVariantOf(ctx))
+	if e, ok := c.get(ctx, key); ok {
+		return e.Text, Usage{Provider: c.provider, Model: c.textModel, Cached: true}, nil
+	}
+
+	res, u, err := c.tools.Text(ctx, sysPrompt, input)
+	if err == nil {
+		c.put(ctx, key, cacheEntry{Text: res})
+	}
+	return res, u, err
+}
+
+// TextStream passes a cached completion to onChunk at once.
+func (c *cached) TextStream(ctx context.Context, sysPrompt, input string,
+	onChunk func(chunk string)) (string, Usage, error) {
+
+	key := cache.Key("text", c.provider, c.textModel, sysPrompt, input, cacheVariantOf(ctx))
+	if e, ok := c.get(ctx, key); ok {
+		onChunk(e.Text)
+		return e.Text, Usage{Provider: c.provider, Model: c.textModel, Cached: true}, nil
+	}
+
+	res, u, err := TextStream(ctx, c.tools, sysPrompt, input, onChunk)
+	if err == nil {
+		c.put(ctx, key, cacheEntry{Text: res})
+	}
+	return res, u, err
+}
+
+func (c *cached) Pic(ctx context.Context, input string) ([]byte, Usage, error) {
+	key := cache.Key("pic", c.provider, c.picModel, input, cacheVariantOf(ctx))
+	if e, ok := c.get(ctx, key); ok {
+		return e.Data, Usage{Provider: c.provider, Model: c.picModel, Cached: true}, nil
+	}
+
+	res, u, err := c.tools.Pic(ctx, input)
+	if err == nil {
+		c.put(ctx, key, cacheEntry{Data: res})
+	}
+	return res, u, err
+}
+
+// get returns the cached entry of key, a broken cache is a miss.
+func (c *cached) get(ctx context.Context, key string) (cacheEntry, bool) {
+	var e cacheEntry
+	if cacheModeOf(ctx) != CacheOn {
+		return e, false
+	}
+
+	data, ok, err := c.store.Get(key)
+	if err != nil {
+		hctx.Warn(ctx, "read llm cache failed: %v", err)
+		return e, false
+	}
+	if !ok {
+		return e, false
+	}
+
+	if err = json.Unmarshal(data, &e); err != nil {
+		hctx.Warn(ctx, "read llm cache failed: %v", err)
+		return e, false
+	}
+
+	hctx.Debug(ctx, "llm cache hit %s", key[:12])
+	return e, true
+}
+
+// put stores the entry of key, a failed write only warns since the response is already paid for.
+func (c *cached) put(ctx context.Context, key string, e cacheEntry) {
+	if cacheModeOf(ctx) == CacheOff {
+		return
+	}
+
+	data, err := json.Marshal(e)
+	if err == nil {
+		err = c.store.Put(key, data)
+	}
+	if err != nil {
+		hctx.Warn(ctx, "write llm cache failed: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"github.io/uberate/hcli/pkg/cache"
+	"testing"
+	"time"
+)
+
+func newTestCache(t *testing.T, tools LLMTools) LLMTools {
+	t.Helper()
+	return WithCache(tools, cache.New(t.TempDir(), time.Hour, 0), "fake", "text-m", "pic-m")
+}
+
+func TestWithCacheHit(t *testing.T) {
+	f := &flakyLLM{}
+	tools := newTestCache(t, f)
+	ctx := context.Background()
+
+	if _, u, err := tools.Text(ctx, "sys", "in"); err != nil || u.Cached {
+		t.Fatalf("Unexpected first call: %+v %v", u, err)
+	}
+	res, u, err := tools.Text(ctx, "sys", "in")
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if res != "ok" || !u.Cached || u.PromptTokens != 0 || u.Model != "text-m" {
+		t.Fatalf("Expected a cached response: %s %+v", res, u)
+	}
+	if f.calls != 1 {
+		t.Fatalf("Expected 1 call, got %d", f.calls)
+	}
+
+	if _, _, err = tools.Text(ctx, "other", "in"); err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if _, _, err = tools.Text(ctx, "sys", "other"); err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if f.calls != 3 {
+		t.Fatalf("Expected the system prompt and the input in the key, got %d calls", f.calls)
+	}
+
+	for i := 0; i < 2; i++ {
+		data, u, err := tools.Pic(ctx, "in")
+		if err != nil || string(data) != "pic" || u.Cached != (i == 1) {
+			t.Fatalf("Unexpected pic %d: %s %+v %v", i, data, u, err)
+		}
+	}
+	if f.calls != 4 {
+		t.Fatalf("Expected pics apart from texts, got %d calls", f.calls)
+	}
+}
+
+func TestWithCacheModes(t *testing.T) {
+	f := &flakyLLM{}
+	tools := newTestCache(t, f)
+	ctx := context.Background()
+
+	if _, _, err := tools.Text(WithCacheMode(ctx, CacheOff), "sys", "in"); err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if _, u, _ := tools.Text(ctx, "sys", "in"); u.Cached {
+		t.Fatal("Expected no write with the cache off")
+	}
+	if _, u, _ := tools.Text(WithCacheMode(ctx, CacheRefresh), "sys", "in"); u.Cached {
+		t.Fatal("Expected no read with refresh")
+	}
+	if _, u, _ := tools.Text(ctx, "sys", "in"); !u.Cached {
+		t.Fatal("Expected a hit after refresh")
+	}
+	if f.calls != 3 {
+		t.Fatalf("Expected 3 calls, got %d", f.calls)
+	}
+}
+
+func TestWithCacheVariant(t *testing.T) {
+	f := &flakyLLM{}
+	tools := newTestCache(t, f)
+	ctx := context.Background()
+
+	for _, v := range []string{"a", "b", "a"} {
+		if _, _, err := tools.Pic(WithCacheVariant(ctx, v), "in"); err != nil {
+			t.Fatalf("Pic failed: %v", err)
+		}
+	}
+	if f.calls != 2 {
+		t.Fatalf("Expected a call per variant, got %d", f.calls)
+	}
+}
+
+func TestWithCacheSkipsErrors(t *testing.T) {
+	f := &flakyLLM{failures: 1, err: errors.New("boom")}
+	tools := newTestCache(t, f)
+	ctx := context.Background()
+
+	if _, _, err := tools.Text(ctx, "sys", "in"); err == nil {
+		t.Fatal("Expected the error")
+	}
+	if _, u, err := tools.Text(ctx, "sys", "in"); err != nil || u.Cached {
+		t.Fatalf("Expected the failure not cached: %+v %v", u, err)
+	}
+}
+
+func TestCacheConfigStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := CacheConfig{Dir: dir}.Store()
+	if err != nil || store.Dir() != dir {
+		t.Fatalf("Unexpected store: %v", err)
+	}
+
+	if _, err = (CacheConfig{TTL: "soon"}).Store(); err == nil {
+		t.Fatal("Expected an invalid TTL error")
+	}
+	if _, err = (CacheConfig{MaxSizeMB: -1}).Store(); err == nil {
+		t.Fatal("Expected an invalid size error")
+	}
+}
@@ -0,0 +1,71 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{&ProviderError{StatusCode: http.StatusTooManyRequests}, true},
+		{&ProviderError{StatusCode: http.StatusBadGateway}, true},
+		{&ProviderError{StatusCode: http.StatusUnauthorized}, false},
+		{fmt.Errorf("wrapped: %w", &ProviderError{StatusCode: http.StatusInternalServerError}), true},
+		{context.DeadlineExceeded, true},
+		{context.Canceled, false},
+		{&url.Error{Op: "Post", URL: "https://ark", Err: io.ErrUnexpectedEOF}, true},
+		{&url.Error{Op: "Post", URL: "https://ark", Err: context.Canceled}, false},
+		{errors.New("no pic model"), false},
+	}
+
+	for _, c := range cases {
+		if got := Retryable(c.err); got != c.want {
+			t.Fatalf("Retryable(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if d := ParseRetryAfter("3", now); d != 3*time.Second {
+		t.Fatalf("Unexpected seconds: %s", d)
+	}
+	if d := ParseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now); d != time.Minute {
+		t.Fatalf("Unexpected date: %s", d)
+	}
+	if d := ParseRetryAfter("soon", now); d != 0 {
+		t.Fatalf("Unexpected invalid value: %s", d)
+	}
+}
+
+func TestVolcError(t *testing.T) {
+	header := http.Header{"Retry-After": []string{"2"}}
+
+	err := volcError(&model.APIError{HTTPStatusCode: http.StatusTooManyRequests}, header)
+	var pe *ProviderError
+	if !errors.As(err, &pe) || pe.StatusCode != http.StatusTooManyRequests || pe.RetryAfter != 2*time.Second {
+		t.Fatalf("Unexpected api error: %v", err)
+	}
+
+	transport := model.NewRequestError(http.StatusInternalServerError, &url.Error{Err: io.EOF}, "")
+	if err = volcError(transport, nil); errors.As(err, &pe) || !Retryable(err) {
+		t.Fatalf("Expected a transport error to be kept and retryable: %v", err)
+	}
+
+	plain := errors.New("plain")
+	if volcError(plain, header) != plain {
+		t.Fatal("Expected other errors to be kept")
+	}
+}
@@ -0,0 +1,125 @@
+package llms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/jsonschema"
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultJSONName   = "answer"
+	defaultJSONReasks = 2
+)
+
+var jsonNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// JSONArgs are the arguments of TextJSON.
+type JSONArgs struct {
+	SysPrompt string
+	Input     string
+	// Name names the schema for the providers, empty means 'answer'.
+	Name string
+	// Reasks is how many times an invalid answer is asked again with its validation error, zero means 2, negative
+	// means never.
+	Reasks int
+}
+
+type jsonSchemaKey struct{}
+
+// jsonSchema is the schema the answer of a call must match.
+type jsonSchema struct {
+	name   string
+	schema *jsonschema.Schema
+}
+
+// jsonSchemaOf returns the schema the answer of the calls of ctx must match, nil if the answer is free text. The
+// providers with a JSON mode send it with the request.
+func jsonSchemaOf(ctx context.Context) *jsonSchema {
+	s, _ := ctx.Value(jsonSchemaKey{}).(*jsonSchema)
+	return s
+}
+
+// TextJSON asks tools for a JSON answer matching the JSON Schema of v and unmarshals it into v, which must be a
+// pointer. The schema is added to the system prompt and sent to the providers with a JSON mode. An answer which
+// doesn't match the schema is asked again with the validation error, the usage of all the calls is summed up.
+func TextJSON(ctx context.Context, tools LLMTools, args JSONArgs, v interface{}) (Usage, error) {
+	var total Usage
+	if v == nil {
+		return total, errors.New("TextJSON needs a pointer to unmarshal into")
+	}
+
+	schema, err := jsonschema.For(v)
+	if err != nil {
+		return total, err
+	}
+	schemaData, err := json.Marshal(schema)
+	if err != nil {
+		return total, err
+	}
+
+	name := args.Name
+	if name == "" {
+		name = defaultJSONName
+	}
+	if !jsonNamePattern.MatchString(name) {
+		return total, fmt.Errorf("invalid JSON schema name '%s'", name)
+	}
+	reasks := args.Reasks
+	if reasks == 0 {
+		reasks = defaultJSONReasks
+	}
+
+	ctx = context.WithValue(ctx, jsonSchemaKey{}, &jsonSchema{name: name, schema: schema})
+	sysPrompt := strings.TrimSpace(args.SysPrompt + "\n\nAnswer with a JSON value only, without Markdown or other " +
+		"text. It must match this JSON Schema:\n" + string(schemaData))
+
+	input := args.Input
+	for attempt := 0; ; attempt++ {
+		res, u, err := tools.Text(ctx, sysPrompt, input)
+		total = addUsage(total, u)
+		if err != nil {
+			return total, err
+		}
+
+		data := []byte(ExtractJSON(res))
+		err = schema.Validate(data)
+		if err == nil {
+			return total, json.Unmarshal(data, v)
+		}
+
+		if attempt >= reasks {
+			return total, fmt.Errorf("invalid JSON answer after %d attempts: %w", attempt+1, err)
+		}
+		hctx.Warn(ctx, "invalid JSON answer, ask again %d/%d: %v", attempt+1, reasks, err)
+
+		input = fmt.Sprintf("%s\n\nYour previous answer was:\n%s\n\nIt is invalid: %v\nAnswer again with the "+
+			"corrected JSON only.", args.Input, res, err)
+	}
+}
+
+// ExtractJSON returns the JSON of an answer, without the Markdown code fence or the text around it.
+func ExtractJSON(answer string) string {
+	s := strings.TrimSpace(answer)
+	if strings.HasPrefix(s, "```") {
+		s = strings.TrimPrefix(s, "```")
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			s = s[i+1:]
+		}
+		s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "```"))
+	}
+
+	start := strings.IndexAny(s, "{[")
+	if start < 0 {
+		return s
+	}
+	end := strings.LastIndexAny(s, "}]")
+	if end < start {
+		return s
+	}
+	return s[start : end+1]
+}
@@ -0,0 +1,87 @@
+package llms
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// scriptedLLM answers the text calls with answers in order and keeps the inputs.
+type scriptedLLM struct {
+	answers []string
+	inputs  []string
+	schemas []*jsonSchema
+}
+
+func (s *scriptedLLM) Text(ctx context.Context, sysPrompt, input string) (string, Usage, error) {
+	s.inputs = append(s.inputs, input)
+	s.schemas = append(s.schemas, jsonSchemaOf(ctx))
+	res := s.answers[0]
+	s.answers = s.answers[1:]
+	return res, Usage{PromptTokens: 1}, nil
+}
+
+func (s *scriptedLLM) Pic(ctx context.Context, input string) ([]byte, Usage, error) {
+	return nil, Usage{}, nil
+}
+
+type tagAnswer struct {
+	Tags   []string `json:"tags" describe:"The tags of the post"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+func TestTextJSON(t *testing.T) {
+	fake := &scriptedLLM{answers: []string{
+		"Sure:\n```json\n{\"tags\": \"go\"}\n```",
+		"{\"tags\": [\"go\", \"cli\"]}",
+	}}
+
+	var res tagAnswer
+	u, err := TextJSON(context.Background(), fake, JSONArgs{SysPrompt: "suggest tags", Input: "post"}, &res)
+	if err != nil {
+		t.Fatalf("TextJSON failed: %v", err)
+	}
+	if strings.Join(res.Tags, ",") != "go,cli" || u.PromptTokens != 2 {
+		t.Fatalf("Unexpected answer %+v, usage %+v", res, u)
+	}
+
+	if len(fake.inputs) != 2 || !strings.Contains(fake.inputs[1], "$.tags: expected array, got string") {
+		t.Fatalf("Expected the validation error in the re-ask: %q", fake.inputs)
+	}
+	if s := fake.schemas[0]; s == nil || s.name != "answer" || s.schema.Properties["tags"] == nil {
+		t.Fatalf("Expected the schema in the context: %+v", s)
+	}
+}
+
+func TestTextJSONGivesUp(t *testing.T) {
+	fake := &scriptedLLM{answers: []string{"no", "no", "no", "no"}}
+
+	var res tagAnswer
+	_, err := TextJSON(context.Background(), fake, JSONArgs{Reasks: 1}, &res)
+	if err == nil || !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Fatalf("Expected to give up after 2 attempts, got %v", err)
+	}
+
+	fake = &scriptedLLM{answers: []string{"no"}}
+	if _, err = TextJSON(context.Background(), fake, JSONArgs{Reasks: -1}, &res); err == nil || len(fake.inputs) != 1 {
+		t.Fatalf("Expected no re-ask, got %v after %d calls", err, len(fake.inputs))
+	}
+
+	if _, err = TextJSON(context.Background(), fake, JSONArgs{Name: "bad name"}, &res); err == nil {
+		t.Fatal("Expected an invalid name error")
+	}
+}
+
+func TestExtractJSON(t *testing.T) {
+	for in, want := range map[string]string{
+		`{"a":1}`:                       `{"a":1}`,
+		"```json\n{\"a\":1}\n```":       `{"a":1}`,
+		"Here it is: [1, 2]. Done.":     `[1, 2]`,
+		"no json":                       "no json",
+		"  \n```\n{\"a\":[1]}\n```\n  ": `{"a":[1]}`,
+	} {
+		if got := ExtractJSON(in); got != want {
+			t.Fatalf("ExtractJSON(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
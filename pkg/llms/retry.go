@@ -0,0 +1,232 @@
+package llms
+
+import (
+	"context"
+	"fmt"
+	"github.io/uberate/hcli/pkg/hctx"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 30 * time.Second
+	defaultTextTimeout    = 2 * time.Minute
+	defaultPicTimeout     = 5 * time.Minute
+)
+
+// RetryConfig is the retry, backoff and timeout policy of the LLM calls.
+type RetryConfig struct {
+	MaxAttempts    int    `yaml:"MaxAttempts" describe:"The max attempts of a call, 1 means no retry." default:"3"`
+	InitialBackoff string `yaml:"InitialBackoff" describe:"The wait before the first retry, doubled on every retry, with jitter." default:"1s"`
+	MaxBackoff     string `yaml:"MaxBackoff" describe:"The max wait between two attempts, a longer Retry-After of the provider fails the call." default:"30s"`
+	TextTimeout    string `yaml:"TextTimeout" describe:"The timeout of one Text attempt." default:"2m"`
+	PicTimeout     string `yaml:"PicTimeout" describe:"The timeout of one Pic attempt." default:"5m"`
+}
+
+// retryPolicy is the parsed RetryConfig.
+type retryPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	textTimeout    time.Duration
+	picTimeout     time.Duration
+}
+
+func (c RetryConfig) policy() (retryPolicy, error) {
+	p := retryPolicy{maxAttempts: c.MaxAttempts}
+	if p.maxAttempts == 0 {
+		p.maxAttempts = defaultMaxAttempts
+	}
+	if p.maxAttempts < 0 {
+		return p, fmt.Errorf("invalid retry max attempts %d", c.MaxAttempts)
+	}
+
+	for _, d := range []struct {
+		name  string
+		value string
+		def   time.Duration
+		dst   *time.Duration
+	}{
+		{"InitialBackoff", c.InitialBackoff, defaultInitialBackoff, &p.initialBackoff},
+		{"MaxBackoff", c.MaxBackoff, defaultMaxBackoff, &p.maxBackoff},
+		{"TextTimeout", c.TextTimeout, defaultTextTimeout, &p.textTimeout},
+		{"PicTimeout", c.PicTimeout, defaultPicTimeout, &p.picTimeout},
+	} {
+		*d.dst = d.def
+		if d.value == "" {
+			continue
+		}
+
+		v, err := time.ParseDuration(d.value)
+		if err != nil || v < 0 {
+			return p, fmt.Errorf("invalid retry %s '%s'", d.name, d.value)
+		}
+		*d.dst = v
+	}
+
+	return p, nil
+}
+
+// Validate checks the values of the config.
+func (c RetryConfig) Validate() error {
+	_, err := c.policy()
+	return err
+}
+
+// WithRetry wraps tools so the retryable failures are sent again with exponential backoff and jitter. Every attempt
+// runs with the timeout of its kind, the usage of all the attempts is summed up.
+func WithRetry(tools LLMTools, c RetryConfig) (LLMTools, error) {
+	p, err := c.policy()
+	if err != nil {
+		return nil, err
+	}
+
+	return &retrier{tools: tools, policy: p, sleep: sleep}, nil
+}
+
+type retrier struct {
+	tools  LLMTools
+	policy retryPolicy
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+func (r *retrier) Text(ctx context.Context, sysPrompt, input string) (string, Usage, error) {
+	var res string
+	u, err := r.do(ctx, "text", r.policy.textTimeout, func(ctx context.Context) (Usage, error) {
+		var u Usage
+		var err error
+		res, u, err = r.tools.Text(ctx, sysPrompt, input)
+		return u, err
+	}, Retryable)
+	return res, u, err
+}
+
+// TextStream retries the failed attempts until a chunk has been passed to onChunk, the chunks can't be taken back.
+func (r *retrier) TextStream(ctx context.Context, sysPrompt, input string,
+	onChunk func(chunk string)) (string, Usage, error) {
+
+	var res string
+	streamed := false
+	u, err := r.do(ctx, "text", r.policy.textTimeout, func(ctx context.Context) (Usage, error) {
+		var u Usage
+		var err error
+		res, u, err = TextStream(ctx, r.tools, sysPrompt, input, func(chunk string) {
+			streamed = true
+			onChunk(chunk)
+		})
+		return u, err
+	}, func(err error) bool {
+		return !streamed && Retryable(err)
+	})
+	return res, u, err
+}
+
+func (r *retrier) Pic(ctx context.Context, input string) ([]byte, Usage, error) {
+	var res []byte
+	u, err := r.do(ctx, "pic", r.policy.picTimeout, func(ctx context.Context) (Usage, error) {
+		var u Usage
+		var err error
+		res, u, err = r.tools.Pic(ctx, input)
+		return u, err
+	}, Retryable)
+	return res, u, err
+}
+
+func (r *retrier) do(ctx context.Context, kind string, timeout time.Duration,
+	call func(ctx context.Context) (Usage, error), retryable func(err error) bool) (Usage, error) {
+
+	var total Usage
+	for attempt := 1; ; attempt++ {
+		u, err := r.attempt(ctx, timeout, call)
+		total = addUsage(total, u)
+		if err == nil {
+			return total, nil
+		}
+
+		if attempt >= r.policy.maxAttempts || ctx.Err() != nil || !retryable(err) {
+			return total, err
+		}
+
+		// a Retry-After longer than the max backoff is not waited, retrying earlier would fail again.
+		wait := RetryAfterOf(err)
+		if wait > r.policy.maxBackoff {
+			return total, fmt.Errorf("%s call failed, the provider asks to retry in %s, more than the max backoff %s: %w",
+				kind, wait, r.policy.maxBackoff, err)
+		}
+		if wait == 0 {
+			wait = r.backoff(attempt)
+		}
+		hctx.Warn(ctx, "%s call failed, retry %d/%d in %s: %v", kind, attempt, r.policy.maxAttempts-1,
+			wait.Round(time.Millisecond), err)
+
+		if err = r.sleep(ctx, wait); err != nil {
+			return total, err
+		}
+	}
+}
+
+func (r *retrier) attempt(ctx context.Context, timeout time.Duration,
+	call func(ctx context.Context) (Usage, error)) (Usage, error) {
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return call(ctx)
+}
+
+// backoff returns the wait after the failed attempt: the doubled initial backoff capped by the max backoff, of
+// which the upper half is random.
+func (r *retrier) backoff(attempt int) time.Duration {
+	d := r.policy.initialBackoff << (attempt - 1)
+	if d > r.policy.maxBackoff || d <= 0 {
+		d = r.policy.maxBackoff
+	}
+	if d <= 1 {
+		return d
+	}
+
+	return d/2 + rand.N(d/2)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func addUsage(a, b Usage) Usage {
+	attempts := append(append([]Usage{}, a.Attempts...), b.Attempts...)
+	a.Attempts, b.Attempts = nil, nil
+
+	// the usage of another provider or model is kept apart, so the tokens are attributed to the one which used them.
+	if b.Provider != "" && a.Provider != "" && (a.Provider != b.Provider || a.Model != b.Model) {
+		b.Attempts = append(attempts, a)
+		return b
+	}
+
+	if b.Provider != "" {
+		a.Provider = b.Provider
+	}
+	if b.Model != "" {
+		a.Model = b.Model
+	}
+	a.PromptTokens += b.PromptTokens
+	a.CompletionTokens += b.CompletionTokens
+	a.Images += b.Images
+	if len(attempts) != 0 {
+		a.Attempts = attempts
+	}
+
+	return a
+}
@@ -0,0 +1,170 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// flakyLLM fails the first failures calls with err, then succeeds. Every call uses one prompt token.
+type flakyLLM struct {
+	failures int
+	err      error
+	calls    int
+	// block makes the failing calls wait for the cancel of their context.
+	block bool
+	// model is the model of the usage, "m" if empty.
+	model string
+}
+
+func (f *flakyLLM) call(ctx context.Context) (Usage, error) {
+	f.calls++
+	u := Usage{Provider: "fake", Model: "m", PromptTokens: 1}
+	if f.model != "" {
+		u.Model = f.model
+	}
+	if f.calls > f.failures {
+		return u, nil
+	}
+	if f.block {
+		<-ctx.Done()
+		return u, ctx.Err()
+	}
+	return u, f.err
+}
+
+func (f *flakyLLM) Text(ctx context.Context, sysPrompt, input string) (string, Usage, error) {
+	u, err := f.call(ctx)
+	if err != nil {
+		return "", u, err
+	}
+	return "ok", u, nil
+}
+
+func (f *flakyLLM) Pic(ctx context.Context, input string) ([]byte, Usage, error) {
+	u, err := f.call(ctx)
+	if err != nil {
+		return nil, u, err
+	}
+	return []byte("pic"), u, nil
+}
+
+func newTestRetrier(t *testing.T, tools LLMTools, c RetryConfig) (*retrier, *[]time.Duration) {
+	t.Helper()
+	wrapped, err := WithRetry(tools, c)
+	if err != nil {
+		t.Fatalf("WithRetry failed: %v", err)
+	}
+
+	r := wrapped.(*retrier)
+	var waits []time.Duration
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	return r, &waits
+}
+
+func TestRetrySucceedsAfterFailures(t *testing.T) {
+	fake := &flakyLLM{failures: 2, err: &ProviderError{StatusCode: http.StatusServiceUnavailable}}
+	r, waits := newTestRetrier(t, fake, RetryConfig{InitialBackoff: "100ms", MaxBackoff: "150ms"})
+
+	res, u, err := r.Text(context.Background(), "", "")
+	if err != nil || res != "ok" {
+		t.Fatalf("Expected success after 2 failures, got %q, %v", res, err)
+	}
+	if fake.calls != 3 || u.PromptTokens != 3 || u.Model != "m" {
+		t.Fatalf("Expected 3 calls with the usage summed up, got %d calls, usage %+v", fake.calls, u)
+	}
+
+	if len(*waits) != 2 {
+		t.Fatalf("Expected 2 waits, got %v", *waits)
+	}
+	if w := (*waits)[0]; w < 50*time.Millisecond || w >= 100*time.Millisecond {
+		t.Fatalf("Expected the first wait in [50ms, 100ms), got %s", w)
+	}
+	if w := (*waits)[1]; w < 75*time.Millisecond || w >= 150*time.Millisecond {
+		t.Fatalf("Expected the second wait capped by the max backoff, got %s", w)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	fake := &flakyLLM{failures: 5, err: &ProviderError{StatusCode: http.StatusTooManyRequests}}
+	r, _ := newTestRetrier(t, fake, RetryConfig{MaxAttempts: 3, InitialBackoff: "1ms"})
+
+	if _, _, err := r.Pic(context.Background(), ""); err == nil {
+		t.Fatal("Expected the retries to give up")
+	}
+	if fake.calls != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", fake.calls)
+	}
+}
+
+func TestRetrySkipsPermanentErrors(t *testing.T) {
+	fake := &flakyLLM{failures: 1, err: &ProviderError{StatusCode: http.StatusBadRequest}}
+	r, _ := newTestRetrier(t, fake, RetryConfig{})
+
+	if _, _, err := r.Text(context.Background(), "", ""); err == nil {
+		t.Fatal("Expected a bad request to fail")
+	}
+	if fake.calls != 1 {
+		t.Fatalf("Expected no retry of a bad request, got %d calls", fake.calls)
+	}
+}
+
+func TestRetryRespectsRetryAfter(t *testing.T) {
+	fake := &flakyLLM{failures: 1, err: &ProviderError{StatusCode: http.StatusTooManyRequests, RetryAfter: 7 * time.Second}}
+	r, waits := newTestRetrier(t, fake, RetryConfig{})
+
+	if _, _, err := r.Text(context.Background(), "", ""); err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Fatalf("Expected to wait the Retry-After, got %v", *waits)
+	}
+}
+
+func TestRetryGivesUpOnLongRetryAfter(t *testing.T) {
+	providerErr := &ProviderError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}
+	fake := &flakyLLM{failures: 1, err: providerErr}
+	r, waits := newTestRetrier(t, fake, RetryConfig{MaxBackoff: "30s"})
+
+	_, _, err := r.Text(context.Background(), "", "")
+	if !errors.Is(err, providerErr) || fake.calls != 1 || len(*waits) != 0 {
+		t.Fatalf("Expected to give up without waiting, got %v after %d calls, waits %v", err, fake.calls, *waits)
+	}
+}
+
+func TestRetryTimeoutPerCall(t *testing.T) {
+	fake := &flakyLLM{failures: 1, block: true}
+	r, _ := newTestRetrier(t, fake, RetryConfig{TextTimeout: "10ms", PicTimeout: "1h"})
+
+	start := time.Now()
+	if _, _, err := r.Text(context.Background(), "", ""); err != nil {
+		t.Fatalf("Expected the timed out attempt to be retried: %v", err)
+	}
+	if fake.calls != 2 || time.Since(start) > time.Second {
+		t.Fatalf("Unexpected retry of the timeout: %d calls in %s", fake.calls, time.Since(start))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fake = &flakyLLM{failures: 1, block: true}
+	r, _ = newTestRetrier(t, fake, RetryConfig{})
+	if _, _, err := r.Pic(ctx, ""); !errors.Is(err, context.Canceled) || fake.calls != 1 {
+		t.Fatalf("Expected a canceled call not to be retried, got %v after %d calls", err, fake.calls)
+	}
+}
+
+func TestRetryConfigValidate(t *testing.T) {
+	for _, c := range []RetryConfig{{MaxAttempts: -1}, {InitialBackoff: "soon"}, {PicTimeout: "-1s"}} {
+		if err := c.Validate(); err == nil {
+			t.Fatalf("Expected %+v to fail", c)
+		}
+	}
+	if err := (RetryConfig{MaxAttempts: 1, TextTimeout: "30s"}).Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.io/uberate/hcli/pkg/hctx"
+	"slices"
+	"strings"
+)
+
+// The tasks the calls are routed by. TaskText and TaskPic are the routes of the text and pic calls without a
+// route of their own task.
+const (
+	TaskText      = "text"
+	TaskPic       = "pic"
+	TaskSummary   = "summary"
+	TaskTranslate = "translate"
+	TaskRewrite   = "rewrite"
+	TaskSuggest   = "suggest"
+)
+
+// DefaultProfile is the name of the profile made of the Provider and VolcEngineConfig of the config.
+const DefaultProfile = "default"
+
+var tasks = []string{TaskText, TaskPic, TaskSummary, TaskTranslate, TaskRewrite, TaskSuggest}
+
+// Profile is a named model setup the tasks are routed to.
+type Profile struct {
+	Name             string           `yaml:"Name" describe:"The name of the profile, used by the routes"`
+	Provider         string           `yaml:"Provider" describe:"The provider of LLM, support: volc"`
+	VolcEngineConfig VolcEngineConfig `yaml:"VolcEngineConfig" describe:"VolcEngineConfig"`
+}
+
+// configured reports whether the profile can make the calls of kind, TaskText or TaskPic.
+func (p Profile) configured(kind string) bool {
+	switch p.Provider {
+	case VolcEngineLLMKey:
+		if p.VolcEngineConfig.ApiKey == "" {
+			return false
+		}
+		if kind == TaskPic {
+			return p.VolcEngineConfig.PicModel != ""
+		}
+		return p.VolcEngineConfig.TextModel != ""
+	}
+
+	return false
+}
+
+// Route is the ordered fallback list of profiles of a task.
+type Route struct {
+	Task     string   `yaml:"Task" describe:"The task, support: text, pic, summary, translate, rewrite,\nsuggest"`
+	Profiles []string `yaml:"Profiles" describe:"The profiles in order, the next one is used if a profile fails or\nisn't configured for the call"`
+}
+
+type taskKey struct{}
+
+// WithTask sets the task the calls of ctx are routed by.
+func WithTask(ctx context.Context, task string) context.Context {
+	return context.WithValue(ctx, taskKey{}, task)
+}
+
+func taskOf(ctx context.Context) string {
+	task, _ := ctx.Value(taskKey{}).(string)
+	return task
+}
+
+// routed is a profile with its tools.
+type routed struct {
+	Profile
+	tools LLMTools
+}
+
+// Router sends every call to the profiles of its task in order, until one succeeds.
+type Router struct {
+	profiles map[string]routed
+	routes   map[string][]string
+}
+
+// NewRouter creates the router of the profiles and routes of c. The Provider of c, if set, is the profile
+// 'default', which the tasks without a route use.
+func NewRouter(c Config) (*Router, error) {
+	profiles := c.Profiles
+	if c.Provider != "" {
+		profiles = append([]Profile{{
+			Name:             DefaultProfile,
+			Provider:         c.Provider,
+			VolcEngineConfig: c.VolcEngineConfig,
+		}}, profiles...)
+	}
+
+	var rs []routed
+	for _, p := range profiles {
+		tools, err := newProfileLLM(c, p)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %w", p.Name, err)
+		}
+		rs = append(rs, routed{Profile: p, tools: tools})
+	}
+
+	return newRouter(rs, c.Routes)
+}
+
+func newRouter(profiles []routed, routes []Route) (*Router, error) {
+	r := &Router{profiles: map[string]routed{}, routes: map[string][]string{}}
+	for _, p := range profiles {
+		if p.Name == "" {
+			return nil, errors.New("profile without name")
+		}
+		if _, ok := r.profiles[p.Name]; ok {
+			return nil, fmt.Errorf("duplicate profile %s", p.Name)
+		}
+		r.profiles[p.Name] = p
+	}
+
+	for _, route := range routes {
+		if !slices.Contains(tasks, route.Task) {
+			return nil, fmt.Errorf("invalid route task '%s', support: %s",
+				route.Task, strings.Join(tasks, ", "))
+		}
+		if _, ok := r.routes[route.Task]; ok {
+			return nil, fmt.Errorf("duplicate route of task %s", route.Task)
+		}
+		if len(route.Profiles) == 0 {
+			return nil, fmt.Errorf("route of task %s has no profiles", route.Task)
+		}
+		for _, name := range route.Profiles {
+			if _, ok := r.profiles[name]; !ok {
+				return nil, fmt.Errorf("route of task %s uses unknown profile %s", route.Task, name)
+			}
+		}
+		r.routes[route.Task] = route.Profiles
+	}
+
+	return r, nil
+}
+
+// chain returns the profiles configured for the call of kind, in order.
+func (r *Router) chain(ctx context.Context, kind string) ([]routed, error) {
+	task := taskOf(ctx)
+	if task == "" {
+		task = kind
+	}
+
+	names, ok := r.routes[task]
+	if !ok {
+		names, ok = r.routes[kind]
+	}
+	if !ok {
+		names = []string{DefaultProfile}
+	}
+
+	var res []routed
+	for _, name := range names {
+		p, ok := r.profiles[name]
+		if !ok || !p.configured(kind) {
+			hctx.Debug(ctx, "profile %s isn't configured for %s, skipped", name, kind)
+			continue
+		}
+		res = append(res, p)
+	}
+
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no profile configured for task %s, tried: %v", task, names)
+	}
+	return res, nil
+}
+
+func (r *Router) Text(ctx context.Context, sysPrompt, input string) (string, Usage, error) {
+	var res string
+	u, err := r.do(ctx, TaskText, func(tools LLMTools) (Usage, error) {
+		var u Usage
+		var err error
+		res, u, err = tools.Text(ctx, sysPrompt, input)
+		return u, err
+	}, nil)
+	return res, u, err
+}
+
+// TextStream falls back to the next profile only until a chunk has been passed to onChunk.
+func (r *Router) TextStream(ctx context.Context, sysPrompt, input string,
+	onChunk func(chunk string)) (string, Usage, error) {
+
+	var res string
+	streamed := false
+	u, err := r.do(ctx, TaskText, func(tools LLMTools) (Usage, error) {
+		var u Usage
+		var err error
+		res, u, err = TextStream(ctx, tools, sysPrompt, input, func(chunk string) {
+			streamed = true
+			onChunk(chunk)
+		})
+		return u, err
+	}, func() bool {
+		return streamed
+	})
+	return res, u, err
+}
+
+func (r *Router) Pic(ctx context.Context, input string) ([]byte, Usage, error) {
+	var res []byte
+	u, err := r.do(ctx, TaskPic, func(tools LLMTools) (Usage, error) {
+		var u Usage
+		var err error
+		res, u, err = tools.Pic(ctx, input)
+		return u, err
+	}, nil)
+	return res, u, err
+}
+
+// do calls the profiles of the chain in order until one succeeds. The usage is the one of the last call, with the
+// calls of the other providers and models in its Attempts. A non-nil final stops the fallback when it returns true.
+func (r *Router) do(ctx context.Context, kind string, call func(tools LLMTools) (Usage, error),
+	final func() bool) (Usage, error) {
+
+	chain, err := r.chain(ctx, kind)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	var total Usage
+	for i, p := range chain {
+		u, err := call(p.tools)
+		total = addUsage(total, u)
+		if err == nil {
+			return total, nil
+		}
+
+		if i == len(chain)-1 || ctx.Err() != nil || (final != nil && final()) {
+			return total, err
+		}
+		hctx.Warn(ctx, "profile %s failed, fall back to %s: %v", p.Name, chain[i+1].Name, err)
+	}
+
+	return total, nil
+}
@@ -0,0 +1,125 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func testProfile(name string, tools LLMTools) routed {
+	return routed{
+		Profile: Profile{Name: name, Provider: VolcEngineLLMKey, VolcEngineConfig: VolcEngineConfig{
+			ApiKey: "key", TextModel: name + "-text", PicModel: name + "-pic",
+		}},
+		tools: tools,
+	}
+}
+
+func TestRouterRoutesByTask(t *testing.T) {
+	def, cheap := &flakyLLM{}, &flakyLLM{}
+	r, err := newRouter([]routed{testProfile(DefaultProfile, def), testProfile("cheap", cheap)},
+		[]Route{{Task: TaskSummary, Profiles: []string{"cheap"}}})
+	if err != nil {
+		t.Fatalf("newRouter failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, _, err = r.Text(WithTask(ctx, TaskSummary), "", ""); err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if _, _, err = r.Text(WithTask(ctx, TaskTranslate), "", ""); err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if _, _, err = r.Pic(ctx, ""); err != nil {
+		t.Fatalf("Pic failed: %v", err)
+	}
+	if cheap.calls != 1 || def.calls != 2 {
+		t.Fatalf("Expected the summary on cheap and the rest on default, got %d and %d", cheap.calls, def.calls)
+	}
+}
+
+func TestRouterFallsBack(t *testing.T) {
+	broken := &flakyLLM{failures: 1, err: &ProviderError{StatusCode: http.StatusServiceUnavailable}, model: "broken"}
+	backup := &flakyLLM{model: "backup"}
+	unconfigured := testProfile("pic-only", &flakyLLM{})
+	unconfigured.VolcEngineConfig.TextModel = ""
+
+	r, err := newRouter([]routed{testProfile("broken", broken), testProfile("backup", backup), unconfigured},
+		[]Route{{Task: TaskText, Profiles: []string{"pic-only", "broken", "backup"}}})
+	if err != nil {
+		t.Fatalf("newRouter failed: %v", err)
+	}
+
+	res, u, err := r.Text(context.Background(), "", "")
+	if err != nil || res != "ok" {
+		t.Fatalf("Expected the backup to answer, got %q, %v", res, err)
+	}
+	if broken.calls != 1 || backup.calls != 1 {
+		t.Fatalf("Unexpected calls %d, %d", broken.calls, backup.calls)
+	}
+	// the failed attempt is attributed to the broken model, not summed into the backup one.
+	if parts := u.Split(); len(parts) != 2 || parts[0].Model != "broken" || parts[1].Model != "backup" ||
+		parts[0].PromptTokens != 1 || parts[1].PromptTokens != 1 {
+		t.Fatalf("Unexpected usage %+v", parts)
+	}
+
+	if _, _, err = r.Pic(context.Background(), ""); err == nil || !strings.Contains(err.Error(), "no profile") {
+		t.Fatalf("Expected no profile of the pic calls, got %v", err)
+	}
+}
+
+func TestRouterStreamFallback(t *testing.T) {
+	failing := &streamLLM{flakyLLM: flakyLLM{failures: 1, err: errors.New("boom")}, chunkFirst: true}
+	backup := &streamLLM{}
+	r, err := newRouter([]routed{testProfile("a", failing), testProfile("b", backup)},
+		[]Route{{Task: TaskText, Profiles: []string{"a", "b"}}})
+	if err != nil {
+		t.Fatalf("newRouter failed: %v", err)
+	}
+
+	var chunks []string
+	if _, _, err = r.TextStream(context.Background(), "", "", collect(&chunks)); err == nil {
+		t.Fatal("Expected no fallback after a chunk was streamed")
+	}
+	if backup.calls != 0 {
+		t.Fatalf("Unexpected backup calls %d", backup.calls)
+	}
+}
+
+func TestNewRouterValidates(t *testing.T) {
+	profiles := []routed{testProfile("a", &flakyLLM{})}
+	for _, routes := range [][]Route{
+		{{Task: "poem", Profiles: []string{"a"}}},
+		{{Task: TaskText, Profiles: []string{"b"}}},
+		{{Task: TaskText}},
+		{{Task: TaskText, Profiles: []string{"a"}}, {Task: TaskText, Profiles: []string{"a"}}},
+	} {
+		if _, err := newRouter(profiles, routes); err == nil {
+			t.Fatalf("Expected routes %+v to be invalid", routes)
+		}
+	}
+
+	if _, err := newRouter(append(profiles, profiles...), nil); err == nil {
+		t.Fatal("Expected duplicate profiles to be invalid")
+	}
+}
+
+func TestNewLLMWithProfiles(t *testing.T) {
+	tools, err := NewLLM(Config{
+		Cache:    CacheConfig{Disabled: true},
+		Profiles: []Profile{{Name: "a", Provider: VolcEngineLLMKey}},
+		Routes:   []Route{{Task: TaskText, Profiles: []string{"a"}}},
+	})
+	if err != nil {
+		t.Fatalf("NewLLM failed: %v", err)
+	}
+	if _, ok := tools.(*Router); !ok {
+		t.Fatalf("Expected a router, got %T", tools)
+	}
+
+	if _, err = NewLLM(Config{Profiles: []Profile{{Name: "a", Provider: "other"}}}); err == nil {
+		t.Fatal("Expected an invalid provider error")
+	}
+}
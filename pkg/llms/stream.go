@@ -0,0 +1,29 @@
+package llms
+
+import (
+	"context"
+)
+
+// StreamTools are the LLM tools which can stream the text completions.
+type StreamTools interface {
+	LLMTools
+	// TextStream is Text which calls onChunk with the chunks of the completion as they arrive. The returned text is
+	// the whole completion, the same as Text returns.
+	TextStream(ctx context.Context, sysPrompt, input string, onChunk func(chunk string)) (string, Usage, error)
+}
+
+// TextStream streams the completion of tools to onChunk. Tools without streaming, and a nil onChunk, fall back to
+// Text, the whole completion is then passed to onChunk at once.
+func TextStream(ctx context.Context, tools LLMTools, sysPrompt, input string,
+	onChunk func(chunk string)) (string, Usage, error) {
+
+	if st, ok := tools.(StreamTools); ok && onChunk != nil {
+		return st.TextStream(ctx, sysPrompt, input, onChunk)
+	}
+
+	res, u, err := tools.Text(ctx, sysPrompt, input)
+	if err == nil && onChunk != nil {
+		onChunk(res)
+	}
+	return res, u, err
+}
@@ -0,0 +1,96 @@
+package llms
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// streamLLM streams "o" and "k" as two chunks. With chunkFirst the failing calls send "o" before they fail.
+type streamLLM struct {
+	flakyLLM
+	chunkFirst bool
+}
+
+func (s *streamLLM) TextStream(ctx context.Context, sysPrompt, input string,
+	onChunk func(chunk string)) (string, Usage, error) {
+
+	if s.chunkFirst {
+		onChunk("o")
+	}
+	u, err := s.call(ctx)
+	if err != nil {
+		return "o", u, err
+	}
+	if !s.chunkFirst {
+		onChunk("o")
+	}
+	onChunk("k")
+	return "ok", u, nil
+}
+
+func collect(chunks *[]string) func(string) {
+	return func(chunk string) {
+		*chunks = append(*chunks, chunk)
+	}
+}
+
+func TestTextStream(t *testing.T) {
+	var chunks []string
+	res, _, err := TextStream(context.Background(), &streamLLM{}, "", "", collect(&chunks))
+	if err != nil || res != "ok" || strings.Join(chunks, "|") != "o|k" {
+		t.Fatalf("Unexpected stream: %q %q %v", res, chunks, err)
+	}
+
+	chunks = nil
+	res, _, err = TextStream(context.Background(), &flakyLLM{}, "", "", collect(&chunks))
+	if err != nil || res != "ok" || strings.Join(chunks, "|") != "ok" {
+		t.Fatalf("Expected the whole text as one chunk without streaming: %q %q %v", res, chunks, err)
+	}
+
+	if res, _, err = TextStream(context.Background(), &streamLLM{}, "", "", nil); err != nil || res != "ok" {
+		t.Fatalf("Unexpected text without onChunk: %q %v", res, err)
+	}
+}
+
+func TestRetryStream(t *testing.T) {
+	fake := &streamLLM{flakyLLM: flakyLLM{failures: 2, err: &ProviderError{StatusCode: http.StatusBadGateway}}}
+	r, _ := newTestRetrier(t, fake, RetryConfig{})
+
+	var chunks []string
+	res, u, err := r.TextStream(context.Background(), "", "", collect(&chunks))
+	if err != nil || res != "ok" || strings.Join(chunks, "") != "ok" {
+		t.Fatalf("Expected a retried stream, got %q %q %v", res, chunks, err)
+	}
+	if fake.calls != 3 || u.PromptTokens != 3 {
+		t.Fatalf("Expected 3 calls, got %d, usage %+v", fake.calls, u)
+	}
+
+	fake = &streamLLM{flakyLLM: flakyLLM{failures: 1, err: &ProviderError{StatusCode: http.StatusBadGateway}},
+		chunkFirst: true}
+	r, _ = newTestRetrier(t, fake, RetryConfig{})
+	chunks = nil
+	if _, _, err = r.TextStream(context.Background(), "", "", collect(&chunks)); err == nil {
+		t.Fatal("Expected no retry after a chunk was streamed")
+	}
+	if fake.calls != 1 || strings.Join(chunks, "") != "o" {
+		t.Fatalf("Unexpected calls %d and chunks %q", fake.calls, chunks)
+	}
+}
+
+func TestCacheStream(t *testing.T) {
+	fake := &streamLLM{}
+	tools := newTestCache(t, fake).(StreamTools)
+
+	for i := 0; i < 2; i++ {
+		var chunks []string
+		res, u, err := tools.TextStream(context.Background(), "sys", "in", collect(&chunks))
+		if err != nil || res != "ok" || strings.Join(chunks, "") != "ok" || u.Cached != (i == 1) {
+			t.Fatalf("Unexpected stream %d: %q %q %+v %v", i, res, chunks, u, err)
+		}
+	}
+	if fake.calls != 1 {
+		t.Fatalf("Expected the second stream from the cache, got %d calls", fake.calls)
+	}
+}
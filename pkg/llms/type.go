@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"os"
+	"slices"
 )
 
 type Config struct {
 	Provider         string           `yaml:"Provider" describe:"The provider of LLM, support: volc"`
 	VolcEngineConfig VolcEngineConfig `yaml:"VolcEngineConfig" describe:"VolcEngineConfig"`
+	Retry            RetryConfig      `yaml:"Retry" describe:"The retry, backoff and timeout policy of the calls"`
+	Cache            CacheConfig      `yaml:"Cache" describe:"The on-disk cache of the responses"`
+	Profiles         []Profile        `yaml:"Profiles" describe:"The named model profiles the tasks are routed to"`
+	Routes           []Route          `yaml:"Routes" describe:"The profiles of the tasks, the tasks without a route use the\nroute of text or pic, then the profile 'default' made of Provider"`
 }
 
 const (
@@ -16,22 +21,74 @@ const (
 )
 
 type LLMTools interface {
-	Text(ctx context.Context, sysPrompt, input string) (resp string, err error)
-	Pic(ctx context.Context, input string) (resp []byte, err error)
+	Text(ctx context.Context, sysPrompt, input string) (resp string, usage Usage, err error)
+	Pic(ctx context.Context, input string) (resp []byte, usage Usage, err error)
+}
+
+// Usage is the metered usage of one LLM call, as reported by the provider.
+type Usage struct {
+	Provider         string `json:"provider"`
+	Model            string `json:"model"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	Images           int    `json:"images"`
+	// Cached is true if the response came from the cache, the call cost nothing.
+	Cached bool `json:"cached,omitempty"`
+	// Attempts are the usages of the failed attempts served by another provider or model before this one, as the
+	// profiles a Router fell back from. Their tokens are not counted in this usage.
+	Attempts []Usage `json:"-"`
+}
+
+// Split returns the attempts and the usage itself, each attributed to the provider and the model which served it.
+func (u Usage) Split() []Usage {
+	res := make([]Usage, 0, len(u.Attempts)+1)
+	for _, a := range u.Attempts {
+		res = append(res, a.Split()...)
+	}
+	u.Attempts = nil
+	return append(res, u)
 }
 
 func NewLLM(c Config) (LLMTools, error) {
-	switch c.Provider {
+	if len(c.Profiles) != 0 || len(c.Routes) != 0 {
+		return NewRouter(c)
+	}
+
+	return newProfileLLM(c, Profile{Provider: c.Provider, VolcEngineConfig: c.VolcEngineConfig})
+}
+
+// newProfileLLM creates the tools of p with the retry and cache of c.
+func newProfileLLM(c Config, p Profile) (LLMTools, error) {
+	switch p.Provider {
 	case VolcEngineLLMKey:
-		return NewVolcEngineLLM(c.VolcEngineConfig), nil
+		tools, err := WithRetry(NewVolcEngineLLM(p.VolcEngineConfig), c.Retry)
+		if err != nil {
+			return nil, err
+		}
+		return withConfigCache(tools, c, p.Provider, p.VolcEngineConfig.TextModel, p.VolcEngineConfig.PicModel)
 	}
 
 	return nil, errors.New("invalid provider, support: [volc]")
 }
 
+// withConfigCache wraps tools with the cache of c, unless it is disabled.
+func withConfigCache(tools LLMTools, c Config, provider, textModel, picModel string) (LLMTools, error) {
+	if c.Cache.Disabled {
+		return tools, nil
+	}
+
+	store, err := c.Cache.Store()
+	if err != nil {
+		return nil, err
+	}
+
+	return WithCache(tools, store, provider, textModel, picModel), nil
+}
+
+// NewLLMWithAutoEnv is NewLLM with the missing volc settings read from the environment, the profiles only read the
+// API key.
 func NewLLMWithAutoEnv(c Config) (LLMTools, error) {
-	switch c.Provider {
-	case VolcEngineLLMKey:
+	if c.Provider == VolcEngineLLMKey {
 		if c.VolcEngineConfig.ApiKey == "" {
 			c.VolcEngineConfig.ApiKey = os.Getenv("VOLC_API_KEY")
 		}
@@ -41,9 +98,14 @@ func NewLLMWithAutoEnv(c Config) (LLMTools, error) {
 		if c.VolcEngineConfig.PicModel == "" {
 			c.VolcEngineConfig.PicModel = os.Getenv("PIC_MODEL_ID")
 		}
+	}
 
-		return NewVolcEngineLLM(c.VolcEngineConfig), nil
+	c.Profiles = slices.Clone(c.Profiles)
+	for i, p := range c.Profiles {
+		if p.Provider == VolcEngineLLMKey && p.VolcEngineConfig.ApiKey == "" {
+			c.Profiles[i].VolcEngineConfig.ApiKey = os.Getenv("VOLC_API_KEY")
+		}
 	}
 
-	return nil, errors.New("invalid provider, support: [volc]")
+	return NewLLM(c)
 }
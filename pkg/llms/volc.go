@@ -3,9 +3,15 @@ package llms
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/volcengine/volcengine-go-sdk/service/arkruntime"
 	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 )
 
 type VolcEngineConfig struct {
@@ -15,7 +21,8 @@ type VolcEngineConfig struct {
 }
 
 func NewVolcEngineLLM(vec VolcEngineConfig) LLMTools {
-	client := arkruntime.NewClientWithApiKey(vec.ApiKey)
+	// the retry of the calls is done by WithRetry
+	client := arkruntime.NewClientWithApiKey(vec.ApiKey, arkruntime.WithRetryTimes(0))
 
 	res := &VolcEngineLLM{
 		client,
@@ -33,29 +40,97 @@ type VolcEngineLLM struct {
 	picModel  string
 }
 
-func (vel *VolcEngineLLM) Text(ctx context.Context, sysPrompt, input string) (resp string, err error) {
-	res, err := vel.c.CreateChatCompletion(ctx, model.CreateChatCompletionRequest{
+func (vel *VolcEngineLLM) Text(ctx context.Context, sysPrompt, input string) (resp string, usage Usage, err error) {
+	usage = Usage{Provider: VolcEngineLLMKey, Model: vel.textModel}
+	res, err := vel.c.CreateChatCompletion(ctx, vel.textRequest(ctx, sysPrompt, input))
+
+	if err != nil {
+		return "", usage, volcError(err, res.GetHeader())
+	}
+
+	usage.PromptTokens = res.Usage.PromptTokens
+	usage.CompletionTokens = res.Usage.CompletionTokens
+
+	if len(res.Choices) == 0 {
+		return "", usage, fmt.Errorf("no response choices received from VolcEngine LLM - check API key and model configuration")
+	}
+
+	return *res.Choices[0].Message.Content.StringValue, usage, nil
+}
+
+func (vel *VolcEngineLLM) TextStream(ctx context.Context, sysPrompt, input string,
+	onChunk func(chunk string)) (resp string, usage Usage, err error) {
+
+	usage = Usage{Provider: VolcEngineLLMKey, Model: vel.textModel}
+	req := vel.textRequest(ctx, sysPrompt, input)
+	req.StreamOptions = &model.StreamOptions{IncludeUsage: true}
+
+	stream, err := vel.c.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return "", usage, volcError(err, nil)
+	}
+	defer stream.Close()
+
+	var sb strings.Builder
+	received := false
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return sb.String(), usage, volcError(err, nil)
+		}
+
+		if chunk.Usage != nil {
+			usage.PromptTokens = chunk.Usage.PromptTokens
+			usage.CompletionTokens = chunk.Usage.CompletionTokens
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		received = true
+		if delta := chunk.Choices[0].Delta.Content; delta != "" {
+			sb.WriteString(delta)
+			onChunk(delta)
+		}
+	}
+
+	if !received {
+		return "", usage, fmt.Errorf("no response choices received from VolcEngine LLM - check API key and model configuration")
+	}
+
+	return sb.String(), usage, nil
+}
+
+// textRequest builds the request of a text call, with the JSON mode if ctx asks for a JSON answer.
+func (vel *VolcEngineLLM) textRequest(ctx context.Context, sysPrompt, input string) model.CreateChatCompletionRequest {
+	req := model.CreateChatCompletionRequest{
 		Model: vel.textModel,
 		Messages: []*model.ChatCompletionMessage{
 			{Role: messageRoleSystem, Content: &model.ChatCompletionMessageContent{StringValue: &sysPrompt}},
 			{Role: messageRoleUser, Content: &model.ChatCompletionMessageContent{StringValue: &input}},
 		},
-	})
-
-	if err != nil {
-		return "", err
 	}
 
-	if len(res.Choices) == 0 {
-		return "", fmt.Errorf("no response choices received from VolcEngine LLM - check API key and model configuration")
+	if s := jsonSchemaOf(ctx); s != nil {
+		req.ResponseFormat = &model.ResponseFormat{
+			Type: model.ResponseFormatJSONSchema,
+			JSONSchema: &model.ResponseFormatJSONSchemaJSONSchemaParam{
+				Name:   s.name,
+				Schema: s.schema,
+			},
+		}
 	}
 
-	return *res.Choices[0].Message.Content.StringValue, nil
+	return req
 }
 
-func (vel *VolcEngineLLM) Pic(ctx context.Context, input string) (resp []byte, err error) {
+func (vel *VolcEngineLLM) Pic(ctx context.Context, input string) (resp []byte, usage Usage, err error) {
+	usage = Usage{Provider: VolcEngineLLMKey, Model: vel.picModel}
 	if vel.picModel == "" {
-		return nil, fmt.Errorf("no pic model specified for VolcEngine LLM")
+		return nil, usage, fmt.Errorf("no pic model specified for VolcEngine LLM")
 	}
 
 	form := model.GenerateImagesResponseFormatBase64
@@ -66,24 +141,56 @@ func (vel *VolcEngineLLM) Pic(ctx context.Context, input string) (resp []byte, e
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, usage, volcError(err, result.GetHeader())
+	}
+
+	usage.Images = len(result.Data)
+	if result.Usage != nil {
+		usage.Images = int(result.Usage.GeneratedImages)
 	}
 
 	if len(result.Data) == 0 {
-		return nil, fmt.Errorf("no image data received from VolcEngine LLM - check API key and model configuration")
+		return nil, usage, fmt.Errorf("no image data received from VolcEngine LLM - check API key and model configuration")
 	}
 
 	if result.Data[0].B64Json == nil {
-		return nil, fmt.Errorf("iamge data format invalid - expected base64 encoded image but received nil")
+		return nil, usage, fmt.Errorf("iamge data format invalid - expected base64 encoded image but received nil")
 	}
 
 	// Decode base64 image data
 	imageData, err := base64.StdEncoding.DecodeString(*result.Data[0].B64Json)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode nase64 image data: %w", err)
+		return nil, usage, fmt.Errorf("failed to decode nase64 image data: %w", err)
+	}
+
+	return imageData, usage, nil
+}
+
+// volcError converts the HTTP errors of the SDK to ProviderError, other errors are returned as is.
+func volcError(err error, header http.Header) error {
+	pe := &ProviderError{Err: err}
+
+	var apiErr *model.APIError
+	var reqErr *model.RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		pe.StatusCode = apiErr.HTTPStatusCode
+	case errors.As(err, &reqErr) && reqErr.Err != nil && !isTransportError(reqErr.Err):
+		pe.StatusCode = reqErr.HTTPStatusCode
+	default:
+		return err
+	}
+
+	if header != nil {
+		pe.RetryAfter = ParseRetryAfter(header.Get("Retry-After"), time.Now())
 	}
+	return pe
+}
 
-	return imageData, nil
+// isTransportError reports whether err is from sending the request, which the SDK reports as status 500.
+func isTransportError(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
 }
 
 const (
@@ -0,0 +1,167 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	headingPattern  = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?[ \t]*$`)
+	closingPattern  = regexp.MustCompile(`(?:^|[ \t]+)#+$`)
+	customIDPattern = regexp.MustCompile(`[ \t]*\{#([^}\s]+)\}$`)
+)
+
+// CodeBlocks finds the fenced code blocks of lines from the index from. code reports whether a line is in a block,
+// fences included.
+func CodeBlocks(lines []string, from int) (code []bool, fences []Fence) {
+	code = make([]bool, len(lines))
+
+	var open string
+	for i := from; i < len(lines); i++ {
+		trimmed := strings.TrimLeft(lines[i], " ")
+		indent := len(lines[i]) - len(trimmed)
+		if open != "" {
+			code[i] = true
+			if indent < 4 && strings.HasPrefix(trimmed, open) && strings.Trim(trimmed, open[:1]+" \t\r") == "" {
+				open = ""
+			}
+			continue
+		}
+
+		if indent >= 4 {
+			continue
+		}
+		if open = fenceMarker(trimmed); open == "" {
+			continue
+		}
+		code[i] = true
+		fences = append(fences, Fence{
+			Line:   i,
+			Indent: indent,
+			Info:   strings.TrimSpace(strings.TrimLeft(trimmed, open[:1])),
+		})
+	}
+
+	return code, fences
+}
+
+// fenceMarker returns the backticks or tildes opening a code block at the start of line, empty if there is none.
+func fenceMarker(line string) string {
+	for _, c := range []string{"`", "~"} {
+		n := len(line) - len(strings.TrimLeft(line, c))
+		if n < 3 {
+			continue
+		}
+		// the info string of a backtick fence can't contain backticks
+		if c == "`" && strings.Contains(line[n:], "`") {
+			return ""
+		}
+		return line[:n]
+	}
+	return ""
+}
+
+// InlineCode returns the byte ranges of the code spans of line, backticks included.
+func InlineCode(line string) [][2]int {
+	var res [][2]int
+	for i := 0; i < len(line); {
+		if line[i] != '`' {
+			i++
+			continue
+		}
+		n := 1
+		for i+n < len(line) && line[i+n] == '`' {
+			n++
+		}
+		end := strings.Index(line[i+n:], strings.Repeat("`", n))
+		if end < 0 {
+			break
+		}
+		end += i + n + n
+		res = append(res, [2]int{i, end})
+		i = end
+	}
+	return res
+}
+
+// MaskInlineCode replaces the code spans of line with spaces, the byte offsets are kept.
+func MaskInlineCode(line string) string {
+	spans := InlineCode(line)
+	if len(spans) == 0 {
+		return line
+	}
+
+	b := []byte(line)
+	for _, span := range spans {
+		for j := span[0]; j < span[1]; j++ {
+			b[j] = ' '
+		}
+	}
+	return string(b)
+}
+
+// ParseHeading parses the ATX heading of line, the second result is false if line isn't a heading.
+func ParseHeading(line string) (Heading, bool) {
+	m := headingPattern.FindStringSubmatchIndex(line)
+	if m == nil {
+		return Heading{}, false
+	}
+
+	h := Heading{Offset: m[2], Level: m[3] - m[2]}
+	if m[4] >= 0 {
+		h.Text = closingPattern.ReplaceAllString(line[m[4]:m[5]], "")
+	}
+	if id := customIDPattern.FindStringSubmatch(h.Text); id != nil {
+		h.ID = id[1]
+		h.Text = strings.TrimSpace(strings.TrimSuffix(h.Text, id[0]))
+	}
+	return h, true
+}
+
+// Anchor returns the anchor of the heading, the custom id or the id Hugo generates from the text: lower case, the
+// punctuation removed and the spaces replaced by '-'.
+func (h Heading) Anchor() string {
+	if h.ID != "" {
+		return h.ID
+	}
+
+	var builder strings.Builder
+	for _, r := range strings.ToLower(stripInline(h.Text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			builder.WriteRune(r)
+		case unicode.IsSpace(r):
+			builder.WriteRune('-')
+		}
+	}
+	return builder.String()
+}
+
+var (
+	linkTextPattern = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	tagPattern      = regexp.MustCompile(`<[^>]+>`)
+)
+
+// stripInline removes the link targets and the HTML tags of text, the rendered text of a heading.
+func stripInline(text string) string {
+	text = linkTextPattern.ReplaceAllString(text, "$1")
+	return tagPattern.ReplaceAllString(text, "")
+}
+
+// Headings returns the ATX headings of lines from the index from, out of the code blocks.
+func Headings(lines []string, from int) []Heading {
+	code, _ := CodeBlocks(lines, from)
+
+	var res []Heading
+	for i := from; i < len(lines); i++ {
+		if code[i] {
+			continue
+		}
+		if h, ok := ParseHeading(strings.TrimSuffix(lines[i], "\r")); ok {
+			h.Line = i
+			res = append(res, h)
+		}
+	}
+	return res
+}
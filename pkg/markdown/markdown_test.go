@@ -0,0 +1,65 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCodeBlocks(t *testing.T) {
+	lines := strings.Split("+++\n```\n+++\ntext\n~~~~ go \n```\n~~~~\n    ```\n``` a`b\n````\n```\n````", "\n")
+
+	code, fences := CodeBlocks(lines, 3)
+	var got []string
+	for i, c := range code {
+		if c {
+			got = append(got, lines[i])
+		}
+	}
+	if strings.Join(got, "|") != "~~~~ go |```|~~~~|````|```|````" {
+		t.Fatalf("Unexpected code lines: %q", got)
+	}
+	if len(fences) != 2 || fences[0].Line != 4 || fences[0].Info != "go" || fences[1].Info != "" {
+		t.Fatalf("Unexpected fences: %+v", fences)
+	}
+}
+
+func TestInlineCode(t *testing.T) {
+	line := "a `b` c ``d`e`` f `g"
+	if got := MaskInlineCode(line); got != "a     c         f `g" {
+		t.Fatalf("Unexpected masked line: %q", got)
+	}
+	if spans := InlineCode("中`x`"); len(spans) != 1 || spans[0] != [2]int{3, 6} {
+		t.Fatalf("Expected byte ranges, got %v", spans)
+	}
+}
+
+func TestParseHeading(t *testing.T) {
+	for line, want := range map[string]string{
+		"## Hello, World! ##":             "2 hello-world",
+		"  # Go 的 `CLI` 工具":               "1 go-的-cli-工具",
+		"### [Link](x.md) and <b>tag</b>": "3 link-and-tag",
+		"#### Custom {#my-id}":            "4 my-id",
+		"# C# #":                          "1 c",
+		"#":                               "1 ",
+	} {
+		h, ok := ParseHeading(line)
+		if got := fmt.Sprintf("%d %s", h.Level, h.Anchor()); !ok || got != want {
+			t.Fatalf("Unexpected heading of %q: %s, want %s", line, got, want)
+		}
+	}
+
+	for _, line := range []string{"#tag", "####### seven", "    # code"} {
+		if _, ok := ParseHeading(line); ok {
+			t.Fatalf("Expected %q not a heading", line)
+		}
+	}
+}
+
+func TestHeadings(t *testing.T) {
+	lines := strings.Split("# A\r\n```\n# not\n```\n## B", "\n")
+	headings := Headings(lines, 0)
+	if len(headings) != 2 || headings[0].Text != "A" || headings[1].Line != 4 {
+		t.Fatalf("Unexpected headings: %+v", headings)
+	}
+}
@@ -0,0 +1,6 @@
+// Package markdown finds the parts of Markdown posts the prose tools must skip or look into: the fenced code
+// blocks, the inline code spans and the heading anchors.
+//
+// It isn't a full CommonMark parser. It works line by line on the body of a post, which keeps the line and the
+// column of everything it finds, and is enough for the linters and formatters of hcli.
+package markdown
@@ -0,0 +1,23 @@
+package markdown
+
+// Fence is the opening line of a fenced code block.
+type Fence struct {
+	// Line is the index of the line.
+	Line int
+	// Indent is the number of spaces before the fence.
+	Indent int
+	// Info is the info string after the fence, the language of the code.
+	Info string
+}
+
+// Heading is an ATX heading.
+type Heading struct {
+	// Line is the index of the line, Offset the byte offset of the first '#'.
+	Line   int
+	Offset int
+	Level  int
+	// Text is the heading text without the custom id.
+	Text string
+	// ID is the custom id, '{#id}', empty if the heading has none.
+	ID string
+}
@@ -144,6 +144,11 @@ func (o *Outputter) Print(format string, args ...interface{}) {
 	fmt.Fprint(o.out, message)
 }
 
+// Write prints s as is, for the progressive output which ends lines itself.
+func (o *Outputter) Write(s string) {
+	fmt.Fprint(o.out, s)
+}
+
 func (o *Outputter) Println(format string, args ...interface{}) {
 	o.Print(format, args...)
 }
@@ -0,0 +1,13 @@
+// Package overlay draws the post title, the date and the site logo on a poster.
+//
+// The image models are asked for pictures without text, so the text of social cards is typeset locally and the
+// stage runs offline. Fonts are TrueType or OpenType files, use a CJK font such as Noto Sans CJK for Chinese
+// titles, the built-in Go font only covers Latin text.
+//
+// Titles are wrapped to the image width: Latin text breaks between words, CJK text breaks between any two
+// characters, and closing punctuation like '，' or '。' never starts a line. Lines beyond MaxLines are cut with
+// an ellipsis.
+//
+// The text block is placed at the top, the center or the bottom of the image, on an optional semi-transparent
+// band and with an optional drop shadow.
+package overlay
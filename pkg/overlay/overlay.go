@@ -0,0 +1,240 @@
+package overlay
+
+import (
+	"bytes"
+	"fmt"
+	"github.io/uberate/hcli/pkg/imaging"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/color"
+	"os"
+	"strings"
+)
+
+// Validate checks the values of the config.
+func (c Config) Validate() error {
+	switch c.Position {
+	case "", PositionTop, PositionCenter, PositionBottom:
+	default:
+		return fmt.Errorf("invalid overlay position '%s', support: top, center, bottom", c.Position)
+	}
+
+	switch c.LogoPosition {
+	case "", LogoTopLeft, LogoTopRight, LogoBottomLeft, LogoBottomRight:
+	default:
+		return fmt.Errorf("invalid logo position '%s', support: top-left, top-right, bottom-left, bottom-right",
+			c.LogoPosition)
+	}
+
+	for _, v := range []string{c.Color, c.ShadowColor, c.BandColor} {
+		if _, err := imaging.ParseColor(v, color.RGBA{}); err != nil {
+			return err
+		}
+	}
+
+	if c.FontSize < 0 || c.MaxLines < 0 || c.Padding < 0 || c.LogoWidth < 0 {
+		return fmt.Errorf("overlay sizes can't be negative")
+	}
+
+	return nil
+}
+
+func (c Config) withDefaults() Config {
+	if c.Position == "" {
+		c.Position = PositionBottom
+	}
+	if c.LogoPosition == "" {
+		c.LogoPosition = LogoTopRight
+	}
+	if c.MaxLines == 0 {
+		c.MaxLines = 3
+	}
+	if c.DateFormat == "" {
+		c.DateFormat = "2006-01-02"
+	}
+	return c
+}
+
+// Stage returns the imaging stage which draws text with the config.
+func Stage(c Config, text Text) imaging.Stage {
+	return func(img image.Image) (image.Image, error) {
+		return Apply(img, c, text)
+	}
+}
+
+// Apply draws the text and the logo on a copy of src.
+func Apply(src image.Image, c Config, text Text) (image.Image, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	c = c.withDefaults()
+
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+
+	if err := drawText(dst, c, text); err != nil {
+		return nil, err
+	}
+
+	if c.LogoFile != "" {
+		if err := drawLogo(dst, c); err != nil {
+			return nil, err
+		}
+	}
+
+	return dst, nil
+}
+
+func drawText(dst *image.RGBA, c Config, text Text) error {
+	if text.Title == "" && text.Date == "" && len(text.Tags) == 0 {
+		return nil
+	}
+
+	width, height := dst.Bounds().Dx(), dst.Bounds().Dy()
+	size := c.FontSize
+	if size == 0 {
+		size = float64(width) / 16
+	}
+	padding := c.Padding
+	if padding == 0 {
+		padding = int(size / 2)
+	}
+
+	titleFace, err := LoadFace(c.FontFile, size)
+	if err != nil {
+		return err
+	}
+	defer titleFace.Close()
+	dateFace, err := LoadFace(c.FontFile, size/2)
+	if err != nil {
+		return err
+	}
+	defer dateFace.Close()
+
+	type line struct {
+		text string
+		face font.Face
+	}
+	var lines []line
+	for _, l := range Wrap(titleFace, text.Title, width-2*padding, c.MaxLines) {
+		lines = append(lines, line{l, titleFace})
+	}
+	if len(text.Tags) != 0 {
+		tags := make([]string, 0, len(text.Tags))
+		for _, tag := range text.Tags {
+			tags = append(tags, "#"+tag)
+		}
+		for _, l := range Wrap(dateFace, strings.Join(tags, " "), width-2*padding, 1) {
+			lines = append(lines, line{l, dateFace})
+		}
+	}
+	if text.Date != "" {
+		lines = append(lines, line{text.Date, dateFace})
+	}
+
+	blockHeight := 0
+	for _, l := range lines {
+		blockHeight += l.face.Metrics().Height.Ceil()
+	}
+
+	top := height - padding - blockHeight
+	switch c.Position {
+	case PositionTop:
+		top = padding
+	case PositionCenter:
+		top = (height - blockHeight) / 2
+	}
+
+	textColor, _ := imaging.ParseColor(c.Color, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	shadowColor, _ := imaging.ParseColor(c.ShadowColor, color.RGBA{A: 160})
+	bandColor, _ := imaging.ParseColor(c.BandColor, color.RGBA{A: 128})
+
+	if bandColor.A != 0 {
+		band := image.Rect(0, top-padding, width, top+blockHeight+padding)
+		draw.Draw(dst, band, image.NewUniform(bandColor), image.Point{}, draw.Over)
+	}
+
+	shadowOffset := max(1, int(size/20))
+	y := top
+	for _, l := range lines {
+		baseline := y + l.face.Metrics().Ascent.Ceil()
+		if shadowColor.A != 0 {
+			drawString(dst, l.face, shadowColor, padding+shadowOffset, baseline+shadowOffset, l.text)
+		}
+		drawString(dst, l.face, textColor, padding, baseline, l.text)
+		y += l.face.Metrics().Height.Ceil()
+	}
+
+	return nil
+}
+
+func drawString(dst *image.RGBA, face font.Face, c color.Color, x, y int, s string) {
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(s)
+}
+
+func drawLogo(dst *image.RGBA, c Config) error {
+	data, err := os.ReadFile(c.LogoFile)
+	if err != nil {
+		return fmt.Errorf("read logo: %w", err)
+	}
+
+	logo, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("decode logo %s: %w", c.LogoFile, err)
+	}
+
+	width, height := dst.Bounds().Dx(), dst.Bounds().Dy()
+	logoWidth := c.LogoWidth
+	if logoWidth == 0 {
+		logoWidth = width / 8
+	}
+	logo = imaging.Resize(logo, logoWidth, 0)
+
+	margin := width / 40
+	x, y := margin, margin
+	if strings.HasSuffix(c.LogoPosition, "right") {
+		x = width - margin - logo.Bounds().Dx()
+	}
+	if strings.HasPrefix(c.LogoPosition, "bottom") {
+		y = height - margin - logo.Bounds().Dy()
+	}
+
+	draw.Draw(dst, logo.Bounds().Add(image.Pt(x, y)), logo, image.Point{}, draw.Over)
+	return nil
+}
+
+// LoadFace loads the font file with size in pixels, an empty path loads the built-in Go bold font. Font
+// collections like '.ttc' use their first font.
+func LoadFace(path string, size float64) (font.Face, error) {
+	data := gobold.TTF
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("read font: %w", err)
+		}
+	}
+
+	f, err := opentype.Parse(data)
+	if err != nil {
+		collection, collectionErr := opentype.ParseCollection(data)
+		if collectionErr != nil {
+			return nil, fmt.Errorf("parse font %s: %w", path, err)
+		}
+		if f, err = collection.Font(0); err != nil {
+			return nil, fmt.Errorf("parse font %s: %w", path, err)
+		}
+	}
+
+	return opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+}
@@ -0,0 +1,78 @@
+package overlay
+
+import (
+	"bytes"
+	"github.io/uberate/hcli/internal/golden"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func background() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 320, 160))
+	for y := 0; y < 160; y++ {
+		for x := 0; x < 320; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 255 / 320), G: 90, B: uint8(y * 255 / 160), A: 255})
+		}
+	}
+	return img
+}
+
+func TestApplyGolden(t *testing.T) {
+	logoPath := filepath.Join(t.TempDir(), "logo.png")
+	logo := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			logo.Set(x, y, color.RGBA{R: 255, G: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, logo); err != nil {
+		t.Fatalf("encode logo failed: %v", err)
+	}
+	if err := os.WriteFile(logoPath, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("write logo failed: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		config Config
+	}{
+		{"bottom_band", Config{Enabled: true, LogoFile: logoPath}},
+		{"top_plain", Config{Enabled: true, Position: PositionTop, BandColor: ColorNone, ShadowColor: ColorNone,
+			Color: "#ffcc00", FontSize: 24, MaxLines: 1}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			img, err := Apply(background(), c.config, Text{Title: "Building a blog with Hugo and hcli",
+				Date: "2026-10-19"})
+			if err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			golden.Image(t, c.name, img)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	invalid := []Config{
+		{Position: "left"},
+		{LogoPosition: "middle"},
+		{Color: "white"},
+		{BandColor: "#12345"},
+		{FontSize: -1},
+	}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Fatalf("Expected %+v to be invalid", c)
+		}
+	}
+
+	if _, err := Apply(background(), Config{FontFile: "missing.ttf"}, Text{Title: "a"}); err == nil {
+		t.Fatal("Expected a missing font to fail")
+	}
+}
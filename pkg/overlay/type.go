@@ -0,0 +1,50 @@
+package overlay
+
+import "github.io/uberate/hcli/pkg/imaging"
+
+const (
+	// ColorNone disables the shadow or the band.
+	ColorNone = imaging.ColorNone
+
+	PositionTop    = "top"
+	PositionCenter = "center"
+	PositionBottom = "bottom"
+
+	LogoTopLeft     = "top-left"
+	LogoTopRight    = "top-right"
+	LogoBottomLeft  = "bottom-left"
+	LogoBottomRight = "bottom-right"
+)
+
+// Config is the text overlay setting of a template.
+type Config struct {
+	Enabled bool `yaml:"Enabled" describe:"Whether to draw the title overlay on the poster." default:"false"`
+
+	FontFile string  `yaml:"FontFile" describe:"The TrueType or OpenType font file, use a CJK font for Chinese titles. Empty means the built-in Go font, Latin only."`
+	FontSize float64 `yaml:"FontSize" describe:"The title font size in pixels, 0 means 1/16 of the image width." default:"0"`
+	MaxLines int     `yaml:"MaxLines" describe:"The max lines of the title, longer titles end with an ellipsis." default:"3"`
+
+	Position string `yaml:"Position" describe:"The position of the text block, support: top, center, bottom." default:"bottom"`
+	Padding  int    `yaml:"Padding" describe:"The padding around the text block in pixels, 0 means half of the font size." default:"0"`
+
+	Color       string `yaml:"Color" describe:"The text color, #rrggbb or #rrggbbaa." default:"#ffffff"`
+	ShadowColor string `yaml:"ShadowColor" describe:"The text shadow color, 'none' means no shadow." default:"#000000a0"`
+	BandColor   string `yaml:"BandColor" describe:"The color of the band behind the text, 'none' means no band." default:"#00000080"`
+
+	ShowTags   bool   `yaml:"ShowTags" describe:"Whether to draw the post tags below the title." default:"false"`
+	ShowDate   bool   `yaml:"ShowDate" describe:"Whether to draw the post date below the title." default:"false"`
+	DateFormat string `yaml:"DateFormat" describe:"The go time layout of the date." default:"2006-01-02"`
+
+	LogoFile     string `yaml:"LogoFile" describe:"The png or jpeg site logo, empty means no logo."`
+	LogoWidth    int    `yaml:"LogoWidth" describe:"The logo width in pixels, 0 means 1/8 of the image width." default:"0"`
+	LogoPosition string `yaml:"LogoPosition" describe:"The logo corner, support: top-left, top-right, bottom-left, bottom-right." default:"top-right"`
+}
+
+// Text is the content drawn on the poster.
+type Text struct {
+	Title string
+	// Tags are drawn as '#tag' after the title, nil means no tags line.
+	Tags []string
+	// Date is the formatted date, empty means no date line.
+	Date string
+}
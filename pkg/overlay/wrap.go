@@ -0,0 +1,117 @@
+package overlay
+
+import (
+	"golang.org/x/image/font"
+	"strings"
+	"unicode"
+)
+
+const ellipsis = "…"
+
+// noLineStart are the closing punctuations which must not start a line.
+const noLineStart = "，。、！？；：）》」』】〉”’,.!?;:)]}%…"
+
+// Wrap breaks text into lines no wider than width with face. Latin words are kept whole unless a single word is
+// wider than width, CJK text breaks between characters. maxLines <= 0 means no limit.
+func Wrap(face font.Face, text string, width int, maxLines int) []string {
+	measure := func(s string) int {
+		return font.MeasureString(face, s).Ceil()
+	}
+
+	var lines []string
+	current := ""
+	space := false
+	for _, token := range tokenize(text) {
+		if token == " " {
+			space = current != ""
+			continue
+		}
+
+		candidate := token
+		if current != "" {
+			candidate = current + token
+			if space {
+				candidate = current + " " + token
+			}
+		}
+		space = false
+
+		switch {
+		case measure(candidate) <= width:
+			current = candidate
+		case current != "" && strings.ContainsRune(noLineStart, []rune(token)[0]):
+			// hang the punctuation at the end of the line
+			current = candidate
+		case measure(token) > width:
+			// a word wider than the line is broken by characters
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			for _, r := range token {
+				if current != "" && measure(current+string(r)) > width {
+					lines = append(lines, current)
+					current = ""
+				}
+				current += string(r)
+			}
+		default:
+			if current != "" {
+				lines = append(lines, current)
+			}
+			current = token
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+
+	if maxLines <= 0 || len(lines) <= maxLines {
+		return lines
+	}
+
+	lines = lines[:maxLines]
+	last := []rune(lines[maxLines-1])
+	for len(last) > 0 && measure(string(last)+ellipsis) > width {
+		last = last[:len(last)-1]
+	}
+	lines[maxLines-1] = strings.TrimRight(string(last), " ") + ellipsis
+
+	return lines
+}
+
+// tokenize splits text into Latin words, single CJK characters and single spaces.
+func tokenize(text string) []string {
+	var tokens []string
+	var word []rune
+
+	flush := func() {
+		if len(word) != 0 {
+			tokens = append(tokens, string(word))
+			word = word[:0]
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+			if len(tokens) != 0 && tokens[len(tokens)-1] != " " {
+				tokens = append(tokens, " ")
+			}
+		case isCJK(r):
+			flush()
+			tokens = append(tokens, string(r))
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef)
+}
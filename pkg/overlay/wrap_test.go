@@ -0,0 +1,42 @@
+package overlay
+
+import (
+	"golang.org/x/image/font/basicfont"
+	"reflect"
+	"testing"
+)
+
+// every rune of basicfont.Face7x13 is 7 pixels wide.
+var fixedFace = basicfont.Face7x13
+
+func TestWrapLatin(t *testing.T) {
+	lines := Wrap(fixedFace, "hello hugo world", 7*11, 0)
+	expected := []string{"hello hugo", "world"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("Expected %q, got %q", expected, lines)
+	}
+
+	// a word longer than the line is broken
+	lines = Wrap(fixedFace, "a abcdefgh", 7*4, 0)
+	expected = []string{"a", "abcd", "efgh"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("Expected %q, got %q", expected, lines)
+	}
+}
+
+func TestWrapCJK(t *testing.T) {
+	// the comma hangs at the line end instead of starting the next line
+	lines := Wrap(fixedFace, "使用Go语言构建博客工具，好", 7*4, 0)
+	expected := []string{"使用Go", "语言构建", "博客工具，", "好"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("Expected %q, got %q", expected, lines)
+	}
+}
+
+func TestWrapMaxLines(t *testing.T) {
+	lines := Wrap(fixedFace, "一二三四五六七八九十", 7*4, 2)
+	expected := []string{"一二三四", "五六七…"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("Expected %q, got %q", expected, lines)
+	}
+}
@@ -0,0 +1,211 @@
+package poster
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/imaging"
+	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/template"
+	"image"
+	"os"
+	"sync"
+)
+
+// Candidate is a poster candidate written in the dir of the post.
+type Candidate struct {
+	// Index counts from 1.
+	Index       int
+	Path        string
+	SummaryPath string
+	Summary     string
+}
+
+// GeneratePosterCandidates generates n posters concurrently. With varySummary every candidate gets its own summary,
+// otherwise all the candidates are drawn from one summary. Candidates have no thumbnails, they are made by
+// ChooseCandidate.
+func GeneratePosterCandidates(ctx context.Context, args GeneratePosterArgs, n int,
+	varySummary bool) ([]*GeneratePosterResult, error) {
+
+	if n < 1 {
+		return nil, fmt.Errorf("invalid candidates %d, need at least 1", n)
+	}
+
+	fileContent, err := prepare(args)
+	if err != nil {
+		return nil, err
+	}
+
+	summaryCount := 1
+	if varySummary {
+		summaryCount = n
+	}
+
+	summaries := make([]string, summaryCount)
+	err = parallel(summaryCount, func(i int) error {
+		var err error
+		sctx := ctx
+		if varySummary {
+			sctx = llms.WithCacheVariant(ctx, candidateVariant(i))
+		}
+		summaries[i], err = summarize(sctx, args, fileContent)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	hctx.Println(ctx, "generate %d summaries done", summaryCount)
+
+	c := args.TP.Poster
+	c.Thumbnails = nil
+
+	res := make([]*GeneratePosterResult, n)
+	err = parallel(n, func(i int) error {
+		var err error
+		res[i], err = render(llms.WithCacheVariant(ctx, candidateVariant(i)), args, fileContent,
+			summaries[i%summaryCount], c)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// candidateVariant keeps the identical calls of the candidates from sharing a cached response.
+func candidateVariant(i int) string {
+	return fmt.Sprintf("candidate-%d", i+1)
+}
+
+// parallel runs fn for 0 to n-1 concurrently and joins the errors.
+func parallel(n int, fn func(i int) error) error {
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// WriteCandidates writes the candidates and their summaries as 'feature.candidate-<i>' in the dir of the post.
+func WriteCandidates(tp *template.Template, fileName string, results []*GeneratePosterResult) error {
+	if existing, err := ListCandidates(tp, fileName); err != nil {
+		return err
+	} else if len(existing) != 0 {
+		return fmt.Errorf("poster candidates of %s already exist, choose one with '--choose' or '--pick N' first",
+			fileName)
+	}
+
+	for i, r := range results {
+		if err := tp.WriteResource(fileName, tp.CandidateFileName(i+1), r.Pic); err != nil {
+			return err
+		}
+		if err := tp.WriteResource(fileName, tp.CandidateSummaryFileName(i+1), []byte(r.Summary)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListCandidates returns the candidates in the dir of the post, in order.
+func ListCandidates(tp *template.Template, fileName string) ([]Candidate, error) {
+	var res []Candidate
+	for i := 1; ; i++ {
+		p := tp.ResourcePath(fileName, tp.CandidateFileName(i))
+		if !template.FileExists(p) {
+			return res, nil
+		}
+
+		c := Candidate{
+			Index:       i,
+			Path:        p,
+			SummaryPath: tp.ResourcePath(fileName, tp.CandidateSummaryFileName(i)),
+		}
+		if data, err := os.ReadFile(c.SummaryPath); err == nil {
+			c.Summary = string(data)
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		res = append(res, c)
+	}
+}
+
+// ChooseCandidate promotes the candidate index to the poster of the post with its summary and thumbnails, and
+// deletes all the candidates.
+func ChooseCandidate(tp *template.Template, fileName string, index int) (*Candidate, error) {
+	candidates, err := ListCandidates(tp, fileName)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no poster candidates of %s, generate them with '--candidates'", fileName)
+	}
+	if index < 1 || index > len(candidates) {
+		return nil, fmt.Errorf("invalid candidate %d, choose 1-%d", index, len(candidates))
+	}
+
+	chosen := candidates[index-1]
+	picData, err := os.ReadFile(chosen.Path)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(picData))
+	if err != nil {
+		return nil, fmt.Errorf("decode candidate %s: %w", chosen.Path, err)
+	}
+	thumbnails, err := imaging.MakeThumbnails(img, tp.Poster)
+	if err != nil {
+		return nil, err
+	}
+
+	// the files of an earlier poster are not overwritten, they are checked before any write so a failed choice
+	// leaves the candidates as they are.
+	targets := []string{tp.ResourcePath(fileName, tp.PosterFileName())}
+	if chosen.Summary != "" {
+		targets = append(targets, tp.SummaryPath(fileName))
+	}
+	for _, thumb := range thumbnails {
+		targets = append(targets, tp.ResourcePath(fileName, tp.ThumbnailFileName(thumb.Width)))
+	}
+	for _, p := range targets {
+		if template.FileExists(p) {
+			return nil, fmt.Errorf("file %s already exists", p)
+		}
+	}
+
+	if err = tp.WritePoster(fileName, picData); err != nil {
+		return nil, err
+	}
+	if chosen.Summary != "" {
+		if err = tp.WritePicSummary(fileName, chosen.Summary); err != nil {
+			return nil, err
+		}
+	}
+	for _, thumb := range thumbnails {
+		if err = tp.WriteResource(fileName, tp.ThumbnailFileName(thumb.Width), thumb.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range candidates {
+		if err = os.Remove(c.Path); err != nil {
+			return nil, err
+		}
+		if err = os.Remove(c.SummaryPath); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	return &chosen, nil
+}
@@ -0,0 +1,112 @@
+package poster
+
+import (
+	"context"
+	"fmt"
+	"github.io/uberate/hcli/pkg/imaging"
+	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/template"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+// countingLLM numbers the summaries it returns.
+type countingLLM struct {
+	fakeLLM
+	texts atomic.Int32
+}
+
+func (l *countingLLM) Text(ctx context.Context, sysPrompt, input string) (string, llms.Usage, error) {
+	return fmt.Sprintf("summary %d", l.texts.Add(1)), llms.Usage{}, nil
+}
+
+func TestGeneratePosterCandidates(t *testing.T) {
+	tp := &template.Template{
+		Dir:     "test_candidate_output",
+		NeedDir: true,
+		Poster:  imaging.Config{Width: 32, Height: 16, Thumbnails: []int{8}},
+	}
+	defer os.RemoveAll("test_candidate_output")
+
+	if err := tp.WriteIfNotExists("post", []byte("+++\ntitle = 'a'\n+++\nbody")); err != nil {
+		t.Fatalf("write post failed: %v", err)
+	}
+
+	llm := &countingLLM{}
+	args := GeneratePosterArgs{TP: tp, LLMTools: llm, FileName: "post"}
+
+	res, err := GeneratePosterCandidates(context.Background(), args, 3, false)
+	if err != nil {
+		t.Fatalf("GeneratePosterCandidates failed: %v", err)
+	}
+	if len(res) != 3 || llm.texts.Load() != 1 || res[2].Summary != "summary 1" || len(res[0].Thumbnails) != 0 {
+		t.Fatalf("Expected 3 candidates of one summary without thumbnails, got %d candidates of %d summaries",
+			len(res), llm.texts.Load())
+	}
+
+	res, err = GeneratePosterCandidates(context.Background(), args, 3, true)
+	if err != nil {
+		t.Fatalf("GeneratePosterCandidates failed: %v", err)
+	}
+	if llm.texts.Load() != 4 || res[0].Summary == res[1].Summary {
+		t.Fatalf("Expected a summary per candidate, got %d summaries", llm.texts.Load()-1)
+	}
+
+	if err = WriteCandidates(tp, "post", res); err != nil {
+		t.Fatalf("WriteCandidates failed: %v", err)
+	}
+	if err = WriteCandidates(tp, "post", res); err == nil {
+		t.Fatal("Expected existing candidates to fail")
+	}
+
+	candidates, err := ListCandidates(tp, "post")
+	if err != nil || len(candidates) != 3 || candidates[1].Summary != res[1].Summary {
+		t.Fatalf("Unexpected candidates: %+v, err: %v", candidates, err)
+	}
+
+	if _, err = ChooseCandidate(tp, "post", 4); err == nil {
+		t.Fatal("Expected an out of range candidate to fail")
+	}
+
+	// the summary of an earlier poster fails the choice before the poster is written.
+	if err = tp.WritePicSummary("post", "earlier"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ChooseCandidate(tp, "post", 2); err == nil {
+		t.Fatal("Expected an existing summary to fail")
+	}
+	if template.FileExists(tp.ResourcePath("post", tp.PosterFileName())) {
+		t.Fatal("Expected no poster written")
+	}
+	if candidates, _ = ListCandidates(tp, "post"); len(candidates) != 3 {
+		t.Fatalf("Expected the candidates kept, got %d", len(candidates))
+	}
+	if err = os.Remove(tp.SummaryPath("post")); err != nil {
+		t.Fatal(err)
+	}
+
+	chosen, err := ChooseCandidate(tp, "post", 2)
+	if err != nil {
+		t.Fatalf("ChooseCandidate failed: %v", err)
+	}
+	if chosen.Summary != res[1].Summary {
+		t.Fatalf("Unexpected chosen candidate: %+v", chosen)
+	}
+
+	for _, name := range []string{tp.PosterFileName(), "post.summary.text", tp.ThumbnailFileName(8)} {
+		if !template.FileExists(tp.ResourcePath("post", name)) {
+			t.Fatalf("Expected %s to be written", name)
+		}
+	}
+	summary, _ := os.ReadFile(tp.ResourcePath("post", "post.summary.text"))
+	if string(summary) != res[1].Summary {
+		t.Fatalf("Unexpected summary: %s", summary)
+	}
+	if candidates, _ = ListCandidates(tp, "post"); len(candidates) != 0 {
+		t.Fatalf("Expected the candidates to be deleted, got %d", len(candidates))
+	}
+	if template.FileExists(tp.ResourcePath("post", tp.CandidateSummaryFileName(1))) {
+		t.Fatal("Expected the candidate summaries to be deleted")
+	}
+}
@@ -0,0 +1,77 @@
+package poster
+
+import (
+	"context"
+	"errors"
+	"github.io/uberate/hcli/pkg/cover"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/imaging"
+	"github.io/uberate/hcli/pkg/overlay"
+)
+
+// RenderLocalPoster renders the poster of the post without any model: a procedural cover seeded from the title, with
+// the title and the tags typeset on it. The result has no summary.
+func RenderLocalPoster(ctx context.Context, args GeneratePosterArgs) (*GeneratePosterResult, error) {
+	if args.TP == nil {
+		return nil, errors.New("template is required for generate poster")
+	}
+
+	if err := args.TP.Poster.Validate(); err != nil {
+		return nil, err
+	}
+	if err := args.TP.Cover.Validate(); err != nil {
+		return nil, err
+	}
+
+	c := LocalOverlayConfig(args.TP.Overlay)
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	fileContent, err := args.TP.ReadIfExists(args.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	text := OverlayText(c, args.FileName, fileContent)
+
+	width, height := args.TP.Poster.Width, args.TP.Poster.Height
+	if width == 0 && height == 0 {
+		width, height = cover.DefaultWidth, cover.DefaultHeight
+	} else if width == 0 {
+		width = height * cover.DefaultWidth / cover.DefaultHeight
+	} else if height == 0 {
+		height = width * cover.DefaultHeight / cover.DefaultWidth
+	}
+
+	background, err := cover.Render(args.TP.Cover, text.Title, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := imaging.ProcessImage(background, args.TP.Poster, overlay.Stage(c, text))
+	if err != nil {
+		return nil, err
+	}
+	hctx.Debug(ctx, "local poster rendered to %dx%d%s", out.Width, out.Height, out.Ext)
+
+	return &GeneratePosterResult{
+		Pic:        out.Data,
+		Thumbnails: out.Thumbnails,
+	}, nil
+}
+
+// LocalOverlayConfig returns the overlay config of the template for local posters: the text is always drawn with the
+// tags, centered and without a band unless the template sets them.
+func LocalOverlayConfig(c overlay.Config) overlay.Config {
+	c.Enabled = true
+	c.ShowTags = true
+	if c.Position == "" {
+		c.Position = overlay.PositionCenter
+	}
+	if c.BandColor == "" {
+		c.BandColor = overlay.ColorNone
+	}
+
+	return c
+}
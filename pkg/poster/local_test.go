@@ -0,0 +1,64 @@
+package poster
+
+import (
+	"bytes"
+	"context"
+	"github.io/uberate/hcli/pkg/cover"
+	"github.io/uberate/hcli/pkg/imaging"
+	"github.io/uberate/hcli/pkg/overlay"
+	"github.io/uberate/hcli/pkg/template"
+	"image"
+	"os"
+	"testing"
+)
+
+func TestRenderLocalPoster(t *testing.T) {
+	tp := &template.Template{
+		Dir:     "test_local_output",
+		NeedDir: true,
+		Poster:  imaging.Config{Width: 120, Format: imaging.FormatPNG, Thumbnails: []int{60}},
+	}
+	defer os.RemoveAll("test_local_output")
+
+	if err := tp.WriteIfNotExists("post", []byte("+++\ntitle = 'Hello'\ntags = ['go']\n+++\nbody")); err != nil {
+		t.Fatalf("write post failed: %v", err)
+	}
+
+	res, err := RenderLocalPoster(context.Background(), GeneratePosterArgs{TP: tp, FileName: "post"})
+	if err != nil {
+		t.Fatalf("RenderLocalPoster failed: %v", err)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(res.Pic))
+	if err != nil {
+		t.Fatalf("decode poster failed: %v", err)
+	}
+	if format != "png" || img.Bounds().Dx() != 120 || img.Bounds().Dy() != 63 {
+		t.Fatalf("Unexpected poster: %s %v", format, img.Bounds())
+	}
+	if res.Summary != "" || len(res.Thumbnails) != 1 {
+		t.Fatalf("Unexpected result: %+v", res)
+	}
+
+	again, err := RenderLocalPoster(context.Background(), GeneratePosterArgs{TP: tp, FileName: "post"})
+	if err != nil || !bytes.Equal(res.Pic, again.Pic) {
+		t.Fatalf("Expected the same post to render the same poster, err: %v", err)
+	}
+
+	tp.Cover = cover.Config{Pattern: "noise"}
+	if _, err = RenderLocalPoster(context.Background(), GeneratePosterArgs{TP: tp, FileName: "post"}); err == nil {
+		t.Fatal("Expected an invalid cover config to fail")
+	}
+}
+
+func TestLocalOverlayConfig(t *testing.T) {
+	c := LocalOverlayConfig(overlay.Config{})
+	if !c.Enabled || !c.ShowTags || c.Position != overlay.PositionCenter || c.BandColor != overlay.ColorNone {
+		t.Fatalf("Unexpected local overlay config: %+v", c)
+	}
+
+	c = LocalOverlayConfig(overlay.Config{Position: overlay.PositionBottom, BandColor: "#000000"})
+	if c.Position != overlay.PositionBottom || c.BandColor != "#000000" {
+		t.Fatalf("Expected the template overlay to be kept: %+v", c)
+	}
+}
@@ -3,23 +3,50 @@ package poster
 import (
 	"context"
 	"errors"
+	"github.io/uberate/hcli/pkg/frontmatter"
 	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/imaging"
 	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/overlay"
+	"github.io/uberate/hcli/pkg/prompts"
 	"github.io/uberate/hcli/pkg/template"
+	"path"
+	"strings"
+	"time"
 )
 
 type GeneratePosterArgs struct {
 	TP       *template.Template
 	LLMTools llms.LLMTools
 	FileName string
+	// Prompts resolves the prompt names, nil means the builtin prompts.
+	Prompts *prompts.Registry
 }
 
 type GeneratePosterResult struct {
 	Summary string
-	Pic     []byte
+	// Pic is the poster processed by the template poster config.
+	Pic        []byte
+	Thumbnails []imaging.Thumbnail
 }
 
 func GeneratePoster(ctx context.Context, args GeneratePosterArgs) (*GeneratePosterResult, error) {
+	fileContent, err := prepare(args)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := summarize(ctx, args, fileContent)
+	if err != nil {
+		return nil, err
+	}
+	hctx.Println(ctx, "generate the summary done")
+
+	return render(ctx, args, fileContent, res, args.TP.Poster)
+}
+
+// prepare checks the args and reads the post.
+func prepare(args GeneratePosterArgs) ([]byte, error) {
 	if args.TP == nil {
 		return nil, errors.New("template is required for generate poster")
 	}
@@ -28,42 +55,89 @@ func GeneratePoster(ctx context.Context, args GeneratePosterArgs) (*GeneratePost
 		return nil, errors.New("LLMTools is required for generate poster")
 	}
 
-	fileContent, err := args.TP.ReadIfExists(args.FileName)
-	if err != nil {
+	// fail before paying for the generation if the poster config is wrong
+	if err := args.TP.Poster.Validate(); err != nil {
+		return nil, err
+	}
+	if err := args.TP.Overlay.Validate(); err != nil {
 		return nil, err
 	}
 
+	return args.TP.ReadIfExists(args.FileName)
+}
+
+func summarize(ctx context.Context, args GeneratePosterArgs, fileContent []byte) (string, error) {
 	summaryPrompt := args.TP.PicSummaryPrompt
 	if summaryPrompt == "" {
-		summaryPrompt = defaultPicSummaryPrompt
+		name := args.TP.PicSummaryPromptName
+		if name == "" {
+			name = prompts.PosterSummary
+		}
+
+		var err error
+		if summaryPrompt, err = args.Prompts.Render(name, prompts.PostVars(fileContent, "")); err != nil {
+			return "", err
+		}
 	}
 
-	res, err := args.LLMTools.Text(ctx, summaryPrompt, string(fileContent))
+	res, _, err := args.LLMTools.Text(llms.WithTask(ctx, llms.TaskSummary), summaryPrompt, string(fileContent))
+	return res, err
+}
+
+// render generates the picture of summary and processes it with c.
+func render(ctx context.Context, args GeneratePosterArgs, fileContent []byte, summary string,
+	c imaging.Config) (*GeneratePosterResult, error) {
+
+	picData, _, err := args.LLMTools.Pic(ctx, summary)
 	if err != nil {
 		return nil, err
 	}
-	hctx.Println(ctx, "generate the summary done")
 
-	picData, err := args.LLMTools.Pic(ctx, res)
+	hctx.Println(ctx, "generate the pic done")
+
+	var stages []imaging.Stage
+	if args.TP.Overlay.Enabled {
+		stages = append(stages, overlay.Stage(args.TP.Overlay, OverlayText(args.TP.Overlay, args.FileName, fileContent)))
+	}
+
+	out, err := imaging.Process(picData, c, stages...)
 	if err != nil {
 		return nil, err
 	}
-
-	hctx.Println(ctx, "generate the pic done")
+	hctx.Debug(ctx, "poster processed to %dx%d%s", out.Width, out.Height, out.Ext)
 
 	return &GeneratePosterResult{
-		Summary: res,
-		Pic:     picData,
+		Summary:    summary,
+		Pic:        out.Data,
+		Thumbnails: out.Thumbnails,
 	}, nil
-
 }
 
-var defaultPicSummaryPrompt = "" +
-	"你是一个文本内容的描述大师，根据用户的需求可以描述需要的图片。要求：" +
-	"1. 图片中不可以有任何文字内容。" +
-	"2. 图片中的主要元素不可过多。" +
-	"3. 响应信息的长度应该不超过 300 字。" +
-	"另外，请着重描述以下内容：图片的风格，核心元素。" +
-	"如果用户未指定风格、内容，你需要自行裁断选择的风格。\n" +
-	"输入的信息中如果存在注释，请忽略：" +
-	"1. markdown 文本类型中的 '+++' 区块。"
+// OverlayText returns the title and the date of the post for the poster overlay. The title falls back to the file
+// name and the date to today.
+func OverlayText(c overlay.Config, fileName string, fileContent []byte) overlay.Text {
+	text := overlay.Text{Title: path.Base(strings.TrimSuffix(fileName, ".md"))}
+	date := time.Now()
+
+	if doc, err := frontmatter.Parse(fileContent); err == nil {
+		if title := doc.GetString("title"); title != "" {
+			text.Title = title
+		}
+		if d, ok := doc.GetTime("date"); ok {
+			date = d
+		}
+		if c.ShowTags {
+			text.Tags = doc.GetStrings("tags")
+		}
+	}
+
+	if c.ShowDate {
+		format := c.DateFormat
+		if format == "" {
+			format = time.DateOnly
+		}
+		text.Date = date.Format(format)
+	}
+
+	return text
+}
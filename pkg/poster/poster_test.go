@@ -0,0 +1,78 @@
+package poster
+
+import (
+	"bytes"
+	"context"
+	"github.io/uberate/hcli/pkg/imaging"
+	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/overlay"
+	"github.io/uberate/hcli/pkg/template"
+	"image"
+	"image/png"
+	"os"
+	"testing"
+)
+
+// fakeLLM returns a fixed summary and a blank square picture.
+type fakeLLM struct{}
+
+func (fakeLLM) Text(ctx context.Context, sysPrompt, input string) (string, llms.Usage, error) {
+	return "summary", llms.Usage{}, nil
+}
+
+func (fakeLLM) Pic(ctx context.Context, input string) ([]byte, llms.Usage, error) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 64, 64)))
+	return buf.Bytes(), llms.Usage{}, err
+}
+
+func TestGeneratePoster(t *testing.T) {
+	tp := &template.Template{
+		Dir:     "test_output",
+		NeedDir: true,
+		Poster:  imaging.Config{Width: 32, Height: 16, Format: imaging.FormatJPEG, Thumbnails: []int{8}},
+		Overlay: overlay.Config{Enabled: true, ShowDate: true},
+	}
+	defer os.RemoveAll("test_output")
+
+	if err := tp.WriteIfNotExists("post", []byte("+++\ntitle = 'a'\n+++\nbody")); err != nil {
+		t.Fatalf("write post failed: %v", err)
+	}
+
+	res, err := GeneratePoster(context.Background(), GeneratePosterArgs{TP: tp, LLMTools: fakeLLM{}, FileName: "post"})
+	if err != nil {
+		t.Fatalf("GeneratePoster failed: %v", err)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(res.Pic))
+	if err != nil {
+		t.Fatalf("decode poster failed: %v", err)
+	}
+	if format != "jpeg" || img.Bounds().Dx() != 32 || img.Bounds().Dy() != 16 {
+		t.Fatalf("Unexpected poster: %s %v", format, img.Bounds())
+	}
+	if res.Summary != "summary" || len(res.Thumbnails) != 1 || res.Thumbnails[0].Height != 4 {
+		t.Fatalf("Unexpected result: %+v", res)
+	}
+
+	tp.Poster.Format = "gif"
+	if _, err = GeneratePoster(context.Background(), GeneratePosterArgs{TP: tp, LLMTools: fakeLLM{},
+		FileName: "post"}); err == nil {
+		t.Fatal("Expected an invalid poster config to fail")
+	}
+}
+
+func TestOverlayText(t *testing.T) {
+	c := overlay.Config{ShowDate: true, ShowTags: true}
+
+	text := OverlayText(c, "dir/my-post.md",
+		[]byte("+++\ntitle = '标题'\ndate = '2025-01-02T03:04:05Z'\ntags = ['go']\n+++\n"))
+	if text.Title != "标题" || text.Date != "2025-01-02" || len(text.Tags) != 1 {
+		t.Fatalf("Unexpected text: %+v", text)
+	}
+
+	text = OverlayText(overlay.Config{}, "dir/my-post.md", []byte("no front matter"))
+	if text.Title != "my-post" || text.Date != "" {
+		t.Fatalf("Unexpected text: %+v", text)
+	}
+}
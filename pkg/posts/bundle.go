@@ -0,0 +1,98 @@
+package posts
+
+import (
+	"fmt"
+	"github.io/uberate/hcli/pkg/template"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// flatAndBundle returns the template of args for the single file posts and for the bundles.
+func flatAndBundle(args BundleArgs) (template.Template, template.Template) {
+	flat, bundle := args.Template, args.Template
+	flat.NeedDir, bundle.NeedDir = false, true
+	return flat, bundle
+}
+
+// PlanBundle plans the conversion of the single file post Name to a page bundle: 'name.md' and its language files
+// move to 'name/index.md' and 'name/index.<lang>.md', with the summary text. The URL of the post doesn't change.
+func PlanBundle(args BundleArgs) (*Plan, error) {
+	flat, bundle := flatAndBundle(args)
+	name := strings.TrimSuffix(args.Name, ".md")
+	file := flat.GetFilePath(name)
+	if !template.FileExists(file) {
+		return nil, fmt.Errorf("post %s not found", file)
+	}
+	dir := filepath.Join(args.Template.Dir, name)
+	if template.FileExists(dir) {
+		return nil, fmt.Errorf("%s already exists", dir)
+	}
+
+	files, err := filepath.Glob(filepath.Join(args.Template.Dir, name+".*.md"))
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+	for _, f := range append([]string{file}, files...) {
+		suffix := strings.TrimPrefix(filepath.Base(f), filepath.Base(name))
+		plan.Renames = append(plan.Renames, Rename{From: f, To: filepath.Join(dir, "index"+suffix)})
+	}
+	plan.mapping = append(mapping{}, plan.Renames...)
+
+	if summary := flat.SummaryPath(name); template.FileExists(summary) {
+		plan.Renames = append(plan.Renames, Rename{From: summary, To: bundle.SummaryPath(name)})
+	}
+
+	if plan.Rewrites, err = rewrites(args.Posts, plan.mapping, args.ContentDir); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// PlanUnbundle plans the conversion of the page bundle Name to a single file post, the reverse of PlanBundle. The
+// bundle can't have resources but the summary text, they would have no page to belong to.
+func PlanUnbundle(args BundleArgs) (*Plan, error) {
+	flat, bundle := flatAndBundle(args)
+	name := strings.TrimSuffix(args.Name, ".md")
+	dir := filepath.Join(args.Template.Dir, name)
+	if !template.FileExists(bundle.GetFilePath(name)) {
+		return nil, fmt.Errorf("bundle %s not found", bundle.GetFilePath(name))
+	}
+	if flat.PostExists(name) {
+		return nil, fmt.Errorf("post %s already exists", flat.GetFilePath(name))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Removes: []string{dir}}
+	var resources []string
+	for _, e := range entries {
+		p := filepath.Join(dir, e.Name())
+		switch {
+		case !e.IsDir() && strings.HasPrefix(e.Name(), "index.") && strings.HasSuffix(e.Name(), ".md"):
+			to := flat.GetFilePath(name)
+			if lang := strings.TrimSuffix(strings.TrimPrefix(e.Name(), "index."), "md"); lang != "" {
+				to = flat.GetLangFilePath(name, strings.TrimSuffix(lang, "."))
+			}
+			plan.Renames = append(plan.Renames, Rename{From: p, To: to})
+		case p == bundle.SummaryPath(name):
+			plan.Renames = append(plan.Renames, Rename{From: p, To: flat.SummaryPath(name)})
+		default:
+			resources = append(resources, e.Name())
+		}
+	}
+	if len(resources) != 0 {
+		return nil, fmt.Errorf("bundle %s has resources: %s, move them first", dir, strings.Join(resources, ", "))
+	}
+	plan.mapping = append(mapping{}, plan.Renames...)
+
+	if plan.Rewrites, err = rewrites(args.Posts, plan.mapping, args.ContentDir); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
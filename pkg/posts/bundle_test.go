@@ -0,0 +1,80 @@
+package posts
+
+import (
+	"github.io/uberate/hcli/pkg/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundleAndUnbundle(t *testing.T) {
+	root := writeSite(t, map[string]string{
+		"posts/a.md":             "+++\n+++\n![x](images/x.png) [b](b.md) [self](#top)\n",
+		"posts/a.en.md":          "+++\n+++\n![x](./images/x.png)\n",
+		"posts/a.summary.text":   "summary",
+		"posts/b.md":             "[a](a.md) [en](a.en.md) [page](a/)\n",
+		"posts/images/x.png":     "",
+		"posts/c/index.md":       "",
+		"posts/c/feature.png":    "",
+		"posts/c/c.summary.text": "",
+	})
+	tp := template.Template{Dir: filepath.Join(root, "posts")}
+	args := BundleArgs{Template: tp, Name: "a", Posts: postFiles(t, tp.Dir)}
+
+	plan, err := PlanBundle(args)
+	if err != nil {
+		t.Fatalf("PlanBundle failed: %v", err)
+	}
+	if len(plan.Renames) != 3 || len(plan.Rewrites) != 5 {
+		t.Fatalf("Unexpected plan: %+v", plan)
+	}
+	if err = plan.Apply(); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if got := readFile(t, filepath.Join(tp.Dir, "a", "index.md")); !strings.HasSuffix(got,
+		"![x](../images/x.png) [b](../b.md) [self](#top)\n") {
+		t.Fatalf("Unexpected bundle: %s", got)
+	}
+	if got := readFile(t, filepath.Join(tp.Dir, "a", "index.en.md")); !strings.HasSuffix(got, "![x](../images/x.png)\n") {
+		t.Fatalf("Unexpected language file: %s", got)
+	}
+	if got := readFile(t, filepath.Join(tp.Dir, "b.md")); got != "[a](a/index.md) [en](a/index.en.md) [page](a/)\n" {
+		t.Fatalf("Unexpected rewrites: %s", got)
+	}
+	bundle := tp
+	bundle.NeedDir = true
+	if readFile(t, bundle.SummaryPath("a")) != "summary" {
+		t.Fatal("Expected the summary moved into the bundle")
+	}
+	if _, err = PlanBundle(args); err == nil {
+		t.Fatal("Expected a bundled post to fail")
+	}
+
+	args.Posts = postFiles(t, tp.Dir)
+	if plan, err = PlanUnbundle(args); err != nil {
+		t.Fatalf("PlanUnbundle failed: %v", err)
+	}
+	if err = plan.Apply(); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if got := readFile(t, filepath.Join(tp.Dir, "a.md")); got != "+++\n+++\n![x](images/x.png) [b](b.md) [self](#top)\n" {
+		t.Fatalf("Expected the post restored: %s", got)
+	}
+	if got := readFile(t, filepath.Join(tp.Dir, "b.md")); got != "[a](a.md) [en](a.en.md) [page](a/)\n" {
+		t.Fatalf("Expected the links restored: %s", got)
+	}
+	if readFile(t, tp.SummaryPath("a")) != "summary" {
+		t.Fatal("Expected the summary moved out of the bundle")
+	}
+	if _, err = os.Stat(filepath.Join(tp.Dir, "a")); !os.IsNotExist(err) {
+		t.Fatalf("Expected the bundle dir removed: %v", err)
+	}
+
+	args.Name = "c"
+	if _, err = PlanUnbundle(args); err == nil || !strings.Contains(err.Error(), "feature.png") {
+		t.Fatalf("Expected the resources to stop the unbundle: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package posts
+
+import (
+	"bytes"
+	"fmt"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GitLastmod returns the time of the last commit which changed the content of the post at path. The commits only
+// editing the front matter are skipped, so writing the lastmod back and committing it doesn't move it again. The
+// file is followed across renames. The second result is false if the file has no commit.
+func GitLastmod(path string) (time.Time, bool, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	dir := filepath.Dir(abs)
+
+	out, err := git(dir, "log", "--follow", "--format=commit %H %cI", "--name-only", "--", filepath.Base(abs))
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	commits, err := parseLog(out)
+	if err != nil || len(commits) == 0 {
+		return time.Time{}, false, err
+	}
+
+	body := func(c commit) string {
+		// the file is missing in a commit which deleted it, it is compared as empty.
+		data, err := git(dir, "show", c.hash+":"+c.path)
+		if err != nil {
+			return ""
+		}
+		return bodyOf(data)
+	}
+
+	current := body(commits[0])
+	for i := 0; i+1 < len(commits); i++ {
+		previous := body(commits[i+1])
+		if previous != current {
+			return commits[i].time, true, nil
+		}
+		current = previous
+	}
+
+	return commits[len(commits)-1].time, true, nil
+}
+
+// Touch sets the lastmod of the post of doc to t, it reports whether the lastmod changed.
+func Touch(doc *frontmatter.Document, t time.Time) (bool, error) {
+	key := keyOf(doc, KeyLastmod)
+	if old, ok := doc.GetTime(key); ok && old.Equal(t) {
+		return false, nil
+	}
+
+	return true, doc.Set(key, t)
+}
+
+// commit is an entry of the history of a file, path is relative to the repository root.
+type commit struct {
+	hash string
+	time time.Time
+	path string
+}
+
+// parseLog parses the output of 'git log --format=commit %H %cI --name-only', the newest commit first.
+func parseLog(out []byte) ([]commit, error) {
+	var res []commit
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "commit "):
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("unexpected git log line: %s", line)
+			}
+			t, err := time.Parse(time.RFC3339, fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("unexpected git log time: %w", err)
+			}
+			res = append(res, commit{hash: fields[1], time: t})
+		case len(res) != 0 && res[len(res)-1].path == "":
+			res[len(res)-1].path = line
+		}
+	}
+
+	return res, nil
+}
+
+// bodyOf returns the content of the post without its front matter, the whole data if the front matter is invalid.
+func bodyOf(data []byte) string {
+	doc, err := frontmatter.Parse(data)
+	if err != nil {
+		return string(data)
+	}
+	return doc.Body
+}
+
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,107 @@
+package posts
+
+import (
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// gitRepo inits a git repository in a temp dir, commit writes the files and commits them at the date.
+func gitRepo(t *testing.T) (string, func(date string, files map[string]string, moves ...string)) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	run := func(env []string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run(nil, "init", "-q")
+
+	return root, func(date string, files map[string]string, moves ...string) {
+		t.Helper()
+		for name, content := range files {
+			p := filepath.Join(root, name)
+			if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for i := 0; i+1 < len(moves); i += 2 {
+			if err := os.MkdirAll(filepath.Dir(filepath.Join(root, moves[i+1])), 0755); err != nil {
+				t.Fatal(err)
+			}
+			run(nil, "mv", moves[i], moves[i+1])
+		}
+		run(nil, "add", "-A")
+		run([]string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date}, "commit", "-q", "-m", date)
+	}
+}
+
+func TestGitLastmod(t *testing.T) {
+	root, commit := gitRepo(t)
+	commit("2026-01-01T09:00:00+08:00", map[string]string{"posts/a.md": "+++\ntitle = 'a'\n+++\nv1\n"})
+	commit("2026-02-01T09:00:00+08:00", map[string]string{"posts/a.md": "+++\ntitle = 'a'\ntags = ['go']\n+++\nv1\n"})
+	commit("2026-03-01T09:00:00+08:00", map[string]string{"posts/a.md": "+++\ntitle = 'a'\ntags = ['go']\n+++\nv2\n"})
+	commit("2026-04-01T09:00:00+08:00", map[string]string{
+		"posts/a.md": "+++\ntitle = 'a'\ntags = ['go']\nlastmod = '2026-03-01T09:00:00+08:00'\n+++\nv2\n",
+		"posts/b.md": "+++\ntitle = 'b'\n+++\nb\n",
+	})
+	commit("2026-05-01T09:00:00+08:00", nil, "posts/a.md", "posts/a/index.md")
+
+	for _, c := range []struct {
+		path string
+		want string
+	}{
+		{"posts/a/index.md", "2026-03-01T09:00:00+08:00"},
+		{"posts/b.md", "2026-04-01T09:00:00+08:00"},
+	} {
+		got, ok, err := GitLastmod(filepath.Join(root, c.path))
+		if err != nil || !ok {
+			t.Fatalf("GitLastmod(%s) failed: %v, %v", c.path, ok, err)
+		}
+		if got.Format(time.RFC3339) != c.want {
+			t.Fatalf("GitLastmod(%s) = %v, want %s", c.path, got, c.want)
+		}
+	}
+
+	untracked := filepath.Join(root, "posts", "c.md")
+	if err := os.WriteFile(untracked, []byte("c"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := GitLastmod(untracked); ok || err != nil {
+		t.Fatalf("Expected no commit for an untracked file: %v, %v", ok, err)
+	}
+
+	if _, _, err := GitLastmod(filepath.Join(t.TempDir(), "d.md")); err == nil {
+		t.Fatal("Expected an error out of a git repository")
+	}
+}
+
+func TestTouch(t *testing.T) {
+	doc, err := frontmatter.Parse([]byte("+++\ntitle = 'a'\nLastmod = 2026-03-01T09:00:00+08:00\n+++\nbody"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2026, 3, 1, 1, 0, 0, 0, time.UTC)
+	if changed, err := Touch(doc, at); changed || err != nil {
+		t.Fatalf("Expected the same time unchanged: %v, %v", changed, err)
+	}
+
+	changed, err := Touch(doc, at.Add(time.Hour))
+	if !changed || err != nil || doc.GetString("Lastmod") != "2026-03-01T02:00:00Z" {
+		t.Fatalf("Expected the lastmod replaced: %v, %v\n%s", changed, err, doc.Bytes())
+	}
+}
@@ -0,0 +1,139 @@
+package posts
+
+import (
+	"fmt"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// keyOf returns the spelling of key in the front matter of doc, Hugo reads the keys case-insensitively. A missing
+// key is returned as is.
+func keyOf(doc *frontmatter.Document, key string) string {
+	values, err := doc.Values()
+	if err != nil {
+		return key
+	}
+	if _, ok := values[key]; ok {
+		return key
+	}
+	for k := range values {
+		if strings.EqualFold(k, key) {
+			return k
+		}
+	}
+	return key
+}
+
+// Publish marks the post of doc published at now: draft is false, and date and lastmod are now. A publishDate is
+// set to now too, an older one would hide the new date.
+func Publish(doc *frontmatter.Document, now time.Time) error {
+	if err := doc.Set(keyOf(doc, KeyDraft), false); err != nil {
+		return err
+	}
+	keys := []string{KeyDate, KeyLastmod}
+	if doc.Has(keyOf(doc, KeyPublishDate)) {
+		keys = append(keys, KeyPublishDate)
+	}
+	for _, key := range keys {
+		if err := doc.Set(keyOf(doc, key), now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unpublish marks the post of doc a draft again, its dates are kept.
+func Unpublish(doc *frontmatter.Document) error {
+	return doc.Set(keyOf(doc, KeyDraft), true)
+}
+
+// Schedule marks the post of doc published at at, which must be after now: draft is false, and date and
+// publishDate are at. Hugo builds it from then on.
+func Schedule(doc *frontmatter.Document, at, now time.Time) error {
+	if !at.After(now) {
+		return fmt.Errorf("%s is not in the future", at.Format(time.RFC3339))
+	}
+
+	if err := doc.Set(keyOf(doc, KeyDraft), false); err != nil {
+		return err
+	}
+	for _, key := range []string{KeyDate, KeyPublishDate} {
+		if err := doc.Set(keyOf(doc, key), at); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StatusOf returns the status of the post of doc at now, and the date it is aged from: the date, the publishDate
+// of the scheduled posts, or the zero time if the post has no date.
+func StatusOf(doc *frontmatter.Document, now time.Time) (string, time.Time) {
+	date, _ := doc.GetTime(keyOf(doc, KeyDate))
+	if draft, _ := doc.GetBool(keyOf(doc, KeyDraft)); draft {
+		return StatusDraft, date
+	}
+
+	if publishDate, ok := doc.GetTime(keyOf(doc, KeyPublishDate)); ok && publishDate.After(now) {
+		return StatusScheduled, publishDate
+	}
+	if date.After(now) {
+		return StatusScheduled, date
+	}
+	return StatusPublished, date
+}
+
+// Drafts returns the drafts and the scheduled posts of paths, the oldest first. A post without a date is aged
+// from the modification time of its file. The files without front matter are skipped.
+func Drafts(paths []string, now time.Time) ([]Draft, error) {
+	var res []Draft
+	for _, p := range paths {
+		doc, err := frontmatter.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		if doc.Format == frontmatter.FormatNone {
+			continue
+		}
+
+		status, date := StatusOf(doc, now)
+		if status == StatusPublished {
+			continue
+		}
+		if date.IsZero() {
+			info, err := os.Stat(p)
+			if err != nil {
+				return nil, err
+			}
+			date = info.ModTime()
+		}
+
+		res = append(res, Draft{
+			Path:   p,
+			Title:  doc.GetString(keyOf(doc, "title")),
+			Status: status,
+			Date:   date,
+			Age:    now.Sub(date),
+		})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Date.Before(res[j].Date)
+	})
+	return res, nil
+}
+
+// FormatAge formats the age of a draft in days, or in hours under a day. A negative age, of a scheduled post, is
+// formatted as the time left prefixed with 'in '.
+func FormatAge(age time.Duration) string {
+	prefix := ""
+	if age < 0 {
+		prefix, age = "in ", -age
+	}
+	if age < 24*time.Hour {
+		return fmt.Sprintf("%s%dh", prefix, int(age.Hours()))
+	}
+	return fmt.Sprintf("%s%dd", prefix, int(age.Hours()/24))
+}
@@ -0,0 +1,91 @@
+package posts
+
+import (
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var lifecycleNow = time.Date(2026, 10, 19, 9, 0, 0, 0, time.FixedZone("CST", 8*3600))
+
+func TestPublish(t *testing.T) {
+	doc, err := frontmatter.Parse([]byte("---\ntitle: post\nDraft: true\ndate: 2026-01-01\npublishDate: 2026-01-01\n---\nbody"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = Publish(doc, lifecycleNow); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	want := "---\ntitle: post\nDraft: false\ndate: 2026-10-19T09:00:00+08:00\n" +
+		"publishDate: 2026-10-19T09:00:00+08:00\nlastmod: \"2026-10-19T09:00:00+08:00\"\n---\nbody"
+	if got := string(doc.Bytes()); got != want {
+		t.Fatalf("Unexpected document:\n%s", got)
+	}
+
+	if err = Unpublish(doc); err != nil || !strings.Contains(string(doc.Bytes()), "Draft: true\n") {
+		t.Fatalf("Expected the post a draft again: %v\n%s", err, doc.Bytes())
+	}
+
+	// the native TOML datetimes stay datetimes.
+	doc, err = frontmatter.Parse([]byte("+++\ndraft = true\ndate = 2024-01-02T03:04:05+08:00\n+++\nbody"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = Publish(doc, lifecycleNow); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	want = "+++\ndraft = false\ndate = 2026-10-19T09:00:00+08:00\nlastmod = 2026-10-19T09:00:00+08:00\n+++\nbody"
+	if got := string(doc.Bytes()); got != want {
+		t.Fatalf("Unexpected document:\n%s", got)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	doc, err := frontmatter.Parse([]byte("+++\ntitle = 'post'\ndraft = true\n+++\nbody"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = Schedule(doc, lifecycleNow.Add(-time.Hour), lifecycleNow); err == nil {
+		t.Fatal("Expected an error for a past date")
+	}
+
+	at := lifecycleNow.Add(48 * time.Hour)
+	if err = Schedule(doc, at, lifecycleNow); err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	if status, date := StatusOf(doc, lifecycleNow); status != StatusScheduled || !date.Equal(at) {
+		t.Fatalf("Unexpected status: %s %v\n%s", status, date, doc.Bytes())
+	}
+	if status, _ := StatusOf(doc, at.Add(time.Minute)); status != StatusPublished {
+		t.Fatalf("Expected the post published after its date, got %s", status)
+	}
+}
+
+func TestDrafts(t *testing.T) {
+	root := writeSite(t, map[string]string{
+		"old.md":       "+++\ntitle = 'old'\ndraft = true\ndate = 2026-10-01T09:00:00+08:00\n+++\n",
+		"new.md":       "+++\ntitle = 'new'\ndraft = true\ndate = 2026-10-19T06:00:00+08:00\n+++\n",
+		"later.md":     "+++\ntitle = 'later'\npublishDate = 2026-11-01T09:00:00+08:00\n+++\n",
+		"published.md": "+++\ntitle = 'published'\ndate = 2026-10-01T09:00:00+08:00\n+++\n",
+		"plain.md":     "no front matter",
+	})
+
+	drafts, err := Drafts(postFiles(t, root), lifecycleNow)
+	if err != nil {
+		t.Fatalf("Drafts failed: %v", err)
+	}
+
+	var got []string
+	for _, d := range drafts {
+		got = append(got, strings.Join([]string{filepath.Base(d.Path), d.Status, FormatAge(d.Age)}, " "))
+	}
+	want := "old.md draft 18d,new.md draft 3h,later.md scheduled in 13d"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("Unexpected drafts: %q", got)
+	}
+}
@@ -0,0 +1,14 @@
+// Package posts reorganizes the posts of a template on disk without breaking the site: moving a post renames its
+// file or bundle dir with the resources named after it, rewrites the ref and relref shortcodes and the relative
+// links of every post to the new paths, and adds the old URL to the Hugo aliases of the post.
+//
+// Every change is planned before anything is touched, so the plan can be printed for a dry run and applied as is.
+//
+// Example:
+//
+//	plan, err := posts.PlanMove(posts.MoveArgs{Template: tp, From: "hello", To: "hello-world", Posts: files})
+//	if err != nil {
+//	    return err
+//	}
+//	return plan.Apply()
+package posts
@@ -0,0 +1,126 @@
+package posts
+
+import (
+	"errors"
+	"fmt"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PlanMove plans the move of the post From of the template to To: its file and language files, or its bundle dir,
+// and its summary text. The old URL is added to the aliases of the pages whose URL changes, each language file has
+// its own URL. The move is refused if a URL can't be resolved, a wrong alias would break the old URL silently.
+func PlanMove(args MoveArgs) (*Plan, error) {
+	tp := args.Template
+	from, to := strings.TrimSuffix(args.From, ".md"), strings.TrimSuffix(args.To, ".md")
+	if from == "" || to == "" || from == to {
+		return nil, errors.New("give two different file names")
+	}
+	if !tp.PostExists(from) {
+		return nil, fmt.Errorf("post %s not found in %s", from, tp.Dir)
+	}
+	if tp.PostExists(to) {
+		return nil, fmt.Errorf("post %s already exists in %s", to, tp.Dir)
+	}
+
+	plan := &Plan{}
+	// the pages of the post, the bundle dir or the file path without extension
+	fromPage, toPage := filepath.Join(tp.Dir, from), filepath.Join(tp.Dir, to)
+	var moved []string
+	if tp.NeedDir {
+		plan.Renames = append(plan.Renames, Rename{From: fromPage, To: toPage})
+		files, err := filepath.Glob(filepath.Join(fromPage, "index*.md"))
+		if err != nil {
+			return nil, err
+		}
+		moved = files
+	} else {
+		files, err := filepath.Glob(fromPage + ".*.md")
+		if err != nil {
+			return nil, err
+		}
+		files = append([]string{tp.GetFilePath(from)}, files...)
+		for _, f := range files {
+			if !template.FileExists(f) {
+				continue
+			}
+			plan.Renames = append(plan.Renames, Rename{From: f, To: toPage + strings.TrimPrefix(f, fromPage)})
+			moved = append(moved, f)
+		}
+	}
+	plan.mapping = append(mapping{}, plan.Renames...)
+	if !tp.NeedDir {
+		// the links to the page of a single file post, '../old/'
+		plan.mapping = append(plan.mapping, Rename{From: fromPage, To: toPage})
+	}
+
+	if summary := tp.SummaryPath(from); template.FileExists(summary) {
+		if tp.NeedDir {
+			// renamed in the moved dir
+			summary, _ = plan.mapping.apply(summary)
+		}
+		plan.Renames = append(plan.Renames, Rename{From: summary, To: tp.SummaryPath(to)})
+	}
+
+	var err error
+	if plan.Rewrites, err = rewrites(args.Posts, plan.mapping, args.ContentDir); err != nil {
+		return nil, err
+	}
+
+	if args.Site == nil {
+		return plan, nil
+	}
+	for _, f := range moved {
+		doc, err := frontmatter.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		newPath, _ := plan.mapping.apply(f)
+		oldURL, err := args.Site.PageURL(f, doc)
+		if err != nil {
+			return nil, err
+		}
+		newURL, err := args.Site.PageURL(newPath, doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if oldURL == newURL || slices.Contains(doc.GetStrings("aliases"), oldURL) {
+			continue
+		}
+		plan.Aliases = append(plan.Aliases, Alias{File: newPath, Alias: oldURL})
+	}
+
+	return plan, nil
+}
+
+// Apply renames the files, removes the dirs, rewrites the links and adds the aliases. A rename never overwrites a
+// file.
+func (p *Plan) Apply() error {
+	for _, r := range p.Renames {
+		if _, err := os.Stat(r.To); err == nil {
+			return fmt.Errorf("%s already exists", r.To)
+		}
+		if err := os.MkdirAll(filepath.Dir(r.To), 0755); err != nil {
+			return err
+		}
+		if err := os.Rename(r.From, r.To); err != nil {
+			return err
+		}
+	}
+
+	for _, dir := range p.Removes {
+		if err := os.Remove(dir); err != nil {
+			return err
+		}
+	}
+
+	if err := p.applyRewrites(); err != nil {
+		return err
+	}
+	return p.applyAliases()
+}
@@ -0,0 +1,181 @@
+package posts
+
+import (
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/hugo"
+	"github.io/uberate/hcli/pkg/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSite writes the files of the map under a temp dir and returns the dir.
+func writeSite(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func postFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := template.Template{Dir: dir}.PostFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestMoveBundle(t *testing.T) {
+	root := writeSite(t, map[string]string{
+		"content/posts/old/index.md":         "+++\ntitle = 'old'\n+++\n![](feature.png) [b](../b.md) [b2]({{< ref \"b\" >}})\n",
+		"content/posts/old/index.en.md":      "+++\ntitle = 'old'\naliases = ['/x/']\n+++\n",
+		"content/posts/old/feature.png":      "png",
+		"content/posts/old/old.summary.text": "summary",
+		"content/posts/b.md": "+++\n+++\n[1](old/) [2](./old/index.md#intro) [3]({{< relref \"old\" >}})\r\n" +
+			"[4]({{< ref \"/posts/old/index.md\" >}}) [5]({{< ref \"posts/old\" >}}) [6](/posts/old/) [7](old%20x)\n",
+	})
+	content := filepath.Join(root, "content")
+	tp := template.Template{Dir: filepath.Join(content, "posts"), NeedDir: true}
+
+	plan, err := PlanMove(MoveArgs{
+		Template:   tp,
+		From:       "old",
+		To:         "2024/new",
+		ContentDir: content,
+		Site:       &hugo.Site{ContentDir: content, DefaultContentLanguage: "en"},
+		Posts:      postFiles(t, tp.Dir),
+	})
+	if err != nil {
+		t.Fatalf("PlanMove failed: %v", err)
+	}
+	if len(plan.Renames) != 2 || len(plan.Rewrites) != 6 || len(plan.Aliases) != 2 {
+		t.Fatalf("Unexpected plan: %+v", plan)
+	}
+
+	if err = plan.Apply(); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	newDir := filepath.Join(tp.Dir, "2024", "new")
+	if readFile(t, filepath.Join(newDir, "feature.png")) != "png" ||
+		readFile(t, tp.SummaryPath("2024/new")) != "summary" {
+		t.Fatal("Expected the bundle and the summary moved")
+	}
+	if _, err = os.Stat(filepath.Join(tp.Dir, "old")); !os.IsNotExist(err) {
+		t.Fatalf("Expected the old bundle gone: %v", err)
+	}
+
+	want := "+++\n+++\n[1](2024/new/) [2](./2024/new/index.md#intro) [3]({{< relref \"2024/new\" >}})\r\n" +
+		"[4]({{< ref \"/posts/2024/new/index.md\" >}}) [5]({{< ref \"posts/2024/new\" >}}) [6](/posts/old/) [7](old%20x)\n"
+	if got := readFile(t, filepath.Join(tp.Dir, "b.md")); got != want {
+		t.Fatalf("Unexpected rewrites:\n%s\nwant:\n%s", got, want)
+	}
+
+	index := readFile(t, filepath.Join(newDir, "index.md"))
+	if !strings.Contains(index, `[b](../../b.md) [b2]({{< ref "b" >}})`) {
+		t.Fatalf("Expected the links of the moved post rewritten:\n%s", index)
+	}
+	doc, err := frontmatter.ReadFile(filepath.Join(newDir, "index.en.md"))
+	if err != nil || strings.Join(doc.GetStrings("aliases"), ",") != "/x/,/posts/old/" {
+		t.Fatalf("Unexpected aliases: %v, %v", doc.GetStrings("aliases"), err)
+	}
+}
+
+func TestMovePermalinks(t *testing.T) {
+	root := writeSite(t, map[string]string{
+		"content/posts/old/index.md":    "+++\ntitle = 'Hello'\ndate = 2024-03-05\n+++\n",
+		"content/posts/old/index.en.md": "+++\ntitle = 'Hello'\ndate = 2024-03-05\n+++\n",
+		"content/notes/old.md":          "+++\ndate = 2024-03-05\n+++\n",
+		"content/notes/old.en.md":       "+++\ndate = 2024-03-05\n+++\n",
+	})
+	content := filepath.Join(root, "content")
+	site := &hugo.Site{
+		ContentDir:             content,
+		DefaultContentLanguage: "zh",
+		Languages:              []hugo.Language{{Code: "zh"}, {Code: "en"}},
+		Permalinks:             map[string]string{"posts": "/:year/:month/:slug/", "notes": "/:year/:filename/"},
+	}
+
+	// the URLs follow the title, moving the bundle keeps them.
+	tp := template.Template{Dir: filepath.Join(content, "posts"), NeedDir: true}
+	plan, err := PlanMove(MoveArgs{Template: tp, From: "old", To: "new", ContentDir: content, Site: site})
+	if err != nil || len(plan.Aliases) != 0 {
+		t.Fatalf("Expected no alias for the unchanged URLs: %+v, %v", plan, err)
+	}
+
+	tp = template.Template{Dir: filepath.Join(content, "notes")}
+	plan, err = PlanMove(MoveArgs{Template: tp, From: "old", To: "new", ContentDir: content, Site: site})
+	if err != nil {
+		t.Fatalf("PlanMove failed: %v", err)
+	}
+	var got []string
+	for _, a := range plan.Aliases {
+		got = append(got, filepath.Base(a.File)+" "+a.Alias)
+	}
+	if strings.Join(got, ",") != "new.md /2024/old/,new.en.md /en/2024/old/" {
+		t.Fatalf("Unexpected aliases: %v", got)
+	}
+
+	// a URL which can't be resolved refuses the move.
+	site.Permalinks["notes"] = "/:year/:unknown/"
+	if _, err = PlanMove(MoveArgs{Template: tp, From: "old", To: "new", ContentDir: content, Site: site}); err == nil {
+		t.Fatal("Expected an unsupported permalink to refuse the move")
+	}
+}
+
+func TestMoveFile(t *testing.T) {
+	root := writeSite(t, map[string]string{
+		"posts/old.md":           "+++\nslug = 'kept'\n+++\n[a](a.md)\n",
+		"posts/old.en.md":        "---\ntitle: old\n---\n",
+		"posts/old.summary.text": "summary",
+		"posts/a.md":             "[old](old/) [en](old.en.md) [x](oldx.md)\n",
+	})
+	tp := template.Template{Dir: filepath.Join(root, "posts")}
+
+	plan, err := PlanMove(MoveArgs{Template: tp, From: "old.md", To: "sub/new", Posts: postFiles(t, tp.Dir)})
+	if err != nil {
+		t.Fatalf("PlanMove failed: %v", err)
+	}
+	if len(plan.Renames) != 3 || len(plan.Aliases) != 0 {
+		t.Fatalf("Unexpected plan: %+v", plan)
+	}
+	if err = plan.Apply(); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if got := readFile(t, filepath.Join(tp.Dir, "a.md")); got != "[old](sub/new/) [en](sub/new.en.md) [x](oldx.md)\n" {
+		t.Fatalf("Unexpected rewrites: %s", got)
+	}
+	if got := readFile(t, filepath.Join(tp.Dir, "sub", "new.md")); !strings.HasSuffix(got, "[a](../a.md)\n") {
+		t.Fatalf("Expected the link of the moved post rewritten: %s", got)
+	}
+	if readFile(t, tp.SummaryPath("sub/new")) != "summary" {
+		t.Fatal("Expected the summary moved")
+	}
+
+	for _, args := range []MoveArgs{{From: "nope", To: "x"}, {From: "a", To: "sub/new"}, {From: "a", To: "a"}} {
+		args.Template = tp
+		if _, err = PlanMove(args); err == nil {
+			t.Fatalf("Expected %s -> %s to fail", args.From, args.To)
+		}
+	}
+}
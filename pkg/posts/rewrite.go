@@ -0,0 +1,215 @@
+package posts
+
+import (
+	"fmt"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/links"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// mapping maps the old paths of the moved files and dirs to the new paths, the paths under a dir move with it.
+type mapping []Rename
+
+// apply returns the new path of p, the second result is false if p doesn't move.
+func (m mapping) apply(p string) (string, bool) {
+	for _, r := range m {
+		if p == r.From {
+			return r.To, true
+		}
+	}
+	for _, r := range m {
+		if rel, err := filepath.Rel(r.From, p); err == nil && rel != "." && !isOutside(rel) {
+			return filepath.Join(r.To, rel), true
+		}
+	}
+	return p, false
+}
+
+func isOutside(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// rewrites returns the rewrites of the links of posts to the paths moved by m, and of the relative links of the
+// moved posts. The paths are resolved before the move.
+func rewrites(posts []string, m mapping, contentDir string) ([]Rewrite, error) {
+	var res []Rewrite
+	for _, post := range posts {
+		data, err := os.ReadFile(post)
+		if err != nil {
+			return nil, err
+		}
+		postLinks, err := links.Extract(post, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", post, err)
+		}
+
+		for _, l := range postLinks {
+			if l.Kind != links.KindRelative && l.Kind != links.KindRef {
+				continue
+			}
+			target, suffix := splitTarget(l.Target)
+			if target == "" {
+				continue
+			}
+
+			if to, ok := rewriteTarget(l.Kind, target, post, m, contentDir); ok && to != target {
+				res = append(res, Rewrite{
+					File:   post,
+					Line:   l.Line,
+					Col:    l.Col,
+					Offset: l.Offset,
+					From:   l.Target,
+					To:     to + suffix,
+				})
+			}
+		}
+	}
+	return res, nil
+}
+
+// splitTarget splits the link target into the path and the query or fragment.
+func splitTarget(target string) (string, string) {
+	if i := strings.IndexAny(target, "?#"); i >= 0 {
+		return target[:i], target[i:]
+	}
+	return target, ""
+}
+
+// rewriteTarget returns the target path of the link of kind in post after the move, the second result is false if
+// the target isn't changed by the move. The style of the target is kept: relative to the post or to the content
+// dir, escaped or not, with or without the trailing slash.
+func rewriteTarget(kind, target, post string, m mapping, contentDir string) (string, bool) {
+	p := target
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		p = unescaped
+	}
+	newPost, postMoved := m.apply(post)
+
+	// the site absolute links are URLs the aliases keep working, and the absolute refs need the content dir
+	if strings.HasPrefix(p, "/") && (kind == links.KindRelative || contentDir == "") {
+		return "", false
+	}
+
+	if kind == links.KindRef && contentDir != "" {
+		base := filepath.Join(filepath.Dir(post), p)
+		if _, moved := m.apply(base); strings.HasPrefix(p, "/") || (!moved && !exists(base)) {
+			newBase, ok := m.apply(filepath.Join(contentDir, p))
+			if !ok {
+				return "", false
+			}
+			rel, err := filepath.Rel(contentDir, newBase)
+			if err != nil {
+				return "", false
+			}
+			if strings.HasPrefix(p, "/") {
+				rel = "/" + rel
+			}
+			return format(rel, target, p), true
+		}
+	}
+
+	base := filepath.Join(filepath.Dir(post), p)
+	newBase, moved := m.apply(base)
+	if !moved && !postMoved {
+		return "", false
+	}
+	rel, err := filepath.Rel(filepath.Dir(newPost), newBase)
+	if err != nil {
+		return "", false
+	}
+	if strings.HasPrefix(p, "./") && !strings.HasPrefix(rel, "..") {
+		rel = "./" + rel
+	}
+	return format(rel, target, p), true
+}
+
+// format formats the new path rel like the old target, whose unescaped path is p.
+func format(rel, target, p string) string {
+	rel = filepath.ToSlash(rel)
+	if strings.HasSuffix(p, "/") && !strings.HasSuffix(rel, "/") {
+		rel += "/"
+	}
+	if p == target {
+		return rel
+	}
+
+	parts := strings.Split(rel, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// exists reports whether a link to base leads to a file: base itself or the Markdown file of the page base.
+func exists(base string) bool {
+	if _, err := os.Stat(base); err == nil {
+		return true
+	}
+	_, err := os.Stat(base + ".md")
+	return err == nil
+}
+
+// applyRewrites replaces the link targets, the files are read from their paths after the renames.
+func (p *Plan) applyRewrites() error {
+	byFile := map[string][]Rewrite{}
+	var files []string
+	for _, r := range p.Rewrites {
+		if _, ok := byFile[r.File]; !ok {
+			files = append(files, r.File)
+		}
+		byFile[r.File] = append(byFile[r.File], r)
+	}
+
+	for _, f := range files {
+		path, _ := p.mapping.apply(f)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		// replace from the end, the offsets of the earlier targets are kept
+		rs := byFile[f]
+		sort.Slice(rs, func(i, j int) bool {
+			if rs[i].Line != rs[j].Line {
+				return rs[i].Line > rs[j].Line
+			}
+			return rs[i].Offset > rs[j].Offset
+		})
+
+		lines := strings.Split(string(data), "\n")
+		for _, r := range rs {
+			line := lines[r.Line-1]
+			if !strings.HasPrefix(line[min(r.Offset, len(line)):], r.From) {
+				return fmt.Errorf("%s:%d: link '%s' changed since the plan", path, r.Line, r.From)
+			}
+			lines[r.Line-1] = line[:r.Offset] + r.To + line[r.Offset+len(r.From):]
+		}
+
+		if err = os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyAliases appends the aliases to the front matter of the posts.
+func (p *Plan) applyAliases() error {
+	for _, a := range p.Aliases {
+		doc, err := frontmatter.ReadFile(a.File)
+		if err != nil {
+			return err
+		}
+		aliases := append(doc.GetStrings("aliases"), a.Alias)
+		if err = doc.Set("aliases", aliases); err != nil {
+			return err
+		}
+		if err = doc.WriteFile(a.File); err != nil {
+			return err
+		}
+	}
+	return nil
+}
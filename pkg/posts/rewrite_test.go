@@ -0,0 +1,44 @@
+package posts
+
+import (
+	"github.io/uberate/hcli/pkg/links"
+	"path/filepath"
+	"testing"
+)
+
+func TestMappingApply(t *testing.T) {
+	m := mapping{{From: "/c/posts/a", To: "/c/posts/b"}, {From: "/c/posts/a.md", To: "/c/x.md"}}
+
+	for p, want := range map[string]string{
+		"/c/posts/a.md":           "/c/x.md",
+		"/c/posts/a":              "/c/posts/b",
+		"/c/posts/a/index.md":     "/c/posts/b/index.md",
+		"/c/posts/ab/index.md":    "",
+		"/c/posts/a/../other.md":  "",
+		"/c/posts/a/sub/logo.png": "/c/posts/b/sub/logo.png",
+	} {
+		got, ok := m.apply(filepath.Clean(p))
+		if (want == "") == ok || (ok && got != want) {
+			t.Fatalf("Unexpected mapping of %s: %s, %v", p, got, ok)
+		}
+	}
+}
+
+func TestRewriteTarget(t *testing.T) {
+	m := mapping{{From: "/c/posts/my post", To: "/c/posts/新 post"}}
+
+	for target, want := range map[string]string{
+		"my%20post/":  "%E6%96%B0%20post/",
+		"./my post":   "./新 post",
+		"../my post/": "",
+	} {
+		got, ok := rewriteTarget(links.KindRelative, target, "/c/posts/a.md", m, "/c")
+		if (want == "") == ok || got != want {
+			t.Fatalf("Unexpected rewrite of %s: %q, %v", target, got, ok)
+		}
+	}
+
+	if path, suffix := splitTarget("a/b.md?x=1#top"); path != "a/b.md" || suffix != "?x=1#top" {
+		t.Fatalf("Unexpected split: %s %s", path, suffix)
+	}
+}
@@ -0,0 +1,94 @@
+package posts
+
+import (
+	"github.io/uberate/hcli/pkg/hugo"
+	"github.io/uberate/hcli/pkg/template"
+	"time"
+)
+
+// Rename is a file or a dir moved.
+type Rename struct {
+	From string
+	To   string
+}
+
+// Rewrite is a link target of a post replaced.
+type Rewrite struct {
+	// File is the path of the post before the renames.
+	File string
+	// Line and Col locate the link, Offset is the byte offset of From in the line.
+	Line   int
+	Col    int
+	Offset int
+	From   string
+	To     string
+}
+
+// Alias is an old URL added to the aliases of a post.
+type Alias struct {
+	// File is the path of the post after the renames.
+	File  string
+	Alias string
+}
+
+// Plan is the changes of a reorganization, in the order they are applied.
+type Plan struct {
+	Renames  []Rename
+	Rewrites []Rewrite
+	Aliases  []Alias
+	// Removes are the dirs removed after the renames, they must be empty then.
+	Removes []string
+
+	// mapping maps the old paths to the new ones, the renames and the page paths of the moved posts.
+	mapping mapping
+}
+
+type MoveArgs struct {
+	Template template.Template
+	// From and To are the file names of the post, as given to 'hcli gen posts'.
+	From string
+	To   string
+	// ContentDir is the content dir of the Hugo site, the content relative refs are relative to it.
+	ContentDir string
+	// Site resolves the URLs of the moved pages, the old URL is added to the aliases of a page whose URL changes.
+	// Nil means no alias is added.
+	Site *hugo.Site
+	// Posts are the posts whose links are rewritten, the moved posts included.
+	Posts []string
+}
+
+type BundleArgs struct {
+	Template template.Template
+	// Name is the file name of the post, as given to 'hcli gen posts'.
+	Name string
+	// ContentDir is the content dir of the Hugo site, the content relative refs are relative to it.
+	ContentDir string
+	// Posts are the posts whose links are rewritten, the converted post included.
+	Posts []string
+}
+
+// The front matter keys of the publishing state.
+const (
+	KeyDraft       = "draft"
+	KeyDate        = "date"
+	KeyLastmod     = "lastmod"
+	KeyPublishDate = "publishDate"
+)
+
+// The statuses of posts.
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusPublished = "published"
+)
+
+// Draft is a post not published yet.
+type Draft struct {
+	Path   string
+	Title  string
+	Status string
+	// Date is the date of the post, or the publishDate of a scheduled post.
+	Date time.Time
+	// Age is the time since Date, negative for the scheduled posts.
+	Age time.Duration
+}
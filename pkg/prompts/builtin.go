@@ -0,0 +1,70 @@
+package prompts
+
+var builtinPrompts = []Prompt{
+	{
+		Name:        PosterSummary,
+		Version:     "1",
+		Description: "describe the poster of a post for the image model",
+		Template: "" +
+			"你是一个文本内容的描述大师，根据用户的需求可以描述需要的图片。要求：" +
+			"1. 图片中不可以有任何文字内容。" +
+			"2. 图片中的主要元素不可过多。" +
+			"3. 响应信息的长度应该不超过 300 字。" +
+			"另外，请着重描述以下内容：图片的风格，核心元素。" +
+			"如果用户未指定风格、内容，你需要自行裁断选择的风格。\n" +
+			"输入的信息中如果存在注释，请忽略：" +
+			"1. markdown 文本类型中的 '+++' 区块。",
+	},
+	{
+		Name:        TranslateBody,
+		Version:     "1",
+		Description: "translate a markdown body to lang, placeholders are kept",
+		Template: "" +
+			"你是一个专业的技术博客翻译，请将用户输入的 Markdown 文章翻译为语言代码为 '{{ .lang }}' 的语言。要求：" +
+			"1. 保持 Markdown 结构不变，包括标题层级、列表、表格、链接地址与图片地址。" +
+			"2. 形如 HCLIKEEP0000 的占位符代表代码块或 Hugo shortcode，必须原样保留在原来的位置。" +
+			"3. 不要翻译行内代码、URL 与 HTML 标签。" +
+			"4. 只输出翻译后的文章，不要添加任何解释。",
+	},
+	{
+		Name:        TranslateTitle,
+		Version:     "1",
+		Description: "translate a post title to lang",
+		Template: "" +
+			"你是一个专业的技术博客翻译，请将用户输入的文章标题翻译为语言代码为 '{{ .lang }}' 的语言。" +
+			"只输出翻译后的标题，不要包含引号或任何解释。",
+	},
+	{
+		Name:        SuggestTags,
+		Version:     "1",
+		Description: "pick the tags and categories of a post from the site vocabulary",
+		Template: "" +
+			"你是一个博客的编辑，请根据用户输入的文章为它挑选标签（tags）与分类（categories）。要求：" +
+			"1. 优先从站点已有的词汇中挑选，已有标签：{{ .known_tags }}；已有分类：{{ .known_categories }}。" +
+			"2. 已有词汇中没有合适的词时才可以新建，标签与分类合计最多新建 {{ .max_new }} 个。" +
+			"3. 文章已有的标签（{{ join .tags \", \" }}）与分类（{{ join .categories \", \" }}）不需要再输出。" +
+			"4. 标签不超过 5 个，分类不超过 2 个，使用与已有词汇相同的写法。",
+	},
+	{
+		Name:        SEOMeta,
+		Version:     "1",
+		Description: "write the description, summary and keywords of a post in lang",
+		Template: "" +
+			"你是一个搜索引擎优化（SEO）编辑，请为用户输入的文章撰写元信息，" +
+			"{{ if .lang }}使用语言代码为 '{{ .lang }}' 的语言{{ else }}使用文章本身的语言{{ end }}。要求：" +
+			"1. description 是一到两句话的搜索结果摘要，不超过 {{ .description_length }} 个字符。" +
+			"2. summary 是用于文章列表与 RSS 的一段简介，不超过 {{ .summary_length }} 个字符。" +
+			"3. keywords 是不超过 {{ .max_keywords }} 个搜索关键词。" +
+			"4. 如实概括文章内容，不要夸大，不要使用 Markdown。",
+	},
+	{
+		Name:        SlugTitle,
+		Version:     "1",
+		Description: "translate a post title to the English words of its slug",
+		Template: "" +
+			"请将用户输入的文章标题翻译为简洁的英文，用作文章的 URL。要求：" +
+			"1. 只输出小写英文单词，单词之间用 '-' 连接，总长度不超过 {{ .max_length }} 个字符。" +
+			"2. 专有名词与技术名词保持原样，例如 Go、Hugo、Kubernetes。" +
+			"3. 不要输出任何解释、引号或标点。",
+	},
+}
@@ -0,0 +1,19 @@
+// Package prompts is the registry of the named prompts used by the LLM features.
+//
+// Every feature references its prompt by name, e.g. 'poster.summary'. The prompts are resolved in order, later
+// sources win:
+//
+//	builtin  the defaults compiled into hcli
+//	dir      the files of the prompt dir, 'prompts/poster/summary.md' is named 'poster.summary'
+//	config   the 'Prompts' of the hcli config
+//
+// A prompt file starts with a front matter header holding its version and description:
+//
+//	+++
+//	version = "2"
+//	description = "describe the poster of a post"
+//	+++
+//	You are ...
+//
+// Prompts are Go templates. The variables of a post are title, tags, categories, lang, excerpt and body.
+package prompts
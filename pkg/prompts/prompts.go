@@ -0,0 +1,196 @@
+package prompts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+const excerptLength = 500
+
+var promptExts = map[string]bool{".md": true, ".txt": true, ".tmpl": true}
+
+var promptFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// NewRegistry returns a registry holding the builtin prompts.
+func NewRegistry() *Registry {
+	r := &Registry{prompts: map[string]Prompt{}}
+	for _, p := range builtinPrompts {
+		p.Source = SourceBuiltin
+		r.prompts[p.Name] = p
+	}
+
+	return r
+}
+
+// Add adds p, a prompt with the same name is replaced. The template is checked before it is added.
+func (r *Registry) Add(p Prompt) error {
+	if p.Name == "" {
+		return errors.New("prompt name is required")
+	}
+	if _, err := parse(p); err != nil {
+		return err
+	}
+
+	r.prompts[p.Name] = p
+	return nil
+}
+
+// LoadDir adds the prompt files in dir, the name of a file is its path relative to dir joined by dots. A missing dir
+// is no error.
+func (r *Registry) LoadDir(dir string) error {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil
+	}
+
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !promptExts[filepath.Ext(p)] {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name := strings.ReplaceAll(strings.TrimSuffix(rel, filepath.Ext(rel)), string(filepath.Separator), ".")
+
+		prompt, err := ReadFile(p, name)
+		if err != nil {
+			return err
+		}
+		return r.Add(prompt)
+	})
+}
+
+// ReadFile reads the prompt file at path. The front matter header holds version, description and an optional name
+// which overrides name.
+func ReadFile(path, name string) (Prompt, error) {
+	doc, err := frontmatter.ReadFile(path)
+	if err != nil {
+		return Prompt{}, fmt.Errorf("read prompt %s: %w", path, err)
+	}
+
+	p := Prompt{
+		Name:        name,
+		Version:     doc.GetString("version"),
+		Description: doc.GetString("description"),
+		Template:    strings.TrimSpace(doc.Body),
+		Source:      path,
+	}
+	if n := doc.GetString("name"); n != "" {
+		p.Name = n
+	}
+
+	return p, nil
+}
+
+// Get returns the prompt of name, a nil registry only has the builtin prompts.
+func (r *Registry) Get(name string) (Prompt, error) {
+	if r == nil {
+		r = NewRegistry()
+	}
+
+	p, ok := r.prompts[name]
+	if !ok {
+		return Prompt{}, fmt.Errorf("prompt %s not found, see 'hcli prompts list'", name)
+	}
+	return p, nil
+}
+
+// List returns the prompts sorted by name.
+func (r *Registry) List() []Prompt {
+	res := make([]Prompt, 0, len(r.prompts))
+	for _, p := range r.prompts {
+		res = append(res, p)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+
+	return res
+}
+
+// Render executes the prompt of name with vars.
+func (r *Registry) Render(name string, vars Vars) (string, error) {
+	p, err := r.Get(name)
+	if err != nil {
+		return "", err
+	}
+
+	return p.Render(vars)
+}
+
+// Render executes the prompt with vars.
+func (p Prompt) Render(vars Vars) (string, error) {
+	t, err := parse(p)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err = t.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("render prompt %s: %w", p.Name, err)
+	}
+
+	return buf.String(), nil
+}
+
+func parse(p Prompt) (*template.Template, error) {
+	t, err := template.New(p.Name).Funcs(promptFuncs).Parse(p.Template)
+	if err != nil {
+		return nil, fmt.Errorf("parse prompt %s: %w", p.Name, err)
+	}
+	return t, nil
+}
+
+// PostVars returns the variables of the post content, lang is the language the prompt works in.
+func PostVars(content []byte, lang string) Vars {
+	vars := Vars{
+		"title":      "",
+		"tags":       []string{},
+		"categories": []string{},
+		"lang":       lang,
+		"excerpt":    "",
+		"body":       string(content),
+	}
+
+	doc, err := frontmatter.Parse(content)
+	if err != nil {
+		return vars
+	}
+
+	vars["title"] = doc.GetString("title")
+	vars["body"] = doc.Body
+	vars["excerpt"] = Excerpt(doc.Body, excerptLength)
+	if tags := doc.GetStrings("tags"); tags != nil {
+		vars["tags"] = tags
+	}
+	if categories := doc.GetStrings("categories"); categories != nil {
+		vars["categories"] = categories
+	}
+
+	return vars
+}
+
+// Excerpt returns the first n runes of body with the whitespace collapsed.
+func Excerpt(body string, n int) string {
+	res := strings.Join(strings.Fields(body), " ")
+	if utf8.RuneCountInString(res) <= n {
+		return res
+	}
+
+	return string([]rune(res)[:n])
+}
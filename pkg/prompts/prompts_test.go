@@ -0,0 +1,102 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinPrompts(t *testing.T) {
+	r := NewRegistry()
+	for _, name := range []string{PosterSummary, TranslateBody, TranslateTitle} {
+		p, err := r.Get(name)
+		if err != nil {
+			t.Fatalf("Get %s failed: %v", name, err)
+		}
+		if p.Source != SourceBuiltin || p.Version == "" {
+			t.Fatalf("Unexpected builtin prompt: %+v", p)
+		}
+	}
+
+	res, err := r.Render(TranslateTitle, Vars{"lang": "en"})
+	if err != nil || !strings.Contains(res, "'en'") {
+		t.Fatalf("Unexpected render: %s, err: %v", res, err)
+	}
+
+	var nilRegistry *Registry
+	if _, err = nilRegistry.Get(PosterSummary); err != nil {
+		t.Fatalf("Expected a nil registry to have the builtin prompts: %v", err)
+	}
+	if _, err = r.Get("missing"); err == nil {
+		t.Fatal("Expected a missing prompt to fail")
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "poster"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"poster/summary.md": "+++\nversion = \"2\"\ndescription = \"tuned\"\n+++\nDraw {{ .title }} with {{ join .tags \", \" }}\n",
+		"custom.txt":        "---\nname: renamed\n---\n{{ .lang }}",
+		"notes.json":        "{}",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewRegistry()
+	if err := r.LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir failed: %v", err)
+	}
+	if err := r.LoadDir(filepath.Join(dir, "missing")); err != nil {
+		t.Fatalf("Expected a missing dir to be skipped: %v", err)
+	}
+
+	p, err := r.Get(PosterSummary)
+	if err != nil || p.Version != "2" || p.Description != "tuned" || !strings.HasSuffix(p.Source, "summary.md") {
+		t.Fatalf("Expected the file to override the builtin prompt: %+v, err: %v", p, err)
+	}
+
+	res, err := r.Render(PosterSummary, PostVars([]byte("+++\ntitle = 'Go'\ntags = ['a', 'b']\n+++\nbody"), ""))
+	if err != nil || res != "Draw Go with a, b" {
+		t.Fatalf("Unexpected render: %q, err: %v", res, err)
+	}
+
+	if _, err = r.Get("renamed"); err != nil {
+		t.Fatalf("Expected the header name to win: %v", err)
+	}
+	if _, err = r.Get("notes"); err == nil {
+		t.Fatal("Expected non prompt files to be skipped")
+	}
+
+	if err = r.Add(Prompt{Name: "bad", Template: "{{ .title"}); err == nil {
+		t.Fatal("Expected a broken template to fail")
+	}
+
+	names := []string{}
+	for _, p := range r.List() {
+		names = append(names, p.Name)
+	}
+	if strings.Join(names, ",") != "poster.summary,renamed,seo.meta,slug.title,suggest.tags,translate.body,translate.title" {
+		t.Fatalf("Unexpected list: %v", names)
+	}
+}
+
+func TestPostVars(t *testing.T) {
+	vars := PostVars([]byte("+++\ntitle = 'Go'\n+++\n\nline one\n\nline   two\n"), "zh")
+	if vars["title"] != "Go" || vars["lang"] != "zh" || vars["excerpt"] != "line one line two" {
+		t.Fatalf("Unexpected vars: %v", vars)
+	}
+	if tags := vars["tags"].([]string); len(tags) != 0 {
+		t.Fatalf("Expected empty tags: %v", tags)
+	}
+
+	if res := Excerpt("你好世界", 2); res != "你好" {
+		t.Fatalf("Unexpected excerpt: %s", res)
+	}
+}
@@ -0,0 +1,38 @@
+package prompts
+
+const (
+	SourceBuiltin = "builtin"
+	SourceConfig  = "config"
+
+	// PosterSummary describes the poster of a post for the image model.
+	PosterSummary = "poster.summary"
+	// TranslateBody translates a markdown body to lang.
+	TranslateBody = "translate.body"
+	// TranslateTitle translates a post title to lang.
+	TranslateTitle = "translate.title"
+	// SuggestTags picks the tags and categories of a post from the vocabulary of the site.
+	SuggestTags = "suggest.tags"
+	// SEOMeta writes the description, summary and keywords of a post in lang.
+	SEOMeta = "seo.meta"
+	// SlugTitle translates a post title to the English words of its slug.
+	SlugTitle = "slug.title"
+)
+
+// Prompt is a named, versioned prompt template.
+type Prompt struct {
+	Name        string `yaml:"Name" describe:"The prompt name, a builtin name overrides the builtin prompt."`
+	Version     string `yaml:"Version" describe:"The prompt version, shown by 'hcli prompts list'."`
+	Description string `yaml:"Description" describe:"What the prompt is for."`
+	Template    string `yaml:"Template" describe:"The go template of the prompt, see 'hcli prompts show' for the variables."`
+
+	// Source is where the prompt is loaded from: builtin, config or the file path.
+	Source string `yaml:"-"`
+}
+
+// Vars are the template variables of a prompt.
+type Vars map[string]interface{}
+
+// Registry holds the prompts by name.
+type Registry struct {
+	prompts map[string]Prompt
+}
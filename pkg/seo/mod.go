@@ -0,0 +1,7 @@
+// Package seo generates the search metadata of a post with the LLM tools: a description, a summary and keywords,
+// written to the 'description', 'summary' and 'keywords' front matter keys Hugo themes read for the meta tags and
+// the RSS feed.
+//
+// The metadata is asked for as JSON in the language of the post. The model is told the length limits, and longer
+// answers are cut to them, so a description always fits a search snippet.
+package seo
@@ -0,0 +1,149 @@
+package seo
+
+import (
+	"context"
+	"errors"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/prompts"
+	"strings"
+	"unicode"
+)
+
+// Missing returns the metadata keys doc has no non-empty value of.
+func Missing(doc *frontmatter.Document) []string {
+	var res []string
+	for _, key := range Keys {
+		if !hasValue(doc, key) {
+			res = append(res, key)
+		}
+	}
+	return res
+}
+
+func hasValue(doc *frontmatter.Document, key string) bool {
+	for _, v := range doc.GetStrings(key) {
+		if strings.TrimSpace(v) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Generate asks the model for the metadata of the post, the description and summary are cut to their limits.
+func Generate(ctx context.Context, args GenerateArgs) (*Metadata, error) {
+	if args.LLMTools == nil {
+		return nil, errors.New("LLMTools is required for seo")
+	}
+
+	descriptionLength := args.DescriptionLength
+	if descriptionLength <= 0 {
+		descriptionLength = DefaultDescriptionLength
+	}
+	summaryLength := args.SummaryLength
+	if summaryLength <= 0 {
+		summaryLength = DefaultSummaryLength
+	}
+
+	doc, err := frontmatter.Parse(args.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := prompts.PostVars(args.Content, args.Lang)
+	vars["description_length"] = descriptionLength
+	vars["summary_length"] = summaryLength
+	vars["max_keywords"] = maxKeywords
+	sysPrompt, err := args.Prompts.Render(prompts.SEOMeta, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	input := doc.Body
+	if title := doc.GetString("title"); title != "" {
+		input = "# " + title + "\n\n" + input
+	}
+
+	var m Metadata
+	if _, err = llms.TextJSON(llms.WithTask(ctx, llms.TaskSummary), args.LLMTools, llms.JSONArgs{
+		SysPrompt: sysPrompt,
+		Input:     input,
+		Name:      "post_seo",
+	}, &m); err != nil {
+		return nil, err
+	}
+
+	m.Description = Truncate(m.Description, descriptionLength)
+	m.Summary = Truncate(m.Summary, summaryLength)
+	m.Keywords = cleanKeywords(m.Keywords)
+
+	return &m, nil
+}
+
+// Apply sets the metadata on doc. The keys with a non-empty value are kept unless overwrite, and empty metadata is
+// never set. It returns the keys set.
+func Apply(doc *frontmatter.Document, m Metadata, overwrite bool) ([]string, error) {
+	var res []string
+	for _, kv := range []struct {
+		key   string
+		value interface{}
+		empty bool
+	}{
+		{KeyDescription, m.Description, m.Description == ""},
+		{KeySummary, m.Summary, m.Summary == ""},
+		{KeyKeywords, m.Keywords, len(m.Keywords) == 0},
+	} {
+		if kv.empty || (!overwrite && hasValue(doc, kv.key)) {
+			continue
+		}
+		if err := doc.Set(kv.key, kv.value); err != nil {
+			return res, err
+		}
+		res = append(res, kv.key)
+	}
+
+	return res, nil
+}
+
+// Truncate cuts s to at most n characters with the whitespace collapsed. A cut text ends at the last sentence or
+// word end which keeps most of it, followed by '…'.
+func Truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(runes[:n])
+	}
+
+	cut := runes[:n-1]
+	for i := len(cut) - 1; i >= len(cut)/2; i-- {
+		if unicode.IsSpace(cut[i]) || unicode.IsPunct(cut[i]) {
+			cut = cut[:i]
+			break
+		}
+	}
+
+	return strings.TrimRightFunc(string(cut), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
+
+// cleanKeywords trims the keywords, drops the empty and duplicated ones and keeps at most maxKeywords.
+func cleanKeywords(keywords []string) []string {
+	seen := map[string]bool{}
+	var res []string
+	for _, k := range keywords {
+		k = strings.TrimSpace(k)
+		if k == "" || seen[strings.ToLower(k)] {
+			continue
+		}
+		seen[strings.ToLower(k)] = true
+		res = append(res, k)
+		if len(res) == maxKeywords {
+			break
+		}
+	}
+	return res
+}
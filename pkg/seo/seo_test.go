@@ -0,0 +1,102 @@
+package seo
+
+import (
+	"context"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/llms"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// fakeLLM answers every text call with answer and keeps the system prompt.
+type fakeLLM struct {
+	answer    string
+	sysPrompt string
+}
+
+func (f *fakeLLM) Text(ctx context.Context, sysPrompt, input string) (string, llms.Usage, error) {
+	f.sysPrompt = sysPrompt
+	return f.answer, llms.Usage{}, nil
+}
+
+func (f *fakeLLM) Pic(ctx context.Context, input string) ([]byte, llms.Usage, error) {
+	return nil, llms.Usage{}, nil
+}
+
+func TestMissing(t *testing.T) {
+	doc, err := frontmatter.Parse([]byte("+++\ndescription = 'd'\nsummary = ' '\nkeywords = []\n+++\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(Missing(doc), ","); got != "summary,keywords" {
+		t.Fatalf("Unexpected missing keys: %s", got)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	fake := &fakeLLM{answer: `{"description": "` + strings.Repeat("word ", 10) + `", "summary": "s",
+		"keywords": ["go", " Go ", "", "hugo"]}`}
+
+	m, err := Generate(context.Background(), GenerateArgs{
+		LLMTools:          fake,
+		Content:           []byte("+++\ntitle = 'post'\n+++\nbody"),
+		Lang:              "en",
+		DescriptionLength: 20,
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if m.Description != "word word word…" {
+		t.Fatalf("Unexpected description: %q", m.Description)
+	}
+	if strings.Join(m.Keywords, ",") != "go,hugo" {
+		t.Fatalf("Unexpected keywords: %q", m.Keywords)
+	}
+	if !strings.Contains(fake.sysPrompt, "'en'") || !strings.Contains(fake.sysPrompt, "不超过 20 个字符") {
+		t.Fatalf("Expected the language and the limit in the prompt: %s", fake.sysPrompt)
+	}
+}
+
+func TestApply(t *testing.T) {
+	doc, err := frontmatter.Parse([]byte("+++\ntitle = 'post'\ndescription = 'kept'\n+++\nbody"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := Metadata{Description: "new", Summary: "s", Keywords: []string{"go"}}
+	set, err := Apply(doc, m, false)
+	if err != nil || strings.Join(set, ",") != "summary,keywords" {
+		t.Fatalf("Unexpected keys set: %v, %v", set, err)
+	}
+	if doc.GetString("description") != "kept" || doc.GetString("summary") != "s" {
+		t.Fatalf("Unexpected front matter: %s", doc.Bytes())
+	}
+
+	if set, _ = Apply(doc, Metadata{Description: "new"}, true); len(set) != 1 || doc.GetString("description") != "new" {
+		t.Fatalf("Expected the description overwritten: %v", set)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"a  b\n c", 10, "a b c"},
+		{"hello world, this is long", 14, "hello world…"},
+		{"这是一个很长的中文描述，需要截断。", 10, "这是一个很长的中文…"},
+	} {
+		got := Truncate(c.in, c.n)
+		if got != c.want {
+			t.Fatalf("Truncate(%q, %d) = %q, want %q", c.in, c.n, got, c.want)
+		}
+		if utf8.RuneCountInString(got) > c.n {
+			t.Fatalf("Truncate(%q, %d) is too long: %q", c.in, c.n, got)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package seo
+
+import (
+	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/prompts"
+)
+
+// The front matter keys of the metadata.
+const (
+	KeyDescription = "description"
+	KeySummary     = "summary"
+	KeyKeywords    = "keywords"
+)
+
+// Keys are the front matter keys of the metadata, in order.
+var Keys = []string{KeyDescription, KeySummary, KeyKeywords}
+
+const (
+	DefaultDescriptionLength = 160
+	DefaultSummaryLength     = 300
+	maxKeywords              = 8
+)
+
+// Metadata is the search metadata of a post.
+type Metadata struct {
+	Description string   `json:"description" describe:"One or two sentences for the search result snippet"`
+	Summary     string   `json:"summary" describe:"A short paragraph for the post list and the RSS feed"`
+	Keywords    []string `json:"keywords" describe:"The search keywords of the post"`
+}
+
+type GenerateArgs struct {
+	LLMTools llms.LLMTools
+	// Prompts resolves the prompt names, nil means the builtin prompts.
+	Prompts *prompts.Registry
+	// Content is the post, front matter included.
+	Content []byte
+	// Lang is the language of the metadata, the language of the post.
+	Lang string
+	// DescriptionLength and SummaryLength are the max lengths in characters, zero means the defaults.
+	DescriptionLength int
+	SummaryLength     int
+}
@@ -0,0 +1,6 @@
+// Package slug derives the ASCII file names of posts from their titles.
+//
+// A slug is lower case ASCII letters and digits joined by dashes. Chinese characters are transliterated offline to
+// their pinyin without tones, one word per character, and the accents of Latin letters are dropped. The LLM mode
+// translates the title to English first, for slugs which read as words instead of pinyin.
+package slug
@@ -0,0 +1,119 @@
+package slug
+
+import (
+	"context"
+	"fmt"
+	"github.com/mozillazg/go-pinyin"
+	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/prompts"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+var pinyinArgs = pinyin.NewArgs()
+
+// Pinyin returns the slug of title, the Chinese characters are written in pinyin.
+func Pinyin(title string) string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() != 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range norm.NFD.String(title) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			if py := pinyin.SinglePinyin(r, pinyinArgs); len(py) != 0 {
+				words = append(words, py[0])
+			}
+		case unicode.Is(unicode.Mn, r):
+			// the accents split off by NFD
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(unicode.ToLower(r))
+		case r == '\'' || r == '’':
+			// keep "don't" as one word
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return join(words)
+}
+
+// Normalize returns s as a slug, the characters out of ASCII letters and digits are dropped.
+func Normalize(s string) string {
+	var words []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(norm.NFD.String(s)), func(r rune) bool {
+		return !(r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))) && !unicode.Is(unicode.Mn, r)
+	}) {
+		w = strings.Map(func(r rune) rune {
+			if unicode.Is(unicode.Mn, r) {
+				return -1
+			}
+			return r
+		}, w)
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+
+	return join(words)
+}
+
+// join joins words with dashes, at most MaxLength characters and whole words.
+func join(words []string) string {
+	res := ""
+	for _, w := range words {
+		if res == "" {
+			res = w
+		} else if len(res)+1+len(w) <= MaxLength {
+			res += "-" + w
+		} else {
+			break
+		}
+	}
+
+	if len(res) > MaxLength {
+		res = res[:MaxLength]
+	}
+	return res
+}
+
+// Translate returns the slug of the English translation of title by tools.
+func Translate(ctx context.Context, tools llms.LLMTools, reg *prompts.Registry, title string) (string, error) {
+	sysPrompt, err := reg.Render(prompts.SlugTitle, prompts.Vars{"max_length": MaxLength})
+	if err != nil {
+		return "", err
+	}
+
+	res, _, err := tools.Text(llms.WithTask(ctx, llms.TaskTranslate), sysPrompt, title)
+	if err != nil {
+		return "", err
+	}
+
+	s := Normalize(res)
+	if s == "" {
+		return "", fmt.Errorf("no slug in the translation '%s'", strings.TrimSpace(res))
+	}
+	return s, nil
+}
+
+// Unique returns s, or s with the first free '-N' suffix from 2 if exists reports s is taken.
+func Unique(s string, exists func(name string) bool) string {
+	if !exists(s) {
+		return s
+	}
+
+	for i := 2; ; i++ {
+		name := fmt.Sprintf("%s-%d", s, i)
+		if !exists(name) {
+			return name
+		}
+	}
+}
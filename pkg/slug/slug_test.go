@@ -0,0 +1,78 @@
+package slug
+
+import (
+	"context"
+	"github.io/uberate/hcli/pkg/llms"
+	"strings"
+	"testing"
+)
+
+type fakeLLM struct {
+	answer string
+}
+
+func (f fakeLLM) Text(ctx context.Context, sysPrompt, input string) (string, llms.Usage, error) {
+	return f.answer, llms.Usage{}, nil
+}
+
+func (f fakeLLM) Pic(ctx context.Context, input string) ([]byte, llms.Usage, error) {
+	return nil, llms.Usage{}, nil
+}
+
+func TestPinyin(t *testing.T) {
+	for title, want := range map[string]string{
+		"中文标题":               "zhong-wen-biao-ti",
+		"Go语言的并发模型":          "go-yu-yan-de-bing-fa-mo-xing",
+		"Hello, World! 2025": "hello-world-2025",
+		"Café Müller":        "cafe-muller",
+		"Don't panic":        "dont-panic",
+		"《深入理解 Kubernetes》：第 1 章": "shen-ru-li-jie-kubernetes-di-1-zhang",
+		"!!!": "",
+	} {
+		if got := Pinyin(title); got != want {
+			t.Fatalf("Pinyin(%q) = %q, want %q", title, got, want)
+		}
+	}
+
+	long := Pinyin(strings.Repeat("长", 40))
+	if len(long) > MaxLength || strings.HasSuffix(long, "-") || strings.HasSuffix(long, "c") {
+		t.Fatalf("Expected a slug cut at a word end: %q", long)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for in, want := range map[string]string{
+		"  Understanding Go Concurrency\n": "understanding-go-concurrency",
+		"\"go-concurrency-model\"":         "go-concurrency-model",
+		"Résumé_tips":                      "resume-tips",
+	} {
+		if got := Normalize(in); got != want {
+			t.Fatalf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	s, err := Translate(context.Background(), fakeLLM{answer: "Go Concurrency Model"}, nil, "Go语言的并发模型")
+	if err != nil || s != "go-concurrency-model" {
+		t.Fatalf("Unexpected slug %q, %v", s, err)
+	}
+
+	if _, err = Translate(context.Background(), fakeLLM{answer: "。"}, nil, "标题"); err == nil {
+		t.Fatal("Expected an empty translation to fail")
+	}
+}
+
+func TestUnique(t *testing.T) {
+	taken := map[string]bool{"post": true, "post-2": true}
+	exists := func(name string) bool {
+		return taken[name]
+	}
+
+	if got := Unique("post", exists); got != "post-3" {
+		t.Fatalf("Unexpected unique slug: %s", got)
+	}
+	if got := Unique("other", exists); got != "other" {
+		t.Fatalf("Unexpected unique slug: %s", got)
+	}
+}
@@ -0,0 +1,10 @@
+package slug
+
+// The slug modes of 'hcli gen posts'.
+const (
+	ModePinyin = "pinyin"
+	ModeLLM    = "llm"
+)
+
+// MaxLength is the max length of a slug, longer slugs are cut at a word end.
+const MaxLength = 60
@@ -0,0 +1,6 @@
+// Package suggest suggests the tags and categories of a post with the LLM tools.
+//
+// The vocabulary of the site is collected from the front matter of all the posts. The model is asked to pick from
+// it and may add at most a fixed number of new terms; the answer is a JSON object validated against a schema.
+// Terms matching the vocabulary case-insensitively are written the way the vocabulary writes them.
+package suggest
@@ -0,0 +1,181 @@
+package suggest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/prompts"
+	"sort"
+	"strings"
+)
+
+// CollectVocabulary counts the tags and categories of the posts at paths.
+func CollectVocabulary(paths []string) (Vocabulary, error) {
+	tags, categories := map[string]*Term{}, map[string]*Term{}
+	for _, p := range paths {
+		doc, err := frontmatter.ReadFile(p)
+		if err != nil {
+			return Vocabulary{}, fmt.Errorf("%s: %w", p, err)
+		}
+
+		count(tags, doc.GetStrings("tags"))
+		count(categories, doc.GetStrings("categories"))
+	}
+
+	return Vocabulary{Tags: sorted(tags), Categories: sorted(categories)}, nil
+}
+
+// count adds names to terms, the first spelling of a name is kept.
+func count(terms map[string]*Term, names []string) {
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if t, ok := terms[key]; ok {
+			t.Count++
+		} else {
+			terms[key] = &Term{Name: name, Count: 1}
+		}
+	}
+}
+
+func sorted(terms map[string]*Term) []Term {
+	res := make([]Term, 0, len(terms))
+	for _, t := range terms {
+		res = append(res, *t)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// SuggestTags asks the model for the tags and categories of the post. The terms out of the vocabulary beyond
+// MaxNew are dropped, as are the terms the post already has.
+func SuggestTags(ctx context.Context, args SuggestTagsArgs) (*Suggestion, error) {
+	if args.LLMTools == nil {
+		return nil, errors.New("LLMTools is required for suggest tags")
+	}
+	if args.MaxNew < 0 {
+		return nil, fmt.Errorf("invalid max new terms %d", args.MaxNew)
+	}
+
+	doc, err := frontmatter.Parse(args.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := prompts.PostVars(args.Content, "")
+	vars["known_tags"] = names(args.Vocabulary.Tags)
+	vars["known_categories"] = names(args.Vocabulary.Categories)
+	vars["max_new"] = args.MaxNew
+	sysPrompt, err := args.Prompts.Render(prompts.SuggestTags, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	input := doc.Body
+	if title := doc.GetString("title"); title != "" {
+		input = "# " + title + "\n\n" + input
+	}
+
+	var a answer
+	if _, err = llms.TextJSON(llms.WithTask(ctx, llms.TaskSuggest), args.LLMTools, llms.JSONArgs{
+		SysPrompt: sysPrompt,
+		Input:     input,
+		Name:      "post_taxonomy",
+	}, &a); err != nil {
+		return nil, err
+	}
+
+	budget := args.MaxNew
+	return &Suggestion{
+		Tags:       choose(a.Tags, args.Vocabulary.Tags, doc.GetStrings("tags"), &budget),
+		Categories: choose(a.Categories, args.Vocabulary.Categories, doc.GetStrings("categories"), &budget),
+	}, nil
+}
+
+// choose matches the suggested names to the vocabulary, the new names take from budget until it runs out.
+func choose(suggested []string, vocabulary []Term, existing []string, budget *int) []Choice {
+	known := map[string]string{}
+	for _, t := range vocabulary {
+		known[strings.ToLower(t.Name)] = t.Name
+	}
+	seen := map[string]bool{}
+	for _, name := range existing {
+		seen[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	var res []Choice
+	for _, name := range suggested {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if v, ok := known[key]; ok {
+			res = append(res, Choice{Name: v})
+			continue
+		}
+		if *budget > 0 {
+			*budget--
+			res = append(res, Choice{Name: name, New: true})
+		}
+	}
+
+	return res
+}
+
+func names(terms []Term) string {
+	res := make([]string, 0, len(terms))
+	for _, t := range terms {
+		res = append(res, t.Name)
+	}
+	return strings.Join(res, ", ")
+}
+
+// Apply appends tags and categories to the front matter of doc, the names it already has are skipped. It returns
+// false if nothing changed.
+func Apply(doc *frontmatter.Document, tags, categories []string) (bool, error) {
+	changed := false
+	for _, kv := range []struct {
+		key   string
+		names []string
+	}{{"tags", tags}, {"categories", categories}} {
+		values := doc.GetStrings(kv.key)
+		seen := map[string]bool{}
+		for _, v := range values {
+			seen[strings.ToLower(v)] = true
+		}
+
+		n := len(values)
+		for _, name := range kv.names {
+			if !seen[strings.ToLower(name)] {
+				seen[strings.ToLower(name)] = true
+				values = append(values, name)
+			}
+		}
+		if len(values) == n {
+			continue
+		}
+
+		if err := doc.Set(kv.key, values); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+
+	return changed, nil
+}
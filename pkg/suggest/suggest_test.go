@@ -0,0 +1,109 @@
+package suggest
+
+import (
+	"context"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/llms"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeLLM answers every text call with answer and keeps the system prompt.
+type fakeLLM struct {
+	answer    string
+	sysPrompt string
+}
+
+func (f *fakeLLM) Text(ctx context.Context, sysPrompt, input string) (string, llms.Usage, error) {
+	f.sysPrompt = sysPrompt
+	return f.answer, llms.Usage{}, nil
+}
+
+func (f *fakeLLM) Pic(ctx context.Context, input string) ([]byte, llms.Usage, error) {
+	return nil, llms.Usage{}, nil
+}
+
+func TestCollectVocabulary(t *testing.T) {
+	root := t.TempDir()
+	posts := map[string]string{
+		"a.md": "+++\ntags = ['Go', 'CLI']\ncategories = 'Read'\n+++\n",
+		"b.md": "---\ntags: [go, hugo, go]\n---\n",
+		"c.md": "no front matter",
+	}
+	var paths []string
+	for name, content := range posts {
+		p := filepath.Join(root, name)
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+
+	v, err := CollectVocabulary(paths)
+	if err != nil {
+		t.Fatalf("CollectVocabulary failed: %v", err)
+	}
+	if len(v.Tags) != 3 || v.Tags[0].Count != 2 || !strings.EqualFold(v.Tags[0].Name, "go") {
+		t.Fatalf("Unexpected tags: %+v", v.Tags)
+	}
+	if len(v.Categories) != 1 || v.Categories[0] != (Term{Name: "Read", Count: 1}) {
+		t.Fatalf("Unexpected categories: %+v", v.Categories)
+	}
+}
+
+func TestSuggestTags(t *testing.T) {
+	fake := &fakeLLM{answer: `{"tags": ["go", "Hugo", "rust", "wasm", "cli"], "categories": ["Read", "Notes"]}`}
+	s, err := SuggestTags(context.Background(), SuggestTagsArgs{
+		LLMTools: fake,
+		Content:  []byte("+++\ntitle = 'post'\ntags = ['CLI']\n+++\nbody"),
+		Vocabulary: Vocabulary{
+			Tags:       []Term{{Name: "Go", Count: 3}, {Name: "Hugo", Count: 1}, {Name: "CLI", Count: 1}},
+			Categories: []Term{{Name: "Read", Count: 2}},
+		},
+		MaxNew: 1,
+	})
+	if err != nil {
+		t.Fatalf("SuggestTags failed: %v", err)
+	}
+
+	want := []Choice{{Name: "Go"}, {Name: "Hugo"}, {Name: "rust", New: true}}
+	if len(s.Tags) != len(want) {
+		t.Fatalf("Unexpected tags: %+v", s.Tags)
+	}
+	for i := range want {
+		if s.Tags[i] != want[i] {
+			t.Fatalf("Unexpected tags: %+v", s.Tags)
+		}
+	}
+	if len(s.Categories) != 1 || s.Categories[0] != (Choice{Name: "Read"}) {
+		t.Fatalf("Expected the new category out of the budget: %+v", s.Categories)
+	}
+
+	if !strings.Contains(fake.sysPrompt, "Go, Hugo, CLI") || !strings.Contains(fake.sysPrompt, "最多新建 1 个") {
+		t.Fatalf("Expected the vocabulary in the prompt: %s", fake.sysPrompt)
+	}
+}
+
+func TestApply(t *testing.T) {
+	doc, err := frontmatter.Parse([]byte("+++\ntitle = 'post'\ntags = ['go']\n+++\nbody"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := Apply(doc, []string{"Go", "hugo"}, []string{"Read"})
+	if err != nil || !changed {
+		t.Fatalf("Apply failed: %v, %v", changed, err)
+	}
+	if got := strings.Join(doc.GetStrings("tags"), ","); got != "go,hugo" {
+		t.Fatalf("Unexpected tags: %s", got)
+	}
+	if got := strings.Join(doc.GetStrings("categories"), ","); got != "Read" {
+		t.Fatalf("Unexpected categories: %s", got)
+	}
+
+	if changed, _ = Apply(doc, []string{"GO"}, nil); changed {
+		t.Fatal("Expected no change for the existing tags")
+	}
+}
@@ -0,0 +1,47 @@
+package suggest
+
+import (
+	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/prompts"
+)
+
+// Term is a tag or a category with the number of posts using it.
+type Term struct {
+	Name  string
+	Count int
+}
+
+// Vocabulary is the taxonomy of the site, the terms are sorted by count then name.
+type Vocabulary struct {
+	Tags       []Term
+	Categories []Term
+}
+
+// Choice is a suggested term, New is true if the vocabulary doesn't have it.
+type Choice struct {
+	Name string
+	New  bool
+}
+
+// Suggestion is the terms suggested for a post, without the terms the post already has.
+type Suggestion struct {
+	Tags       []Choice
+	Categories []Choice
+}
+
+type SuggestTagsArgs struct {
+	LLMTools llms.LLMTools
+	// Prompts resolves the prompt names, nil means the builtin prompts.
+	Prompts *prompts.Registry
+	// Content is the post, front matter included.
+	Content    []byte
+	Vocabulary Vocabulary
+	// MaxNew is how many terms out of the vocabulary are allowed, tags and categories together.
+	MaxNew int
+}
+
+// answer is the JSON answer of the model.
+type answer struct {
+	Tags       []string `json:"tags" describe:"The tags of the post"`
+	Categories []string `json:"categories" describe:"The categories of the post"`
+}
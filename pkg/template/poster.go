@@ -0,0 +1,88 @@
+package template
+
+import (
+	"fmt"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"strings"
+)
+
+// Empty reports whether no key is declared.
+func (p PosterFrontMatter) Empty() bool {
+	return len(p.Image) == 0 && len(p.Alt) == 0 && len(p.Images) == 0
+}
+
+// Apply sets the poster keys on doc. An empty alt text is set to the title of the post, so it is in the language of
+// the post, an alt written by the author is kept.
+func (p PosterFrontMatter) Apply(doc *frontmatter.Document, poster string) error {
+	for _, key := range p.Image {
+		if err := doc.Set(key, poster); err != nil {
+			return err
+		}
+	}
+
+	alt := strings.Join(strings.Fields(doc.GetString("title")), " ")
+	for _, key := range p.Alt {
+		if strings.TrimSpace(doc.GetString(key)) != "" || alt == "" {
+			continue
+		}
+		if err := doc.Set(key, alt); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range p.Images {
+		images := doc.GetStrings(key)
+		if containsString(images, poster) {
+			continue
+		}
+		if err := doc.Set(key, append(images, poster)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdatePosterFrontMatter sets the poster keys of the template on the existing files of the post, of the template
+// languages and of langs, the rest of the files is kept as is.
+func (t Template) UpdatePosterFrontMatter(fileName string, langs ...string) ([]string, error) {
+	if t.PosterFrontMatter.Empty() {
+		return nil, nil
+	}
+
+	var paths []string
+	for _, p := range t.LangFiles(fileName, langs...) {
+		if FileExists(p) {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("post %s not found in %s", fileName, t.Dir)
+	}
+
+	var updated []string
+	for _, p := range paths {
+		doc, err := frontmatter.ReadFile(p)
+		if err != nil {
+			return updated, err
+		}
+		if err = t.PosterFrontMatter.Apply(doc, t.PosterFileName()); err != nil {
+			return updated, err
+		}
+		if err = doc.WriteFile(p); err != nil {
+			return updated, err
+		}
+		updated = append(updated, p)
+	}
+
+	return updated, nil
+}
+
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
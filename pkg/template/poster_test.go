@@ -0,0 +1,80 @@
+package template
+
+import (
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/imaging"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPosterFrontMatterApply(t *testing.T) {
+	p := PosterFrontMatter{Image: []string{"cover.image"}, Alt: []string{"cover.alt"}, Images: []string{"images"}}
+
+	doc, err := frontmatter.Parse([]byte("+++\ntitle = 'Hello'\nimages = ['a.png']\n\n[cover]\nhidden = true\n+++\nbody\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if err = p.Apply(doc, "feature.png"); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	// applying twice doesn't duplicate the image
+	if err = p.Apply(doc, "feature.png"); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if doc.GetString("cover.image") != "feature.png" || doc.GetString("cover.alt") != "Hello" {
+		t.Fatalf("Unexpected cover: %s", doc.Bytes())
+	}
+	if images := doc.GetStrings("images"); strings.Join(images, ",") != "a.png,feature.png" {
+		t.Fatalf("Unexpected images: %v", images)
+	}
+	if hidden, _ := doc.GetBool("cover.hidden"); !hidden || !strings.HasSuffix(string(doc.Bytes()), "+++\nbody\n") {
+		t.Fatalf("Expected the rest of the post to be kept: %s", doc.Bytes())
+	}
+
+	doc, _ = frontmatter.Parse([]byte("---\ntitle: Hello\ncover:\n  alt: a sunny beach\n---\n"))
+	if err = p.Apply(doc, "feature.webp"); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if doc.GetString("cover.alt") != "a sunny beach" || doc.GetString("cover.image") != "feature.webp" {
+		t.Fatalf("Expected the alt of the author to be kept: %s", doc.Bytes())
+	}
+}
+
+func TestUpdatePosterFrontMatter(t *testing.T) {
+	tp := Template{
+		Dir:               "test_poster_output",
+		NeedDir:           true,
+		Poster:            imaging.Config{Format: imaging.FormatWebP},
+		PosterFrontMatter: PosterFrontMatter{Images: []string{"images"}},
+		Languages:         []LanguageTemplate{{Lang: "en"}, {Lang: "ja"}},
+	}
+	defer os.RemoveAll("test_poster_output")
+
+	if updated, err := tp.UpdatePosterFrontMatter("post"); err == nil {
+		t.Fatalf("Expected a missing post to fail, updated: %v", updated)
+	}
+
+	// a post may only exist in languages, of the template or of the site.
+	_ = tp.WriteLangIfNotExists("post", "en", []byte("+++\ntitle = 'b'\n+++\n"))
+	_ = tp.WriteLangIfNotExists("post", "fr", []byte("+++\ntitle = 'c'\n+++\n"))
+
+	updated, err := tp.UpdatePosterFrontMatter("post", "fr")
+	if err != nil {
+		t.Fatalf("UpdatePosterFrontMatter failed: %v", err)
+	}
+	if len(updated) != 2 {
+		t.Fatalf("Expected the en and fr files to be updated, got %v", updated)
+	}
+
+	doc, _ := frontmatter.ReadFile(tp.GetLangFilePath("post", "en"))
+	if images := doc.GetStrings("images"); len(images) != 1 || images[0] != "feature.webp" {
+		t.Fatalf("Unexpected images: %v", images)
+	}
+
+	if updated, _ = (Template{}).UpdatePosterFrontMatter("post", ""); updated != nil {
+		t.Fatal("Expected no update without poster keys")
+	}
+}
@@ -0,0 +1,69 @@
+package template
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// PostFiles returns the paths of the Markdown files under the dir of the template, language files included, in
+// lexical order. A missing dir has no posts.
+func (t Template) PostFiles() ([]string, error) {
+	if t.Dir == "" {
+		return nil, nil
+	}
+
+	var res []string
+	err := filepath.WalkDir(t.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == t.Dir && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".md") {
+			res = append(res, p)
+		}
+		return nil
+	})
+
+	return res, err
+}
+
+// PostExists reports whether the name of the post fileName is taken: by the post, by a language file of it, or by
+// its dir for the templates which need a dir.
+func (t Template) PostExists(fileName string) bool {
+	fileName = strings.TrimSuffix(fileName, ".md")
+	if t.NeedDir {
+		return FileExists(filepath.Join(t.Dir, fileName))
+	}
+
+	if FileExists(t.GetFilePath(fileName)) {
+		return true
+	}
+	matches, _ := filepath.Glob(filepath.Join(t.Dir, fileName+".*.md"))
+	return len(matches) != 0
+}
+
+// LangFiles returns the post fileName and its existing language files, of the languages of the template and of
+// langs, the languages of the site.
+func (t Template) LangFiles(fileName string, langs ...string) []string {
+	res := []string{t.GetFilePath(fileName)}
+	seen := map[string]bool{res[0]: true}
+
+	codes := append([]string{}, langs...)
+	for _, l := range t.Languages {
+		codes = append(codes, l.Lang)
+	}
+	for _, lang := range codes {
+		p := t.GetLangFilePath(fileName, lang)
+		if lang != "" && !seen[p] && FileExists(p) {
+			seen[p] = true
+			res = append(res, p)
+		}
+	}
+
+	return res
+}
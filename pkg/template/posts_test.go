@@ -0,0 +1,83 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPostFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.md", "b/index.md", "b/index.en.md", "b/feature.png", "c/d/e.MD"} {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := Template{Dir: root}.PostFiles()
+	if err != nil {
+		t.Fatalf("PostFiles failed: %v", err)
+	}
+	for i, f := range files {
+		files[i], _ = filepath.Rel(root, f)
+	}
+	if got := strings.Join(files, ","); got != "a.md,b/index.en.md,b/index.md,c/d/e.MD" {
+		t.Fatalf("Unexpected post files: %s", got)
+	}
+
+	if files, err = (Template{Dir: filepath.Join(root, "missing")}).PostFiles(); err != nil || files != nil {
+		t.Fatalf("Expected no posts of a missing dir: %v, %v", files, err)
+	}
+}
+
+func TestPostExists(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.md", "b.en.md", "c/index.md"} {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	single := Template{Dir: root}
+	for name, want := range map[string]bool{"a": true, "a.md": true, "b": true, "c": false, "d": false} {
+		if single.PostExists(name) != want {
+			t.Fatalf("Expected PostExists(%s) of single files to be %v", name, want)
+		}
+	}
+
+	bundle := Template{Dir: root, NeedDir: true}
+	if !bundle.PostExists("c") || bundle.PostExists("d") {
+		t.Fatal("Unexpected PostExists of bundles")
+	}
+}
+
+func TestLangFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a/index.md", "a/index.en.md", "a/index.fr.md", "a/index.de.md"} {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tmpl := Template{Dir: root, NeedDir: true, Languages: []LanguageTemplate{{Lang: "en"}, {Lang: "ja"}}}
+	var got []string
+	for _, p := range tmpl.LangFiles("a", "en", "fr") {
+		got = append(got, strings.TrimPrefix(p, root+"/"))
+	}
+	if strings.Join(got, ",") != "a/index.md,a/index.en.md,a/index.fr.md" {
+		t.Fatalf("Unexpected files: %v", got)
+	}
+}
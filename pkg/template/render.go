@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"gopkg.in/yaml.v3"
 	"strings"
 	"text/template"
 	"time"
@@ -15,6 +17,8 @@ type RenderOption struct {
 	AppendTags       []string
 	AppendCategories []string
 	CustomArgs       map[string]string
+	// Lang selects the language overrides of the template, empty means the default template.
+	Lang string
 }
 
 // RenderTemplate renders a template with the given data
@@ -24,8 +28,15 @@ func RenderTemplate(ctx context.Context, tmpl *Template, option RenderOption) (s
 		return "", errors.New("template is nil")
 	}
 
+	body := tmpl.Template
+	lt, hasLang := tmpl.Language(option.Lang)
+	if hasLang && lt.Template != "" {
+		body = lt.Template
+	}
+
 	vars := map[string]string{
 		"title":      option.Title,
+		"lang":       option.Lang,
 		"createAt":   time.Now().Format(time.RFC3339),
 		"tags":       fmt.Sprintf("[%s]", formatStringArray(append(tmpl.Tags, option.AppendTags...)...)),
 		"categories": fmt.Sprintf("[%s]", formatStringArray(append(tmpl.Categories, option.AppendCategories...)...)),
@@ -36,7 +47,7 @@ func RenderTemplate(ctx context.Context, tmpl *Template, option RenderOption) (s
 	}
 
 	// Create Go template
-	t, err := template.New(tmpl.Name).Parse(tmpl.Template)
+	t, err := template.New(tmpl.Name).Funcs(renderFuncs).Parse(body)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
@@ -47,7 +58,125 @@ func RenderTemplate(ctx context.Context, tmpl *Template, option RenderOption) (s
 		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	return buf.String(), nil
+	if !hasLang || len(lt.FrontMatter) == 0 {
+		return buf.String(), nil
+	}
+
+	doc, err := frontmatter.Parse(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("failed to parse rendered front matter: %w", err)
+	}
+	if err = lt.ApplyFrontMatter(doc); err != nil {
+		return "", err
+	}
+
+	return string(doc.Bytes()), nil
+}
+
+// ApplyFrontMatter sets the language front matter values on doc. A value is a YAML flow scalar or list, 'false',
+// '10' or '[a, b]', anything else is a string. The values of a key which is a list already, as the rendered tags
+// and categories, are appended to it.
+func (lt LanguageTemplate) ApplyFrontMatter(doc *frontmatter.Document) error {
+	for _, item := range lt.FrontMatter {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok || k == "" {
+			return fmt.Errorf("wrong front matter format of language %s, need k=v: %s", lt.Lang, item)
+		}
+
+		value := parseValue(v)
+		if current, ok := doc.Get(k); ok {
+			if items, ok := current.([]interface{}); ok {
+				value = mergeItems(items, value)
+			}
+		}
+		if err := doc.Set(k, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseValue decodes v as a YAML bool, number or list of scalars, other values are kept as the string v.
+func parseValue(v string) interface{} {
+	var decoded interface{}
+	if err := yaml.Unmarshal([]byte(v), &decoded); err != nil {
+		return v
+	}
+
+	switch value := decoded.(type) {
+	case bool, int, float64:
+		return value
+	case string:
+		// a quoted string is unquoted, the plain ones are kept as written.
+		if trimmed := strings.TrimSpace(v); len(trimmed) >= 2 && strings.ContainsAny(trimmed[:1], `"'`) {
+			return value
+		}
+	case []interface{}:
+		for _, item := range value {
+			switch item.(type) {
+			case string, bool, int, float64:
+			default:
+				return v
+			}
+		}
+		return value
+	}
+
+	return v
+}
+
+// mergeItems appends the items of value missing in items, value is a list or a scalar.
+func mergeItems(items []interface{}, value interface{}) []interface{} {
+	added, ok := value.([]interface{})
+	if !ok {
+		added = []interface{}{value}
+	}
+
+	res := append([]interface{}{}, items...)
+	for _, a := range added {
+		found := false
+		for _, item := range res {
+			if fmt.Sprint(item) == fmt.Sprint(a) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, a)
+		}
+	}
+
+	return res
+}
+
+// renderFuncs are the Hugo template functions available in templates, so imported archetypes keep working.
+var renderFuncs = template.FuncMap{
+	"replace": func(input, old, new string) string {
+		return strings.ReplaceAll(input, old, new)
+	},
+	"title": titleCase,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"humanize": func(input string) string {
+		res := strings.Join(strings.FieldsFunc(input, func(r rune) bool { return r == '-' || r == '_' }), " ")
+		if res == "" {
+			return res
+		}
+		return strings.ToUpper(res[:1]) + res[1:]
+	},
+}
+
+// titleCase upper cases the first letter of every word.
+func titleCase(input string) string {
+	words := strings.Split(input, " ")
+	for i, w := range words {
+		if w != "" {
+			r := []rune(w)
+			words[i] = strings.ToUpper(string(r[0])) + string(r[1:])
+		}
+	}
+	return strings.Join(words, " ")
 }
 
 func formatStringArray(inputs ...string) string {
@@ -72,5 +201,5 @@ func RenderToFile(ctx context.Context, tmpl *Template, fileName string, data Ren
 		return fmt.Errorf("failed to render template: %w", err)
 	}
 
-	return tmpl.WriteIfNotExists(fileName, []byte(content))
+	return tmpl.WriteLangIfNotExists(fileName, data.Lang, []byte(content))
 }
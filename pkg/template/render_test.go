@@ -1,72 +1,135 @@
 package template
 
 import (
+	"context"
+	"github.io/uberate/hcli/pkg/frontmatter"
 	"os"
-	"path/filepath"
+	"strings"
 	"testing"
 )
 
 func TestRenderTemplate(t *testing.T) {
 	tmpl := &Template{
 		Name:       "test",
-		Template:   "Hello, {{.Data.Name}}!",
+		Template:   "Hello, {{.title}}! {{.tags}} {{.who}}",
 		Categories: []string{"test"},
 		Tags:       []string{"demo"},
 		Dir:        "test_output",
 	}
 
-	data := map[string]interface{}{
-		"Name": "World",
+	// Test rendering
+	result, err := RenderTemplate(context.Background(), tmpl, RenderOption{
+		Title:      "World",
+		AppendTags: []string{"extra"},
+		CustomArgs: map[string]string{"who": "me"},
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate failed: %v", err)
 	}
 
-	// Test rendering
-	result, err := RenderTemplate(tmpl, data)
+	expected := `Hello, World! ["demo", "extra"] me`
+	if result != expected {
+		t.Fatalf("Expected: %s, Got: %s", expected, result)
+	}
+}
+
+func TestRenderTemplateFuncs(t *testing.T) {
+	tmpl := &Template{
+		Name:     "funcs",
+		Template: `{{ replace .title "-" " " | title }}|{{ humanize .title }}|{{ upper .lang }}`,
+	}
+
+	result, err := RenderTemplate(context.Background(), tmpl, RenderOption{Title: "hello-world", Lang: "en"})
 	if err != nil {
 		t.Fatalf("RenderTemplate failed: %v", err)
 	}
 
-	expected := "Hello, World!"
+	expected := "Hello World|Hello world|EN"
 	if result != expected {
 		t.Fatalf("Expected: %s, Got: %s", expected, result)
 	}
 }
 
+func TestRenderTemplateWithLang(t *testing.T) {
+	tmpl := &Template{
+		Name:     "test_lang",
+		Template: "+++\ntitle = '{{.title}}'\n+++\n默认",
+		Languages: []LanguageTemplate{
+			{
+				Lang:        "en",
+				Template:    "+++\ntitle = '{{.title}}'\n+++\nDefault {{.lang}}",
+				FrontMatter: []string{"categories=Read"},
+			},
+		},
+	}
+
+	result, err := RenderTemplate(context.Background(), tmpl, RenderOption{Title: "T", Lang: "en"})
+	if err != nil {
+		t.Fatalf("RenderTemplate failed: %v", err)
+	}
+
+	expected := "+++\ntitle = 'T'\ncategories = \"Read\"\n+++\nDefault en"
+	if result != expected {
+		t.Fatalf("Expected: %q, Got: %q", expected, result)
+	}
+
+	// languages without overrides fall back to the default template
+	result, err = RenderTemplate(context.Background(), tmpl, RenderOption{Title: "T", Lang: "zh"})
+	if err != nil {
+		t.Fatalf("RenderTemplate failed: %v", err)
+	}
+	if !strings.HasSuffix(result, "默认") {
+		t.Fatalf("Expected default template, Got: %q", result)
+	}
+}
+
+func TestApplyFrontMatter(t *testing.T) {
+	doc, err := frontmatter.Parse([]byte("+++\ntitle = 'T'\ncategories = ['Go']\ntags = ['a']\n+++\nbody"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lt := LanguageTemplate{Lang: "en", FrontMatter: []string{
+		"categories=Read", "tags=[a, b]", "draft=false", "weight=10", "version='10'", "summary=a # b: c",
+	}}
+	if err = lt.ApplyFrontMatter(doc); err != nil {
+		t.Fatalf("ApplyFrontMatter failed: %v", err)
+	}
+
+	expected := "+++\ntitle = 'T'\ncategories = [\"Go\", \"Read\"]\ntags = [\"a\", \"b\"]\ndraft = false\nweight = 10\n" +
+		"version = \"10\"\nsummary = \"a # b: c\"\n+++\nbody"
+	if got := string(doc.Bytes()); got != expected {
+		t.Fatalf("Expected: %q, Got: %q", expected, got)
+	}
+	if categories := doc.GetStrings("categories"); len(categories) != 2 {
+		t.Fatalf("Expected categories to stay a list: %v", categories)
+	}
+}
+
 func TestRenderToFile(t *testing.T) {
 	tmpl := &Template{
 		Name:       "test_file",
-		Template:   "Title: {{.Data.Title}}\nContent: {{.Data.Content}}",
+		Template:   "Title: {{.title}}\nContent: {{.content}}",
 		Categories: []string{"test"},
 		Tags:       []string{"file"},
 		Dir:        "test_output",
 		NeedDir:    false,
 	}
 
-	data := map[string]interface{}{
-		"Title":   "Test Title",
-		"Content": "This is test content",
-	}
-
 	// Clean up
 	defer os.RemoveAll("test_output")
 
 	// Test render to file
-	err := RenderToFile(tmpl, data)
+	err := RenderToFile(context.Background(), tmpl, "post", RenderOption{
+		Title:      "Test Title",
+		CustomArgs: map[string]string{"content": "This is test content"},
+	})
 	if err != nil {
 		t.Fatalf("RenderToFile failed: %v", err)
 	}
 
-	// Check that a file was created with timestamp in filename
-	files, err := filepath.Glob("test_output/test_file_*.md")
-	if err != nil {
-		t.Fatalf("Failed to list files: %v", err)
-	}
-
-	if len(files) == 0 {
-		t.Fatal("No files were created in the output directory")
-	}
-
 	// Verify the file content
-	content, err := os.ReadFile(files[0])
+	content, err := os.ReadFile("test_output/post.md")
 	if err != nil {
 		t.Fatalf("Failed to read created file: %v", err)
 	}
@@ -75,45 +138,64 @@ func TestRenderToFile(t *testing.T) {
 	if string(content) != expectedContent {
 		t.Fatalf("File content mismatch. Expected: %s, Got: %s", expectedContent, string(content))
 	}
+
+	// The same file can't be rendered twice
+	if err = RenderToFile(context.Background(), tmpl, "post", RenderOption{}); err == nil {
+		t.Fatal("RenderToFile should fail when the file exists")
+	}
 }
 
 func TestRenderToFileWithNeedDir(t *testing.T) {
 	tmpl := &Template{
 		Name:       "test_need_dir",
-		Template:   "Data: {{.Data.Value}}",
+		Template:   "Data: {{.value}}",
 		Categories: []string{"test"},
 		Tags:       []string{"dir"},
 		Dir:        "test_output_need_dir",
 		NeedDir:    true,
 	}
 
-	data := map[string]interface{}{
-		"Value": "test_value",
-	}
-
 	// Clean up
 	defer os.RemoveAll("test_output_need_dir")
 
 	// Test render to file with NeedDir=true
-	err := RenderToFile(tmpl, data)
+	err := RenderToFile(context.Background(), tmpl, "post", RenderOption{
+		CustomArgs: map[string]string{"value": "test_value"},
+		Lang:       "zh",
+	})
 	if err != nil {
 		t.Fatalf("RenderToFile with NeedDir failed: %v", err)
 	}
 
-	// Should create index.md in the directory
-	expectedPath := "test_output_need_dir/index.md"
+	// Should create index.zh.md in the directory
+	expectedPath := "test_output_need_dir/post/index.zh.md"
 	if !FileExists(expectedPath) {
-		t.Fatalf("index.md was not created: %s", expectedPath)
+		t.Fatalf("index.zh.md was not created: %s", expectedPath)
 	}
 
 	// Verify the file content
 	content, err := os.ReadFile(expectedPath)
 	if err != nil {
-		t.Fatalf("Failed to read index.md: %v", err)
+		t.Fatalf("Failed to read index.zh.md: %v", err)
 	}
 
 	expectedContent := "Data: test_value"
 	if string(content) != expectedContent {
 		t.Fatalf("File content mismatch. Expected: %s, Got: %s", expectedContent, string(content))
 	}
-}
\ No newline at end of file
+}
+
+func TestGetLangFilePath(t *testing.T) {
+	tmpl := Template{Dir: "content/posts"}
+	if p := tmpl.GetLangFilePath("hello.md", "en"); p != "content/posts/hello.en.md" {
+		t.Fatalf("Unexpected path: %s", p)
+	}
+
+	tmpl.NeedDir = true
+	if p := tmpl.GetLangFilePath("hello", "en"); p != "content/posts/hello/index.en.md" {
+		t.Fatalf("Unexpected path: %s", p)
+	}
+	if p := tmpl.GetFilePath("hello"); p != "content/posts/hello/index.md" {
+		t.Fatalf("Unexpected path: %s", p)
+	}
+}
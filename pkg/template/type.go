@@ -2,6 +2,10 @@ package template
 
 import (
 	"fmt"
+	"github.io/uberate/hcli/pkg/cover"
+	"github.io/uberate/hcli/pkg/imaging"
+	"github.io/uberate/hcli/pkg/lint"
+	"github.io/uberate/hcli/pkg/overlay"
 	"os"
 	"path"
 	"path/filepath"
@@ -9,40 +13,91 @@ import (
 )
 
 type Template struct {
-	Name string `yaml:"Name" describe:"Template name, use hcli gen posts --template-name(|-n)=Name to generate a new 
-posts by template value to init."`
+	Name string `yaml:"Name" describe:"Template name, use hcli gen posts --template-name(|-n)=Name to generate a new \nposts by template value to init."`
 
 	Categories []string `yaml:"Categories" describe:"Categories of posts, can be append by '--categories' args."`
 	Tags       []string `yaml:"Tags" describe:"Tags of posts, can be append by '--tag|-t'"`
 
 	Template string `yaml:"Template" describe:"The go template of posts."`
 
-	Dir     string `yaml:"Dir" describe:"The directory of posts, absolute of command run path."`
-	NeedDir bool   `yaml:"NeedDir" describe:"Whether to need directory, if need, hcli will create posts in a new dir
-named args and set file to index.md" default:"false"`
+	Dir             string `yaml:"Dir" describe:"The directory of posts, absolute of command run path."`
+	ContentRelative bool   `yaml:"ContentRelative" describe:"Whether Dir is relative to the content dir of the Hugo site." default:"false"`
+	NeedDir         bool   `yaml:"NeedDir" describe:"Whether to need directory, if need, hcli will create posts in a new dir\nnamed args and set file to index.md" default:"false"`
 
-	PicSummaryPrompt string `yaml:"PicSummaryPrompt" describe:"Pic summary prompt"`
+	PicSummaryPrompt     string `yaml:"PicSummaryPrompt" describe:"Pic summary prompt, the literal prompt wins over PicSummaryPromptName."`
+	PicSummaryPromptName string `yaml:"PicSummaryPromptName" describe:"The registered prompt name of the pic summary, see 'hcli prompts list'." default:"poster.summary"`
+
+	Poster  imaging.Config `yaml:"Poster" describe:"The poster post-processing: size, crop, format and thumbnails."`
+	Overlay overlay.Config `yaml:"Overlay" describe:"The title, date and logo drawn on the poster, rendered locally."`
+	Cover   cover.Config   `yaml:"Cover" describe:"The procedural background of 'hcli gen pic --renderer local'."`
+
+	PosterFrontMatter PosterFrontMatter `yaml:"PosterFrontMatter" describe:"The front matter keys set on the post after its poster is written."`
+
+	Languages []LanguageTemplate `yaml:"Languages" describe:"Per language bodies and front matter, used by 'hcli gen posts --lang'\nand 'hcli translate --to'."`
+
+	Lint lint.Config `yaml:"Lint" describe:"The rules of 'hcli lint' for the posts of the template."`
+}
+
+// PosterFrontMatter declares the front matter keys which reference the poster of a post.
+type PosterFrontMatter struct {
+	Image  []string `yaml:"Image" describe:"The keys set to the poster file name, e.g. cover.image."`
+	Alt    []string `yaml:"Alt" describe:"The keys set to the poster alt text, the post title unless the key has a value, e.g. cover.alt."`
+	Images []string `yaml:"Images" describe:"The list keys the poster file name is appended to, e.g. images."`
+}
+
+// LanguageTemplate overrides a template for one language of a multilingual site.
+type LanguageTemplate struct {
+	Lang string `yaml:"Lang" describe:"The language code, posts are written to 'index.<Lang>.md' or '<name>.<Lang>.md'."`
+
+	Template string `yaml:"Template" describe:"The go template of posts in this language, empty means use the default\ntemplate."`
+
+	FrontMatter []string `yaml:"FrontMatter" describe:"Front matter values of this language in k=v format, set on the posts\ngenerated or translated to this language. v is a YAML scalar or flow list, appended to the keys which are lists."`
+}
+
+// Language returns the overrides of lang, the second result is false if the template does not define lang.
+func (t Template) Language(lang string) (LanguageTemplate, bool) {
+	for _, l := range t.Languages {
+		if l.Lang == lang {
+			return l, true
+		}
+	}
+	return LanguageTemplate{}, false
 }
 
 func (t Template) GetFilePath(fileName string) string {
+	return t.GetLangFilePath(fileName, "")
+}
+
+// GetLangFilePath returns the post path of lang following the Hugo multilingual naming, 'index.<lang>.md' for
+// bundles and '<name>.<lang>.md' for single files. An empty lang returns the path without language.
+func (t Template) GetLangFilePath(fileName, lang string) string {
 
 	if strings.HasSuffix(fileName, ".md") {
 		fileName = strings.TrimSuffix(fileName, ".md")
 	}
 
+	suffix := ".md"
+	if lang != "" {
+		suffix = fmt.Sprintf(".%s.md", lang)
+	}
+
 	res := ""
 	if t.NeedDir {
-		res = path.Join(fileName, "index.md")
+		res = path.Join(fileName, "index"+suffix)
 	} else {
-		res = fmt.Sprintf("%s.md", fileName)
+		res = fileName + suffix
 	}
 
 	return filepath.Join(t.Dir, res)
 }
 
+// SummaryPath returns the path of the pic summary text of the post, in the dir of the post.
+func (t Template) SummaryPath(fileName string) string {
+	return path.Join(path.Dir(t.GetFilePath(fileName)), fmt.Sprintf("%s.summary.text", fileName))
+}
+
 func (t Template) WritePicSummary(fileName string, summary string) error {
-	dir := path.Dir(t.GetFilePath(fileName))
-	summaryFileName := path.Join(dir, fmt.Sprintf("%s.summary.text", fileName))
+	summaryFileName := t.SummaryPath(fileName)
 	if FileExists(summaryFileName) {
 		return fmt.Errorf("file %s already exists", summaryFileName)
 	}
@@ -50,18 +105,51 @@ func (t Template) WritePicSummary(fileName string, summary string) error {
 	return SafeWriteFile(summaryFileName, []byte(summary))
 }
 
+// PosterFileName returns the poster file name in the post dir, the extension follows the poster format.
+func (t Template) PosterFileName() string {
+	return "feature" + t.Poster.Ext()
+}
+
+// ThumbnailFileName returns the file name of the poster thumbnail with width.
+func (t Template) ThumbnailFileName(width int) string {
+	return fmt.Sprintf("thumbnail-%d%s", width, t.Poster.Ext())
+}
+
+// CandidateFileName returns the file name of the poster candidate index, counted from 1.
+func (t Template) CandidateFileName(index int) string {
+	return fmt.Sprintf("feature.candidate-%d%s", index, t.Poster.Ext())
+}
+
+// CandidateSummaryFileName returns the file name of the summary the poster candidate index was generated from.
+func (t Template) CandidateSummaryFileName(index int) string {
+	return fmt.Sprintf("feature.candidate-%d.summary.text", index)
+}
+
 func (t Template) WritePoster(fileName string, picData []byte) error {
-	dir := path.Dir(t.GetFilePath(fileName))
-	summaryFileName := path.Join(dir, "feature.png")
-	if FileExists(summaryFileName) {
-		return fmt.Errorf("file %s already exists", summaryFileName)
+	return t.WriteResource(fileName, t.PosterFileName(), picData)
+}
+
+// ResourcePath returns the path of the file named name in the dir of the post.
+func (t Template) ResourcePath(fileName string, name string) string {
+	return path.Join(path.Dir(t.GetFilePath(fileName)), name)
+}
+
+// WriteResource writes a file named name in the dir of the post, an existing file is not overwritten.
+func (t Template) WriteResource(fileName string, name string, data []byte) error {
+	resourceFileName := t.ResourcePath(fileName, name)
+	if FileExists(resourceFileName) {
+		return fmt.Errorf("file %s already exists", resourceFileName)
 	}
 
-	return SafeWriteFile(summaryFileName, picData)
+	return SafeWriteFile(resourceFileName, data)
 }
 
 func (t Template) WriteIfNotExists(fileName string, data []byte) error {
-	outputPath := t.GetFilePath(fileName)
+	return t.WriteLangIfNotExists(fileName, "", data)
+}
+
+func (t Template) WriteLangIfNotExists(fileName, lang string, data []byte) error {
+	outputPath := t.GetLangFilePath(fileName, lang)
 
 	// Check if file already exists and create a new name with timestamp
 	if FileExists(outputPath) {
@@ -82,7 +170,11 @@ func (t Template) WriteIfNotExists(fileName string, data []byte) error {
 }
 
 func (t Template) ReadIfExists(fileName string) ([]byte, error) {
-	outputPath := t.GetFilePath(fileName)
+	return t.ReadLangIfExists(fileName, "")
+}
+
+func (t Template) ReadLangIfExists(fileName, lang string) ([]byte, error) {
+	outputPath := t.GetLangFilePath(fileName, lang)
 	if !FileExists(outputPath) {
 		return nil, fmt.Errorf("file does not exist: %s", outputPath)
 	}
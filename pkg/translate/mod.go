@@ -0,0 +1,9 @@
+// Package translate translates Hugo posts into sibling language files with the LLM tools.
+//
+// The post body is sent through llms.LLMTools.Text with a prompt that keeps the Markdown structure. Fenced code
+// blocks and Hugo shortcode tags are replaced by placeholders before the request and restored afterwards, so they
+// reach the translated post byte for byte.
+//
+// Both the source and the translated post get the same 'translationKey' front matter key, which Hugo uses to link
+// the translations of a page.
+package translate
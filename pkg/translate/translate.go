@@ -0,0 +1,231 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/hctx"
+	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/prompts"
+	"github.io/uberate/hcli/pkg/template"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const TranslationKey = "translationKey"
+
+type TranslatePostArgs struct {
+	TP       *template.Template
+	LLMTools llms.LLMTools
+	FileName string
+	// From is the language of the source post, empty means the post without language suffix.
+	From string
+	To   string
+	// Prompts resolves the prompt names, nil means the builtin prompts.
+	Prompts *prompts.Registry
+	// OnChunk receives the translated body as it arrives, nil means no progressive output.
+	OnChunk func(chunk string)
+}
+
+type TranslatePostResult struct {
+	// Source is the source post with the translation key added.
+	Source []byte
+	// Target is the translated post.
+	Target []byte
+}
+
+func TranslatePost(ctx context.Context, args TranslatePostArgs) (*TranslatePostResult, error) {
+	if args.TP == nil {
+		return nil, errors.New("template is required for translate post")
+	}
+
+	if args.LLMTools == nil {
+		return nil, errors.New("LLMTools is required for translate post")
+	}
+
+	if args.To == "" || args.To == args.From {
+		return nil, fmt.Errorf("invalid target language '%s'", args.To)
+	}
+
+	fileContent, err := args.TP.ReadLangIfExists(args.FileName, args.From)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := frontmatter.Parse(fileContent)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := frontmatter.Parse(fileContent)
+	if err != nil {
+		return nil, err
+	}
+
+	key := source.GetString(TranslationKey)
+	if key == "" {
+		key = path.Base(strings.TrimSuffix(args.FileName, ".md"))
+	}
+
+	if err = source.Set(TranslationKey, key); err != nil {
+		return nil, err
+	}
+	if err = target.Set(TranslationKey, key); err != nil {
+		return nil, err
+	}
+
+	ctx = llms.WithTask(ctx, llms.TaskTranslate)
+	if title := source.GetString("title"); title != "" {
+		titlePrompt, err := args.Prompts.Render(prompts.TranslateTitle, prompts.PostVars(fileContent, args.To))
+		if err != nil {
+			return nil, err
+		}
+		translated, _, err := args.LLMTools.Text(ctx, titlePrompt, title)
+		if err != nil {
+			return nil, err
+		}
+		if err = target.Set("title", strings.TrimSpace(translated)); err != nil {
+			return nil, err
+		}
+	}
+
+	if lt, ok := args.TP.Language(args.To); ok {
+		if err = lt.ApplyFrontMatter(target); err != nil {
+			return nil, err
+		}
+	}
+
+	target.Body, err = TranslateMarkdown(ctx, args.LLMTools, args.Prompts, source.Body, args.To, args.OnChunk)
+	if err != nil {
+		return nil, err
+	}
+	hctx.Println(ctx, "translate the post to %s done", args.To)
+
+	return &TranslatePostResult{
+		Source: source.Bytes(),
+		Target: target.Bytes(),
+	}, nil
+}
+
+// TranslateMarkdown translates a markdown body to the language lang, code blocks and shortcode tags are kept. A
+// non-nil onChunk receives the translation as it arrives, with the protected blocks already restored.
+func TranslateMarkdown(ctx context.Context, tools llms.LLMTools, reg *prompts.Registry, body, lang string,
+	onChunk func(chunk string)) (string, error) {
+
+	if strings.TrimSpace(body) == "" {
+		return body, nil
+	}
+
+	bodyPrompt, err := reg.Render(prompts.TranslateBody, prompts.PostVars([]byte(body), lang))
+	if err != nil {
+		return "", err
+	}
+
+	ctx = llms.WithTask(ctx, llms.TaskTranslate)
+	protected, blocks := protect(body)
+	var stream *restoreStream
+	if onChunk != nil {
+		stream = &restoreStream{blocks: blocks, out: onChunk}
+		onChunk = stream.write
+	}
+
+	res, _, err := llms.TextStream(ctx, tools, bodyPrompt, protected, onChunk)
+	if stream != nil {
+		stream.flush()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return restore(res, blocks)
+}
+
+var protectedPattern = regexp.MustCompile("(?ms)^[ \t]*```.*?^[ \t]*```[ \t]*$|^[ \t]*~~~.*?^[ \t]*~~~[ \t]*$|" +
+	"\\{\\{[<%].*?[%>]\\}\\}")
+
+const placeholderPrefix = "HCLIKEEP"
+
+var placeholderPattern = regexp.MustCompile(placeholderPrefix + "[0-9]{4}")
+
+func placeholder(i int) string {
+	return fmt.Sprintf(placeholderPrefix+"%04d", i)
+}
+
+// protect replaces the code blocks and shortcode tags of body with placeholders.
+func protect(body string) (string, []string) {
+	var blocks []string
+	res := protectedPattern.ReplaceAllStringFunc(body, func(s string) string {
+		blocks = append(blocks, s)
+		return placeholder(len(blocks) - 1)
+	})
+
+	return res, blocks
+}
+
+// restore puts the protected blocks back, it fails if the model dropped any of the placeholders.
+func restore(body string, blocks []string) (string, error) {
+	for i, block := range blocks {
+		p := placeholder(i)
+		if !strings.Contains(body, p) {
+			return "", fmt.Errorf("translation lost the protected block %d: %.40q", i, block)
+		}
+		body = strings.Replace(body, p, block, 1)
+	}
+
+	return body, nil
+}
+
+// restoreStream restores the protected blocks in a stream of chunks. The end of a chunk which may start a
+// placeholder is held back until the next chunk tells.
+type restoreStream struct {
+	blocks  []string
+	out     func(chunk string)
+	pending string
+}
+
+func (r *restoreStream) write(chunk string) {
+	s := placeholderPattern.ReplaceAllStringFunc(r.pending+chunk, func(p string) string {
+		i, _ := strconv.Atoi(strings.TrimPrefix(p, placeholderPrefix))
+		if i < len(r.blocks) {
+			return r.blocks[i]
+		}
+		return p
+	})
+
+	keep := partialPlaceholder(s)
+	r.pending = s[len(s)-keep:]
+	if out := s[:len(s)-keep]; out != "" {
+		r.out(out)
+	}
+}
+
+func (r *restoreStream) flush() {
+	if r.pending != "" {
+		r.out(r.pending)
+		r.pending = ""
+	}
+}
+
+// partialPlaceholder returns the length of the end of s which may be the start of a placeholder.
+func partialPlaceholder(s string) int {
+	for n := min(len(s), len(placeholder(0))-1); n > 0; n-- {
+		tail := s[len(s)-n:]
+		prefix := placeholderPrefix[:min(n, len(placeholderPrefix))]
+		if strings.HasPrefix(tail, prefix) && isDigits(tail[len(prefix):]) {
+			return n
+		}
+	}
+	return 0
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
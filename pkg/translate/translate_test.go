@@ -0,0 +1,123 @@
+package translate
+
+import (
+	"context"
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/llms"
+	"github.io/uberate/hcli/pkg/template"
+	"os"
+	"strings"
+	"testing"
+)
+
+// fakeLLM upper cases the input, placeholders are not changed by it.
+type fakeLLM struct{}
+
+func (fakeLLM) Text(ctx context.Context, sysPrompt, input string) (string, llms.Usage, error) {
+	return strings.ToUpper(input), llms.Usage{}, nil
+}
+
+func (fakeLLM) Pic(ctx context.Context, input string) ([]byte, llms.Usage, error) {
+	return nil, llms.Usage{}, nil
+}
+
+func TestProtectAndRestore(t *testing.T) {
+	body := "text {{< figure src=\"a.png\" >}}\n\n```go\nfmt.Println(1)\n```\nend {{% note %}}"
+
+	protected, blocks := protect(body)
+	if len(blocks) != 3 {
+		t.Fatalf("Expected 3 protected blocks, got %d: %q", len(blocks), blocks)
+	}
+	if strings.Contains(protected, "fmt.Println") || strings.Contains(protected, "figure") {
+		t.Fatalf("Protected body still contains blocks: %q", protected)
+	}
+
+	restored, err := restore(protected, blocks)
+	if err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if restored != body {
+		t.Fatalf("Expected %q, got %q", body, restored)
+	}
+
+	if _, err = restore("nothing", blocks); err == nil {
+		t.Fatal("restore should fail when a placeholder is lost")
+	}
+}
+
+func TestRestoreStream(t *testing.T) {
+	body := "a {{< x >}} b\n```\ncode\n```\nHCLIKEEP"
+	protected, blocks := protect(body)
+
+	for size := 1; size <= len(protected); size++ {
+		var chunks []string
+		r := &restoreStream{blocks: blocks, out: func(chunk string) {
+			chunks = append(chunks, chunk)
+		}}
+		for i := 0; i < len(protected); i += size {
+			r.write(protected[i:min(i+size, len(protected))])
+		}
+		r.flush()
+
+		if res := strings.Join(chunks, ""); res != body {
+			t.Fatalf("Expected %q with chunks of %d, got %q", body, size, res)
+		}
+		for _, chunk := range chunks {
+			if strings.Contains(chunk, "HCLIKEEP0") {
+				t.Fatalf("Unexpected placeholder in chunk %q", chunk)
+			}
+		}
+	}
+}
+
+func TestTranslateMarkdownStream(t *testing.T) {
+	body := "text {{< figure >}} end"
+	var sb strings.Builder
+	res, err := TranslateMarkdown(context.Background(), fakeLLM{}, nil, body, "en", func(chunk string) {
+		sb.WriteString(chunk)
+	})
+	if err != nil {
+		t.Fatalf("TranslateMarkdown failed: %v", err)
+	}
+	if res != "TEXT {{< figure >}} END" || sb.String() != res {
+		t.Fatalf("Expected the streamed text to be the result: %q, %q", res, sb.String())
+	}
+}
+
+func TestTranslatePost(t *testing.T) {
+	tp := &template.Template{
+		Dir:     "test_output",
+		NeedDir: true,
+		Languages: []template.LanguageTemplate{
+			{Lang: "en", FrontMatter: []string{"categories=Read"}},
+		},
+	}
+	defer os.RemoveAll("test_output")
+
+	if err := tp.WriteLangIfNotExists("post", "zh", []byte("+++\ntitle = 'hello'\n+++\nbody `code`\n")); err != nil {
+		t.Fatalf("write source failed: %v", err)
+	}
+
+	res, err := TranslatePost(context.Background(), TranslatePostArgs{
+		TP:       tp,
+		LLMTools: fakeLLM{},
+		FileName: "post",
+		From:     "zh",
+		To:       "en",
+	})
+	if err != nil {
+		t.Fatalf("TranslatePost failed: %v", err)
+	}
+
+	source, _ := frontmatter.Parse(res.Source)
+	target, _ := frontmatter.Parse(res.Target)
+	if source.GetString(TranslationKey) != "post" || target.GetString(TranslationKey) != "post" {
+		t.Fatalf("Expected translation key on both posts:\n%s\n%s", res.Source, res.Target)
+	}
+	if target.GetString("title") != "HELLO" || target.GetString("categories") != "Read" {
+		t.Fatalf("Unexpected target front matter:\n%s", res.Target)
+	}
+	if target.Body != "BODY `CODE`\n" {
+		t.Fatalf("Unexpected target body: %q", target.Body)
+	}
+}
@@ -0,0 +1,194 @@
+package typography
+
+import (
+	"github.io/uberate/hcli/pkg/frontmatter"
+	"github.io/uberate/hcli/pkg/markdown"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	protectedPattern = regexp.MustCompile(`\{\{[<%].*?[>%]\}\}|\{\{.*?\}\}|<[a-zA-Z/!][^>]*>|\]\([^)]*\)|\{#[^}]*\}|` +
+		`(?:[a-zA-Z][a-zA-Z0-9+.-]*://|www\.)[^\s<>()\[\]{}"'` + cjkClass + fullWidthPunctuation + `]+`)
+	urlPattern       = regexp.MustCompile(`^(?:[a-zA-Z][a-zA-Z0-9+.-]*://|www\.)`)
+	referencePattern = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:`)
+
+	ellipsisPattern          = regexp.MustCompile(`([` + cjkClass + `])[ \t]*(?:\.{3,}|。{3,}|…+)`)
+	quotePattern             = regexp.MustCompile(`"([^"]*)"`)
+	punctuationPattern       = regexp.MustCompile(`([` + cjkClass + closingPunctuation + `])[ \t]*([,;:!?])[ \t]*`)
+	periodPattern            = regexp.MustCompile(`([` + cjkClass + closingPunctuation + `])[ \t]*\.(?:[ \t]+|$)`)
+	parenPattern             = regexp.MustCompile(`\(([^()]*)\)`)
+	punctuationBeforePattern = regexp.MustCompile(`([` + cjkClass + closingPunctuation + `][A-Za-z0-9]*)` +
+		`[ \t]*([,;:!?])[ \t]*([` + cjkClass + `])`)
+	spaceBeforePattern = regexp.MustCompile(`([` + cjkClass + `A-Za-z0-9` + fullWidthPunctuation + `])[ \t]+([` +
+		fullWidthPunctuation + `])`)
+	spaceAfterPattern = regexp.MustCompile(`([` + fullWidthPunctuation + `])[ \t]+(\S)`)
+	cjkPattern        = regexp.MustCompile(`[` + cjkClass + `]`)
+)
+
+// Format formats the body of the post data, the front matter is kept as it is.
+func Format(data []byte) ([]byte, error) {
+	doc, err := frontmatter.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// the body is the tail of data, the head is kept byte for byte
+	head := data[:len(data)-len(doc.Body)]
+	return append(append([]byte{}, head...), FormatBody(doc.Body)...), nil
+}
+
+// FormatBody formats the Markdown body, the fenced code blocks and the link reference definitions are kept.
+func FormatBody(body string) string {
+	lines := strings.Split(body, "\n")
+	code, _ := markdown.CodeBlocks(lines, 0)
+	for i, line := range lines {
+		if code[i] || referencePattern.MatchString(line) {
+			continue
+		}
+
+		text, cr := strings.CutSuffix(line, "\r")
+		lines[i] = formatLine(text)
+		if cr {
+			lines[i] += "\r"
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatLine formats a line of prose.
+func formatLine(line string) string {
+	segments := split(line)
+
+	var builder strings.Builder
+	for i, s := range segments {
+		if !s.protected {
+			s.text = formatText(s.text)
+			segments[i] = s
+		}
+		if i > 0 && needSpace(segments[i-1], s) {
+			builder.WriteString(" ")
+		}
+		builder.WriteString(s.text)
+	}
+	return builder.String()
+}
+
+// split splits line into the prose and the protected runs: the inline code, the URLs, the link targets, the HTML
+// tags, the heading ids and the Hugo shortcodes.
+func split(line string) []segment {
+	var res []segment
+	text := func(s string) {
+		from := 0
+		for _, m := range protectedPattern.FindAllStringIndex(s, -1) {
+			if m[0] > from {
+				res = append(res, segment{text: s[from:m[0]]})
+			}
+			p := s[m[0]:m[1]]
+			res = append(res, segment{text: p, protected: true, latin: urlPattern.MatchString(p)})
+			from = m[1]
+		}
+		if from < len(s) {
+			res = append(res, segment{text: s[from:]})
+		}
+	}
+
+	from := 0
+	for _, span := range markdown.InlineCode(line) {
+		text(line[from:span[0]])
+		res = append(res, segment{text: line[span[0]:span[1]], protected: true, latin: true})
+		from = span[1]
+	}
+	text(line[from:])
+
+	return res
+}
+
+// formatText formats a run of prose.
+func formatText(s string) string {
+	s = strings.Map(toHalfWidth, s)
+	s = ellipsisPattern.ReplaceAllString(s, "${1}……")
+	s = quotePattern.ReplaceAllStringFunc(s, func(m string) string {
+		if !cjkPattern.MatchString(m) {
+			return m
+		}
+		return "“" + m[1:len(m)-1] + "”"
+	})
+	s = replaceMatches(s, punctuationPattern, func(s string, m []int) string {
+		mark := s[m[4]:m[5]]
+		// '![' starts an image
+		if mark == "!" && m[1] < len(s) && s[m[1]] == '[' {
+			return s[m[0]:m[1]]
+		}
+		return s[m[2]:m[3]] + halfToFull[mark]
+	})
+	s = periodPattern.ReplaceAllString(s, "${1}。")
+	s = parenPattern.ReplaceAllStringFunc(s, func(m string) string {
+		if !cjkPattern.MatchString(m) {
+			return m
+		}
+		return "（" + strings.TrimSpace(m[1:len(m)-1]) + "）"
+	})
+	// the punctuation between CJK, or after a Latin word written into CJK, is full-width. English text before CJK
+	// keeps its ASCII punctuation.
+	s = replaceMatches(s, punctuationBeforePattern, func(s string, m []int) string {
+		return s[m[2]:m[3]] + halfToFull[s[m[4]:m[5]]] + s[m[6]:m[7]]
+	})
+	s = spaceBeforePattern.ReplaceAllString(s, "$1$2")
+	s = spaceAfterPattern.ReplaceAllString(s, "$1$2")
+
+	var builder strings.Builder
+	var prev rune
+	for _, r := range s {
+		if (isCJK(prev) && isLatin(r)) || (isLatin(prev) && isCJK(r)) {
+			builder.WriteRune(' ')
+		}
+		builder.WriteRune(r)
+		prev = r
+	}
+	return builder.String()
+}
+
+// replaceMatches replaces the matches of pattern in s with the results of fn, which gets s and the submatch
+// indexes of the match.
+func replaceMatches(s string, pattern *regexp.Regexp, fn func(s string, m []int) string) string {
+	var builder strings.Builder
+	from := 0
+	for _, m := range pattern.FindAllStringSubmatchIndex(s, -1) {
+		builder.WriteString(s[from:m[0]])
+		builder.WriteString(fn(s, m))
+		from = m[1]
+	}
+	builder.WriteString(s[from:])
+	return builder.String()
+}
+
+// needSpace reports whether a space is put between the segments, a CJK run and a Latin one.
+func needSpace(left, right segment) bool {
+	if (left.protected && !left.latin) || (right.protected && !right.latin) {
+		return false
+	}
+
+	l, _ := utf8.DecodeLastRuneInString(left.text)
+	r, _ := utf8.DecodeRuneInString(right.text)
+	return (isCJK(l) && (right.latin || isLatin(r))) || ((left.latin || isLatin(l)) && isCJK(r))
+}
+
+// toHalfWidth maps the full-width ASCII letters and digits to ASCII.
+func toHalfWidth(r rune) rune {
+	if (r >= '０' && r <= '９') || (r >= 'Ａ' && r <= 'Ｚ') || (r >= 'ａ' && r <= 'ｚ') {
+		return r - 0xFEE0
+	}
+	return r
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Bopomofo)
+}
+
+func isLatin(r rune) bool {
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,66 @@
+package typography
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatText(t *testing.T) {
+	for in, want := range map[string]string{
+		"用Go写CLI,很简单...":               "用 Go 写 CLI，很简单……",
+		"版本2.0发布了. 下一个版本:3.0":          "版本 2.0 发布了。下一个版本：3.0",
+		"他说\"你好\" ,然后走了!":              "他说“你好”，然后走了！",
+		"支持ＡＩ和１２３(中文注释)":               "支持 AI 和 123（中文注释）",
+		"Hello, world. (no change)...": "Hello, world. (no change)...",
+		"中文 ， 中文 。  ":                  "中文，中文。  ",
+		"- “引用” 和 1. （一）":              "- “引用”和 1. （一）",
+		"看图![图](a.png)。":               "看图![图](a.png)。",
+		"等等…":                          "等等……",
+		"Use foo, 中文 and bar: 说明":      "Use foo, 中文 and bar: 说明",
+		"参见(中文),然后":                    "参见（中文），然后",
+	} {
+		if got := formatLine(in); got != want {
+			t.Fatalf("Unexpected format of %q:\n%q\nwant:\n%q", in, got, want)
+		}
+	}
+}
+
+func TestFormatKeepsProtected(t *testing.T) {
+	for in, want := range map[string]string{
+		"使用`go build`命令":                       "使用 `go build` 命令",
+		"见https://example.com/a?b=c,d文档":       "见 https://example.com/a?b=c,d 文档",
+		"[文档](./中文.md)和<a href=\"x,y\">链接</a>": "[文档](./中文.md)和<a href=\"x,y\">链接</a>",
+		"{{< figure alt=\"中文,Go\" >}}说明":       "{{< figure alt=\"中文,Go\" >}}说明",
+		"## 标题Go {#go-title}":                  "## 标题 Go {#go-title}",
+		"``a`b``中文":                            "``a`b`` 中文",
+	} {
+		if got := formatLine(in); got != want {
+			t.Fatalf("Unexpected format of %q:\n%q\nwant:\n%q", in, got, want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	in := "+++\ntitle = '中文Go,标题'\n+++\n中文Go\r\n```go\nfmt.Println(\"中文Go,\")\n```\n[ref]: ./a,中文.md\n    中文Go\n"
+	want := "+++\ntitle = '中文Go,标题'\n+++\n中文 Go\r\n```go\nfmt.Println(\"中文Go,\")\n```\n[ref]: ./a,中文.md\n    中文 Go\n"
+
+	got, err := Format([]byte(in))
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("Unexpected format:\n%s\nwant:\n%s", got, want)
+	}
+
+	again, _ := Format(got)
+	if string(again) != string(got) {
+		t.Fatalf("Expected the format idempotent:\n%s", again)
+	}
+	if strings.Count(string(got), "\n") != strings.Count(in, "\n") {
+		t.Fatal("Expected the lines kept")
+	}
+
+	if _, err = Format([]byte("+++\ntitle = 'a'\n")); err == nil {
+		t.Fatal("Expected an unclosed front matter to fail")
+	}
+}
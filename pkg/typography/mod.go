@@ -0,0 +1,11 @@
+// Package typography formats the Chinese and Japanese text mixed with Latin text in the body of posts.
+//
+// The formatter puts a space between the CJK and the Latin or digit runs, uses the full-width punctuation after CJK
+// text, turns the full-width letters and digits into ASCII, and fixes the ellipses and the quotes around CJK text:
+//
+//	用Go写CLI,很简单...   ->   用 Go 写 CLI，很简单……
+//
+// Only the prose is changed: the front matter, the fenced code blocks, the inline code, the URLs, the link targets,
+// the HTML tags and the Hugo shortcodes are kept byte for byte. Lines are never added or removed, so a formatted
+// post diffs line by line against the original.
+package typography
@@ -0,0 +1,28 @@
+package typography
+
+// cjkClass is the regexp class of the CJK characters, put in brackets to use it.
+const cjkClass = `\p{Han}\p{Hiragana}\p{Katakana}\p{Bopomofo}`
+
+// fullWidthPunctuation are the punctuation marks no space is kept around in CJK text.
+const fullWidthPunctuation = "，。、；：！？（）《》「」『』【】“”‘’…"
+
+// closingPunctuation are the full-width marks closing CJK text, the punctuation after them is full-width.
+const closingPunctuation = "”’）》」』】"
+
+// halfToFull maps the half-width punctuation marks to the full-width ones used after CJK text.
+var halfToFull = map[string]string{
+	",": "，",
+	";": "；",
+	":": "：",
+	"!": "！",
+	"?": "？",
+	".": "。",
+}
+
+// segment is a run of a line, the protected runs are kept as they are.
+type segment struct {
+	text      string
+	protected bool
+	// latin marks the protected runs spaced from CJK text like Latin words: the inline code and the URLs.
+	latin bool
+}
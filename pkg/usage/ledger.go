@@ -0,0 +1,167 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.io/uberate/hcli/pkg/llms"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cost returns the cost of u, zero if no price matches its model.
+func (c Config) Cost(u llms.Usage) float64 {
+	for _, p := range c.Prices {
+		if p.Model != u.Model || (p.Provider != "" && p.Provider != u.Provider) {
+			continue
+		}
+		return float64(u.PromptTokens)*p.PromptPerMillion/1e6 +
+			float64(u.CompletionTokens)*p.CompletionPerMillion/1e6 +
+			float64(u.Images)*p.PerImage
+	}
+
+	return 0
+}
+
+// PriceRecords sets the cost of the records.
+func (c Config) PriceRecords(records []Record) {
+	for i := range records {
+		records[i].Cost = c.Cost(records[i].Usage)
+	}
+}
+
+// Append appends the records to the ledger at path, one JSON object per line.
+func Append(path string, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var builder strings.Builder
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		builder.Write(line)
+		builder.WriteByte('\n')
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// one write call, so concurrent runs don't interleave their lines
+	_, err = f.WriteString(builder.String())
+	return err
+}
+
+// ReadLedger reads the records of the ledger at path since the time since.
+func ReadLedger(path string, since time.Time) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var res []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var r Record
+		if err = json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("read ledger %s line %d: %w", path, line, err)
+		}
+		if !r.Time.Before(since) {
+			res = append(res, r)
+		}
+	}
+
+	return res, scanner.Err()
+}
+
+// ParseSince parses a relative duration like '30d', '12h' or '90m', or a date like '2006-01-02', into the start
+// time counted back from now.
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("invalid since '%s', use like 30d, 12h or 2006-01-02", s)
+		}
+		return now.AddDate(0, 0, -n), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid since '%s', use like 30d, 12h or 2006-01-02", s)
+	}
+	return now.Add(-d), nil
+}
+
+// Sum adds the records up.
+func Sum(records []Record) Total {
+	var t Total
+	for _, r := range records {
+		t.add(r)
+	}
+	return t
+}
+
+func (t *Total) add(r Record) {
+	t.Calls++
+	if r.Cached {
+		t.Cached++
+	}
+	t.PromptTokens += r.PromptTokens
+	t.CompletionTokens += r.CompletionTokens
+	t.Images += r.Images
+	t.Cost += r.Cost
+}
+
+// Report sums the records by month, provider and model, sorted in that order.
+func Report(records []Record) []Row {
+	index := map[string]int{}
+	var res []Row
+	for _, r := range records {
+		row := Row{Month: r.Time.Local().Format("2006-01"), Provider: r.Provider, Model: r.Model}
+		key := row.Month + "\x00" + row.Provider + "\x00" + row.Model
+
+		i, ok := index[key]
+		if !ok {
+			i = len(res)
+			index[key] = i
+			res = append(res, row)
+		}
+		res[i].add(r)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Month != res[j].Month {
+			return res[i].Month < res[j].Month
+		}
+		if res[i].Provider != res[j].Provider {
+			return res[i].Provider < res[j].Provider
+		}
+		return res[i].Model < res[j].Model
+	})
+
+	return res
+}
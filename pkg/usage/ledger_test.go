@@ -0,0 +1,93 @@
+package usage
+
+import (
+	"github.io/uberate/hcli/pkg/llms"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCost(t *testing.T) {
+	c := Config{Prices: []Price{
+		{Provider: "volc", Model: "text", PromptPerMillion: 2, CompletionPerMillion: 8},
+		{Model: "pic", PerImage: 0.2},
+	}}
+
+	records := []Record{
+		{Usage: llms.Usage{Provider: "volc", Model: "text", PromptTokens: 1000000, CompletionTokens: 500000}},
+		{Usage: llms.Usage{Provider: "other", Model: "pic", Images: 2}},
+		{Usage: llms.Usage{Provider: "other", Model: "text", PromptTokens: 100}},
+	}
+	c.PriceRecords(records)
+
+	for i, want := range []float64{6, 0.4, 0} {
+		if math.Abs(records[i].Cost-want) > 1e-9 {
+			t.Fatalf("Unexpected cost of record %d: %f, want %f", i, records[i].Cost, want)
+		}
+	}
+}
+
+func TestLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage", "ledger.jsonl")
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	records := []Record{
+		{Time: now.AddDate(0, -2, 0), Usage: llms.Usage{Provider: "volc", Model: "text", PromptTokens: 1}},
+		{Time: now.AddDate(0, -1, 0), Usage: llms.Usage{Provider: "volc", Model: "text", PromptTokens: 2}, Cost: 1},
+		{Time: now, Usage: llms.Usage{Provider: "volc", Model: "text", PromptTokens: 3}, Cost: 2},
+		{Time: now, Usage: llms.Usage{Provider: "volc", Model: "pic", Images: 1}},
+		{Time: now, Usage: llms.Usage{Provider: "volc", Model: "pic", Cached: true}},
+	}
+	if err := Append(path, records[:2]); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := Append(path, records[2:]); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	since, err := ParseSince("40d", now)
+	if err != nil {
+		t.Fatalf("ParseSince failed: %v", err)
+	}
+	read, err := ReadLedger(path, since)
+	if err != nil {
+		t.Fatalf("ReadLedger failed: %v", err)
+	}
+	if len(read) != 4 {
+		t.Fatalf("Expected 4 records since %v, got %d", since, len(read))
+	}
+
+	if total := Sum(read); total.Calls != 4 || total.Cached != 1 || total.Images != 1 {
+		t.Fatalf("Unexpected total: %+v", total)
+	}
+
+	rows := Report(read)
+	if len(rows) != 3 {
+		t.Fatalf("Unexpected report: %+v", rows)
+	}
+	if rows[0].Month != "2026-02" || rows[1].Model != "pic" || rows[2].PromptTokens != 3 || rows[2].Cost != 2 {
+		t.Fatalf("Unexpected report: %+v", rows)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	cases := map[string]time.Time{
+		"30d":        now.AddDate(0, 0, -30),
+		"12h":        now.Add(-12 * time.Hour),
+		"2026-01-02": time.Date(2026, 1, 2, 0, 0, 0, 0, time.Local),
+	}
+	for input, want := range cases {
+		if got, err := ParseSince(input, now); err != nil || !got.Equal(want) {
+			t.Fatalf("ParseSince(%s) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "xd", "-1d", "month"} {
+		if _, err := ParseSince(input, now); err == nil {
+			t.Fatalf("Expected ParseSince(%s) to fail", input)
+		}
+	}
+}
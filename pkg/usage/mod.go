@@ -0,0 +1,6 @@
+// Package usage meters the LLM calls of a command run and keeps a local ledger of them.
+//
+// Meter wraps llms.LLMTools and records the usage every call returns into the Recorder of the run. At the end of
+// the run the records are priced with the configured model prices, summarized and, if a ledger file is configured,
+// appended to it as JSON lines. Report sums the ledger by month, provider and model.
+package usage
@@ -0,0 +1,80 @@
+package usage
+
+import (
+	"context"
+	"github.io/uberate/hcli/pkg/llms"
+	"time"
+)
+
+type recorderKey struct{}
+
+// NewRecorder returns the recorder of a run of command.
+func NewRecorder(command string) *Recorder {
+	return &Recorder{command: command}
+}
+
+// WithRecorder attaches r to ctx.
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// RecorderFrom returns the recorder of ctx, nil if there is none.
+func RecorderFrom(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+// Add records the usage of a call, an attempt of another provider or model is a record of its own. A nil recorder
+// drops it.
+func (r *Recorder) Add(kind string, u llms.Usage) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, part := range u.Split() {
+		r.records = append(r.records, Record{Time: time.Now(), Command: r.command, Kind: kind, Usage: part})
+	}
+}
+
+// Records returns a copy of the records.
+func (r *Recorder) Records() []Record {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Record{}, r.records...)
+}
+
+// Meter wraps tools so the usage of every call is added to r, failed calls included.
+func Meter(tools llms.LLMTools, r *Recorder) llms.LLMTools {
+	return &meter{tools: tools, r: r}
+}
+
+type meter struct {
+	tools llms.LLMTools
+	r     *Recorder
+}
+
+func (m *meter) Text(ctx context.Context, sysPrompt, input string) (string, llms.Usage, error) {
+	res, u, err := m.tools.Text(ctx, sysPrompt, input)
+	m.r.Add(KindText, u)
+	return res, u, err
+}
+
+func (m *meter) TextStream(ctx context.Context, sysPrompt, input string,
+	onChunk func(chunk string)) (string, llms.Usage, error) {
+
+	res, u, err := llms.TextStream(ctx, m.tools, sysPrompt, input, onChunk)
+	m.r.Add(KindText, u)
+	return res, u, err
+}
+
+func (m *meter) Pic(ctx context.Context, input string) ([]byte, llms.Usage, error) {
+	res, u, err := m.tools.Pic(ctx, input)
+	m.r.Add(KindPic, u)
+	return res, u, err
+}
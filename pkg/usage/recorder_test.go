@@ -0,0 +1,82 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"github.io/uberate/hcli/pkg/llms"
+	"sync"
+	"testing"
+)
+
+type fakeLLM struct{}
+
+func (fakeLLM) Text(ctx context.Context, sysPrompt, input string) (string, llms.Usage, error) {
+	return "ok", llms.Usage{Provider: "fake", Model: "text", PromptTokens: 10, CompletionTokens: 5}, nil
+}
+
+func (fakeLLM) Pic(ctx context.Context, input string) ([]byte, llms.Usage, error) {
+	return nil, llms.Usage{Provider: "fake", Model: "pic", Images: 1}, errors.New("failed")
+}
+
+func TestMeter(t *testing.T) {
+	r := NewRecorder("gen pic")
+	ctx := WithRecorder(context.Background(), r)
+	if RecorderFrom(ctx) != r || RecorderFrom(context.Background()) != nil {
+		t.Fatal("Unexpected recorder of the context")
+	}
+
+	tools := Meter(fakeLLM{}, r)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _ = tools.Text(ctx, "", "")
+		}()
+	}
+	wg.Wait()
+	if _, _, err := tools.Pic(ctx, ""); err == nil {
+		t.Fatal("Expected the error to be passed through")
+	}
+
+	records := r.Records()
+	total := Sum(records)
+	if total.Calls != 5 || total.PromptTokens != 40 || total.CompletionTokens != 20 || total.Images != 1 {
+		t.Fatalf("Unexpected total: %+v", total)
+	}
+	if records[4].Kind != KindPic || records[0].Command != "gen pic" {
+		t.Fatalf("Unexpected record: %+v", records[4])
+	}
+
+	var nilRecorder *Recorder
+	nilRecorder.Add(KindText, llms.Usage{})
+	if Meter(fakeLLM{}, nil) == nil || nilRecorder.Records() != nil {
+		t.Fatal("Expected a nil recorder to drop the records")
+	}
+}
+
+func TestAddAttempts(t *testing.T) {
+	r := NewRecorder("gen post")
+	failed := llms.Usage{Provider: "volc", Model: "a", PromptTokens: 3}
+	r.Add(KindText, llms.Usage{Provider: "openai", Model: "b", PromptTokens: 7, Attempts: []llms.Usage{failed}})
+
+	records := r.Records()
+	if len(records) != 2 || records[0].Model != "a" || records[0].PromptTokens != 3 ||
+		records[1].Model != "b" || records[1].PromptTokens != 7 || records[1].Attempts != nil {
+		t.Fatalf("Expected a record per attempt: %+v", records)
+	}
+}
+
+func TestMeterStream(t *testing.T) {
+	r := NewRecorder("translate")
+	var chunks []string
+	res, _, err := llms.TextStream(context.Background(), Meter(fakeLLM{}, r), "", "", func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+	if err != nil || res != "ok" || len(chunks) != 1 {
+		t.Fatalf("Unexpected stream: %q %q %v", res, chunks, err)
+	}
+	if records := r.Records(); len(records) != 1 || records[0].Kind != KindText {
+		t.Fatalf("Expected the stream to be recorded: %+v", records)
+	}
+}
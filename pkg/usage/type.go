@@ -0,0 +1,64 @@
+package usage
+
+import (
+	"github.io/uberate/hcli/pkg/llms"
+	"sync"
+	"time"
+)
+
+const (
+	KindText = "text"
+	KindPic  = "pic"
+)
+
+// Config is the usage setting of the hcli config.
+type Config struct {
+	Ledger string  `yaml:"Ledger" describe:"The JSONL file the usage of every run is appended to, relative to the config\nfile. Empty means no ledger."`
+	Prices []Price `yaml:"Prices" describe:"The model prices used to compute the cost of the calls."`
+}
+
+// Price is the price of a model, in any currency as long as all the prices use the same one.
+type Price struct {
+	Provider             string  `yaml:"Provider" describe:"The provider of the model, empty matches all the providers."`
+	Model                string  `yaml:"Model" describe:"The model id."`
+	PromptPerMillion     float64 `yaml:"PromptPerMillion" describe:"The price of 1M prompt tokens."`
+	CompletionPerMillion float64 `yaml:"CompletionPerMillion" describe:"The price of 1M completion tokens."`
+	PerImage             float64 `yaml:"PerImage" describe:"The price of one image."`
+}
+
+// Record is the usage of one LLM call, a line of the ledger.
+type Record struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Kind    string    `json:"kind"`
+	llms.Usage
+	Cost float64 `json:"cost"`
+}
+
+// Recorder collects the records of a command run, it is safe for concurrent use.
+type Recorder struct {
+	command string
+
+	mu      sync.Mutex
+	records []Record
+}
+
+// Total is the sum of records.
+type Total struct {
+	Calls int
+	// Cached counts the calls answered from the LLM cache.
+	Cached           int
+	PromptTokens     int
+	CompletionTokens int
+	Images           int
+	Cost             float64
+}
+
+// Row is a total of the report.
+type Row struct {
+	// Month is formatted as 2006-01.
+	Month    string
+	Provider string
+	Model    string
+	Total
+}